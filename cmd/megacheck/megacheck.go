@@ -13,9 +13,13 @@ import (
 func main() {
 	var flags struct {
 		staticcheck struct {
-			enabled     bool
-			generated   bool
-			exitNonZero bool
+			enabled               bool
+			generated             bool
+			exitNonZero           bool
+			paddingThreshold      int64
+			chanCapacityThreshold int64
+			dynamicConversions    bool
+			pointerSliceThreshold int64
 		}
 		gosimple struct {
 			enabled     bool
@@ -49,6 +53,14 @@ func main() {
 		"staticcheck.generated", false, "Check generated code (only applies to a subset of checks)")
 	fs.BoolVar(&flags.staticcheck.exitNonZero,
 		"staticcheck.exit-non-zero", true, "Exit non-zero if any problems were found")
+	fs.Int64Var(&flags.staticcheck.paddingThreshold,
+		"staticcheck.padding-threshold", 0, "Only report structs that can shrink by more than this many `bytes` (SA6005)")
+	fs.Int64Var(&flags.staticcheck.chanCapacityThreshold,
+		"staticcheck.chan-capacity-threshold", 100, "Only report buffered channels with a literal capacity larger than `n` (SA9010)")
+	fs.BoolVar(&flags.staticcheck.dynamicConversions,
+		"staticcheck.dynamic-conversions", false, "Also report narrowing conversions of values whose range isn't known, with a low confidence (SA5012)")
+	fs.Int64Var(&flags.staticcheck.pointerSliceThreshold,
+		"staticcheck.pointer-slice-threshold", 16, "Only report slices of pointers to structs of at most this many `bytes` (SA6008)")
 
 	fs.BoolVar(&flags.unused.enabled,
 		"unused.enabled", true, "Run unused")
@@ -76,6 +88,10 @@ func main() {
 	if flags.staticcheck.enabled {
 		sac := staticcheck.NewChecker()
 		sac.CheckGenerated = flags.staticcheck.generated
+		sac.PaddingThreshold = flags.staticcheck.paddingThreshold
+		sac.ChanCapacityThreshold = flags.staticcheck.chanCapacityThreshold
+		sac.DynamicConversions = flags.staticcheck.dynamicConversions
+		sac.PointerSliceThreshold = flags.staticcheck.pointerSliceThreshold
 		checkers = append(checkers, lintutil.CheckerConfig{
 			Checker:     sac,
 			ExitNonZero: flags.staticcheck.exitNonZero,
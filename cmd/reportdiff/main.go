@@ -0,0 +1,76 @@
+// reportdiff compares two reports in the json output format of the
+// linters and prints the problems that have been added.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"honnef.co/go/tools/reportdiff"
+	"honnef.co/go/tools/version"
+)
+
+var (
+	fFixed   string
+	fVersion bool
+)
+
+func init() {
+	flag.StringVar(&fFixed, "fixed", "", "Also write the problems that have been fixed to `file`")
+	flag.BoolVar(&fVersion, "version", false, "Print version and exit")
+	flag.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: %s [flags] old.json new.json\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "Flags:\n")
+		flag.PrintDefaults()
+	}
+}
+
+func read(name string) []reportdiff.Problem {
+	f, err := os.Open(name)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer f.Close()
+	ps, err := reportdiff.Read(f)
+	if err != nil {
+		log.Fatalf("%s: %s", name, err)
+	}
+	return ps
+}
+
+func main() {
+	log.SetFlags(0)
+	flag.Parse()
+
+	if fVersion {
+		version.Print()
+		os.Exit(0)
+	}
+
+	if flag.NArg() != 2 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	added, fixed := reportdiff.Diff(read(flag.Arg(0)), read(flag.Arg(1)))
+	if err := reportdiff.Write(os.Stdout, added); err != nil {
+		log.Fatal(err)
+	}
+	if fFixed != "" {
+		f, err := os.Create(fFixed)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if err := reportdiff.Write(f, fixed); err != nil {
+			log.Fatal(err)
+		}
+		if err := f.Close(); err != nil {
+			log.Fatal(err)
+		}
+	}
+	if len(added) > 0 {
+		os.Exit(1)
+	}
+}
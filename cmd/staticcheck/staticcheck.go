@@ -12,9 +12,17 @@ import (
 func main() {
 	fs := lintutil.FlagSet("staticcheck")
 	gen := fs.Bool("generated", false, "Check generated code")
+	padding := fs.Int64("padding-threshold", 0, "Only report structs that can shrink by more than this many `bytes` (SA6005)")
+	chanCapacity := fs.Int64("chan-capacity-threshold", 100, "Only report buffered channels with a literal capacity larger than `n` (SA9010)")
+	dynamicConversions := fs.Bool("dynamic-conversions", false, "Also report narrowing conversions of values whose range isn't known, with a low confidence (SA5012)")
+	pointerSlice := fs.Int64("pointer-slice-threshold", 16, "Only report slices of pointers to structs of at most this many `bytes` (SA6008)")
 	fs.Parse(os.Args[1:])
 	c := staticcheck.NewChecker()
 	c.CheckGenerated = *gen
+	c.PaddingThreshold = *padding
+	c.ChanCapacityThreshold = *chanCapacity
+	c.DynamicConversions = *dynamicConversions
+	c.PointerSliceThreshold = *pointerSlice
 	cfg := lintutil.CheckerConfig{
 		Checker:     c,
 		ExitNonZero: true,
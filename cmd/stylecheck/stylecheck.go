@@ -9,9 +9,17 @@ import (
 func main() {
 	fs := lintutil.FlagSet("stylecheck")
 	gen := fs.Bool("generated", false, "Check generated code")
+	floatZero := fs.Bool("float-zero", false, "Also flag comparisons of floating-point values with 0 in ST1013")
+	panicInInit := fs.Bool("panic-in-init", false, "Also flag panics in init functions in ST1014")
+	unwrappedStatements := fs.Int("unwrapped-error-statements", 10, "Flag returning errors without context in ST1017 only after this many statements in a function")
+	nakedReturnLines := fs.Int("naked-return-lines", 30, "Flag naked returns in ST1021 only in functions longer than this many lines")
 	fs.Parse(os.Args[1:])
 	c := stylecheck.NewChecker()
 	c.CheckGenerated = *gen
+	c.FloatZero = *floatZero
+	c.PanicInInit = *panicInInit
+	c.UnwrappedErrorStatements = *unwrappedStatements
+	c.NakedReturnLines = *nakedReturnLines
 	cfg := lintutil.CheckerConfig{
 		Checker:     c,
 		ExitNonZero: true,
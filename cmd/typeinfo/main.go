@@ -0,0 +1,81 @@
+// typeinfo emits the type information of packages as JSON.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"go/build"
+	"log"
+	"os"
+
+	"honnef.co/go/tools/typeinfo"
+	"honnef.co/go/tools/version"
+
+	"github.com/kisielk/gotool"
+	"golang.org/x/tools/go/loader"
+)
+
+var (
+	fVersion bool
+)
+
+func init() {
+	flag.BoolVar(&fVersion, "version", false, "Print version and exit")
+}
+
+func main() {
+	log.SetFlags(0)
+	flag.Parse()
+
+	if fVersion {
+		version.Print()
+		os.Exit(0)
+	}
+
+	if len(flag.Args()) == 0 {
+		flag.Usage()
+		os.Exit(1)
+	}
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		log.Fatal(err)
+	}
+	// Resolve patterns and relative paths, such as ./..., to import
+	// paths, which the loaded packages are identified by.
+	var paths []string
+	seen := map[string]bool{}
+	for _, arg := range gotool.ImportPaths(flag.Args()) {
+		bp, err := build.Default.Import(arg, cwd, build.FindOnly)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if !seen[bp.ImportPath] {
+			seen[bp.ImportPath] = true
+			paths = append(paths, bp.ImportPath)
+		}
+	}
+
+	conf := loader.Config{
+		Build: &build.Default,
+		Cwd:   cwd,
+	}
+	for _, path := range paths {
+		conf.Import(path)
+	}
+	lprog, err := conf.Load()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	enc := json.NewEncoder(os.Stdout)
+	for _, path := range paths {
+		info := lprog.Package(path)
+		if info == nil {
+			log.Fatalf("package %s wasn't loaded", path)
+		}
+		if err := enc.Encode(typeinfo.Export(lprog.Fset, info.Pkg, &info.Info, info.Files)); err != nil {
+			log.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,514 @@
+// Package config implements the loading of staticcheck.conf files,
+// which configure the linters for all packages in a directory tree.
+//
+// A configuration file consists of lines of key = value pairs,
+// grouped into sections that are started by a section name in
+// brackets. Empty lines and lines starting with # are ignored. The
+// following sections are supported:
+//
+//	[severity]
+//	# Maps check names to the severity that their problems
+//	# should be reported with, one of error, warning and info.
+//	# Notes about checks that were skipped, for example because
+//	# of a timeout, belong to the pseudo check "skipped".
+//	SA1000 = warning
+//	ST1005 = error
+//	skipped = info
+//
+//	[checks]
+//	# Comma-separated list of checks to run, replacing the
+//	# default selection, including opt-in checks; see
+//	# lint.Linter.Checks. Tokens are applied in order: "all"
+//	# selects all checks, tokens starting with ^ are regular
+//	# expressions, and a leading dash deselects. The -checks
+//	# flag replaces this list instead of adding to it.
+//	select = all, -ST1000, -^SA9
+//	# Report at most this many problems per check, followed by a
+//	# note on how many more there are. 0 means no limit. The
+//	# -max-per-check flag takes precedence.
+//	max-per-check = 50
+//	# Don't report problems with a lower confidence, between 0
+//	# and 1; see lint.Linter.MinConfidence. The -min-confidence
+//	# flag takes precedence.
+//	min-confidence = 0.8
+//
+//	[tests]
+//	# Comma-separated lists of checks to additionally run for,
+//	# and to not report problems of in, _test.go files. Check
+//	# names support globbing.
+//	enable = SA9*
+//	disable = ST1003
+//
+//	[generated]
+//	# Whether to discard problems in generated files, which are
+//	# still analyzed, and a regular expression that identifies
+//	# generated files by matching a line of a comment before the
+//	# package clause, in addition to lint.DefaultGeneratedPattern.
+//	exclude = true
+//	pattern = ^// Automatically generated by
+//
+//	[names]
+//	# Comma-separated lists of identifier names that checks
+//	# shouldn't report problems about, such as names kept for
+//	# compatibility. Names support globbing.
+//	ST1003 = Id, *Url
+//
+//	[rules]
+//	# Custom checks, written in the pattern language of package
+//	# rules, named like other checks with the prefix R.
+//	R1000 = report CallExpr where Fun == "fmt.Println" and Args[0] is StringLit and Args[0] matches "\\n$": "Println already adds a newline"
+//
+// The same configuration can also be written as TOML, in a file
+// named staticcheck.toml:
+//
+//	[severity]
+//	SA1000 = "warning"
+//	ST1005 = "error"
+//
+// or as JSON, in a file named staticcheck.json:
+//
+//	{"severity": {"SA1000": "warning", "ST1005": "error"}}
+//
+// Problems in code that can't be annotated with linter directives,
+// such as generated code, can be ignored with ignore files, which are
+// described in the documentation of IgnoreFileName.
+package config // import "honnef.co/go/tools/config"
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"honnef.co/go/tools/lint"
+	"honnef.co/go/tools/rules"
+)
+
+// FileName is the name of configuration files.
+const FileName = "staticcheck.conf"
+
+// FileNames lists the names of configuration files in all supported
+// formats. A directory may contain at most one of them.
+var FileNames = []string{FileName, "staticcheck.toml", "staticcheck.json"}
+
+// Config describes the configuration of the linters.
+type Config struct {
+	// Severity overrides the default severity of individual checks.
+	Severity map[string]lint.Severity
+	// Checks, if not nil, selects the checks to run; see
+	// lint.Linter.Checks.
+	Checks []string
+	// MaxPerCheck, if not nil, limits the number of problems that
+	// are reported for each check; see lint.Linter.MaxPerCheck.
+	MaxPerCheck *int
+	// MinConfidence, if not nil, is the confidence below which
+	// problems aren't reported; see lint.Linter.MinConfidence.
+	MinConfidence *float64
+	// TestEnabled lists opt-in checks that are only run for tests.
+	TestEnabled []string
+	// TestDisabled lists checks that aren't reported in tests.
+	TestDisabled []string
+	// Ignores are the ignores loaded from ignore files.
+	Ignores []*lint.RangeIgnore
+	// ExcludeGenerated, if not nil, controls whether problems in
+	// generated files are discarded.
+	ExcludeGenerated *bool
+	// GeneratedPattern identifies generated files in addition to
+	// lint.DefaultGeneratedPattern.
+	GeneratedPattern *regexp.Regexp
+	// AllowedNames maps checks to the identifier names that they
+	// shouldn't report problems about; see lint.Linter.AllowedNames.
+	AllowedNames map[string][]string
+	// Rules maps the names of custom checks to their rules, in the
+	// pattern language of package rules.
+	Rules map[string]string
+}
+
+// Merge returns the result of applying o on top of c. Settings in o
+// take precedence over those in c.
+func (c Config) Merge(o Config) Config {
+	out := Config{}
+	if len(c.Severity) > 0 || len(o.Severity) > 0 {
+		out.Severity = map[string]lint.Severity{}
+		for k, v := range c.Severity {
+			out.Severity[k] = v
+		}
+		for k, v := range o.Severity {
+			out.Severity[k] = v
+		}
+	}
+	out.Checks = c.Checks
+	if o.Checks != nil {
+		out.Checks = o.Checks
+	}
+	out.MaxPerCheck = c.MaxPerCheck
+	if o.MaxPerCheck != nil {
+		out.MaxPerCheck = o.MaxPerCheck
+	}
+	out.MinConfidence = c.MinConfidence
+	if o.MinConfidence != nil {
+		out.MinConfidence = o.MinConfidence
+	}
+	out.TestEnabled = c.TestEnabled
+	if o.TestEnabled != nil {
+		out.TestEnabled = o.TestEnabled
+	}
+	out.TestDisabled = c.TestDisabled
+	if o.TestDisabled != nil {
+		out.TestDisabled = o.TestDisabled
+	}
+	out.ExcludeGenerated = c.ExcludeGenerated
+	if o.ExcludeGenerated != nil {
+		out.ExcludeGenerated = o.ExcludeGenerated
+	}
+	out.GeneratedPattern = c.GeneratedPattern
+	if o.GeneratedPattern != nil {
+		out.GeneratedPattern = o.GeneratedPattern
+	}
+	if len(c.AllowedNames) > 0 || len(o.AllowedNames) > 0 {
+		out.AllowedNames = map[string][]string{}
+		for k, v := range c.AllowedNames {
+			out.AllowedNames[k] = v
+		}
+		for k, v := range o.AllowedNames {
+			out.AllowedNames[k] = v
+		}
+	}
+	if len(c.Rules) > 0 || len(o.Rules) > 0 {
+		out.Rules = map[string]string{}
+		for k, v := range c.Rules {
+			out.Rules[k] = v
+		}
+		for k, v := range o.Rules {
+			out.Rules[k] = v
+		}
+	}
+	// Ignores accumulate instead of overriding each other
+	out.Ignores = append(append(out.Ignores, c.Ignores...), o.Ignores...)
+	return out
+}
+
+// set applies a single setting to cfg. All formats are mapped onto
+// sections, keys and values.
+func (cfg *Config) set(section, key, value string) error {
+	switch section {
+	case "severity":
+		sev, err := lint.ParseSeverity(value)
+		if err != nil {
+			return err
+		}
+		if cfg.Severity == nil {
+			cfg.Severity = map[string]lint.Severity{}
+		}
+		cfg.Severity[key] = sev
+		return nil
+	case "checks":
+		switch key {
+		case "select":
+			checks := splitChecks(value)
+			if err := lint.ValidateChecks(checks); err != nil {
+				return err
+			}
+			cfg.Checks = checks
+		case "max-per-check":
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 0 {
+				return fmt.Errorf("invalid number of problems %q", value)
+			}
+			cfg.MaxPerCheck = &n
+		case "min-confidence":
+			f, err := strconv.ParseFloat(value, 64)
+			if err != nil || f < 0 || f > 1 {
+				return fmt.Errorf("invalid confidence %q, confidences are between 0 and 1", value)
+			}
+			cfg.MinConfidence = &f
+		default:
+			return fmt.Errorf("unknown key %q", key)
+		}
+		return nil
+	case "tests":
+		checks := splitChecks(value)
+		switch key {
+		case "enable":
+			cfg.TestEnabled = checks
+		case "disable":
+			cfg.TestDisabled = checks
+		default:
+			return fmt.Errorf("unknown key %q", key)
+		}
+		return nil
+	case "generated":
+		switch key {
+		case "exclude":
+			b, err := strconv.ParseBool(value)
+			if err != nil {
+				return fmt.Errorf("invalid boolean %q", value)
+			}
+			cfg.ExcludeGenerated = &b
+		case "pattern":
+			rx, err := regexp.Compile(value)
+			if err != nil {
+				return err
+			}
+			cfg.GeneratedPattern = rx
+		default:
+			return fmt.Errorf("unknown key %q", key)
+		}
+		return nil
+	case "names":
+		if cfg.AllowedNames == nil {
+			cfg.AllowedNames = map[string][]string{}
+		}
+		cfg.AllowedNames[key] = splitChecks(value)
+		return nil
+	case "rules":
+		if !rules.ValidName(key) {
+			return fmt.Errorf("invalid rule name %q, rule names look like R1000", key)
+		}
+		if _, err := rules.Parse(value); err != nil {
+			return err
+		}
+		if cfg.Rules == nil {
+			cfg.Rules = map[string]string{}
+		}
+		cfg.Rules[key] = value
+		return nil
+	default:
+		return fmt.Errorf("unknown key %q", key)
+	}
+}
+
+// splitChecks splits a comma-separated list of checks. The result
+// is never nil, so that an empty list can override another one.
+func splitChecks(value string) []string {
+	checks := []string{}
+	for _, check := range strings.Split(value, ",") {
+		if check = strings.TrimSpace(check); check != "" {
+			checks = append(checks, check)
+		}
+	}
+	return checks
+}
+
+func knownSection(section string) bool {
+	switch section {
+	case "severity", "checks", "tests", "generated", "names", "rules":
+		return true
+	default:
+		return false
+	}
+}
+
+// Parse parses a configuration. The name is only used in error
+// messages.
+func Parse(name string, r io.Reader) (Config, error) {
+	return parseLines(name, r, false)
+}
+
+// ParseTOML parses a configuration in the TOML format. Only the
+// subset of TOML needed to express configurations is supported:
+// tables, and keys with string values. The name is only used in
+// error messages.
+func ParseTOML(name string, r io.Reader) (Config, error) {
+	return parseLines(name, r, true)
+}
+
+// ParseJSON parses a configuration in the JSON format, an object
+// mapping section names to objects of keys and string values. The
+// name is only used in error messages.
+func ParseJSON(name string, r io.Reader) (Config, error) {
+	var sections map[string]map[string]string
+	if err := json.NewDecoder(r).Decode(&sections); err != nil {
+		return Config{}, fmt.Errorf("%s: %s", name, err)
+	}
+	var names []string
+	for section := range sections {
+		names = append(names, section)
+	}
+	sort.Strings(names)
+	var cfg Config
+	for _, section := range names {
+		if !knownSection(section) {
+			return Config{}, fmt.Errorf("%s: unknown section %q", name, section)
+		}
+		var keys []string
+		for key := range sections[section] {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			if err := cfg.set(section, key, sections[section][key]); err != nil {
+				return Config{}, fmt.Errorf("%s: %s.%s: %s", name, section, key, err)
+			}
+		}
+	}
+	return cfg, nil
+}
+
+// unquoteTOML parses a TOML basic or literal string, followed by an
+// optional comment.
+func unquoteTOML(s string) (string, error) {
+	if s == "" {
+		return "", fmt.Errorf("missing value")
+	}
+	var value, rest string
+	switch s[0] {
+	case '"':
+		end := 1
+		for ; end < len(s) && s[end] != '"'; end++ {
+			if s[end] == '\\' {
+				end++
+			}
+		}
+		if end >= len(s) {
+			return "", fmt.Errorf("unterminated string")
+		}
+		var err error
+		value, err = strconv.Unquote(s[:end+1])
+		if err != nil {
+			return "", fmt.Errorf("malformed string %s", s[:end+1])
+		}
+		rest = s[end+1:]
+	case '\'':
+		end := strings.IndexByte(s[1:], '\'')
+		if end == -1 {
+			return "", fmt.Errorf("unterminated string")
+		}
+		value, rest = s[1:end+1], s[end+2:]
+	default:
+		return "", fmt.Errorf("unsupported value %s, only strings are supported", s)
+	}
+	rest = strings.TrimSpace(rest)
+	if rest != "" && !strings.HasPrefix(rest, "#") {
+		return "", fmt.Errorf("unexpected %q after value", rest)
+	}
+	return value, nil
+}
+
+// parseLines parses the line-based formats, that is our own and
+// TOML, which differ in how keys and values are written.
+func parseLines(name string, r io.Reader, toml bool) (Config, error) {
+	var cfg Config
+	section := ""
+	scanner := bufio.NewScanner(r)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if strings.HasPrefix(line, "[") {
+			if toml {
+				if i := strings.LastIndex(line, "#"); i != -1 && strings.LastIndex(line, "]") < i {
+					line = strings.TrimSpace(line[:i])
+				}
+			}
+			if !strings.HasSuffix(line, "]") {
+				return Config{}, fmt.Errorf("%s:%d: malformed section header", name, n)
+			}
+			section = strings.TrimSpace(line[1 : len(line)-1])
+			if !knownSection(section) {
+				return Config{}, fmt.Errorf("%s:%d: unknown section %q", name, n, section)
+			}
+			continue
+		}
+		idx := strings.Index(line, "=")
+		if idx == -1 {
+			return Config{}, fmt.Errorf("%s:%d: expected key = value", name, n)
+		}
+		key := strings.TrimSpace(line[:idx])
+		value := strings.TrimSpace(line[idx+1:])
+		if key == "" {
+			return Config{}, fmt.Errorf("%s:%d: missing key", name, n)
+		}
+		if toml {
+			var err error
+			if strings.HasPrefix(key, `"`) || strings.HasPrefix(key, "'") {
+				key, err = unquoteTOML(key)
+				if err != nil {
+					return Config{}, fmt.Errorf("%s:%d: malformed key: %s", name, n, err)
+				}
+			}
+			value, err = unquoteTOML(value)
+			if err != nil {
+				return Config{}, fmt.Errorf("%s:%d: %s", name, n, err)
+			}
+		}
+		if err := cfg.set(section, key, value); err != nil {
+			return Config{}, fmt.Errorf("%s:%d: %s", name, n, err)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return Config{}, fmt.Errorf("%s: %s", name, err)
+	}
+	return cfg, nil
+}
+
+// ParseFile parses the named configuration file. Files with the
+// extensions .toml and .json are parsed as TOML and JSON
+// respectively, all other files are expected to be in our own format.
+func ParseFile(name string) (Config, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return Config{}, err
+	}
+	defer f.Close()
+	switch filepath.Ext(name) {
+	case ".toml":
+		return ParseTOML(name, f)
+	case ".json":
+		return ParseJSON(name, f)
+	default:
+		return Parse(name, f)
+	}
+}
+
+// Load returns the merged configuration of all configuration files
+// in dir and its parent directories. Files in deeper directories
+// take precedence. The ignores of all ignore files in these
+// directories are combined.
+func Load(dir string) (Config, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return Config{}, err
+	}
+	var dirs []string
+	for {
+		dirs = append(dirs, dir)
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			break
+		}
+		dir = parent
+	}
+	var cfg Config
+	for i := len(dirs) - 1; i >= 0; i-- {
+		var found string
+		for _, fname := range FileNames {
+			name := filepath.Join(dirs[i], fname)
+			c, err := ParseFile(name)
+			if os.IsNotExist(err) {
+				continue
+			}
+			if err != nil {
+				return Config{}, err
+			}
+			if found != "" {
+				return Config{}, fmt.Errorf("found both %s and %s, only one configuration file per directory is allowed", found, name)
+			}
+			found = name
+			cfg = cfg.Merge(c)
+		}
+		ignores, err := ParseIgnoreFile(filepath.Join(dirs[i], IgnoreFileName))
+		if err != nil && !os.IsNotExist(err) {
+			return Config{}, err
+		}
+		cfg = cfg.Merge(Config{Ignores: ignores})
+	}
+	return cfg, nil
+}
@@ -0,0 +1,425 @@
+package config
+
+import (
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"honnef.co/go/tools/lint"
+)
+
+func TestParse(t *testing.T) {
+	src := `
+# comment
+[severity]
+SA1000 = warning
+ST1005=error
+	SA4006 = info
+`
+	cfg, err := Parse("test.conf", strings.NewReader(src))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]lint.Severity{
+		"SA1000": lint.SeverityWarning,
+		"ST1005": lint.SeverityError,
+		"SA4006": lint.SeverityInfo,
+	}
+	if len(cfg.Severity) != len(want) {
+		t.Fatalf("got %v, want %v", cfg.Severity, want)
+	}
+	for k, v := range want {
+		if cfg.Severity[k] != v {
+			t.Errorf("severity of %s is %s, want %s", k, cfg.Severity[k], v)
+		}
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		src string
+		err string
+	}{
+		{"[severity]\nSA1000 = fatal", "test.conf:2: unknown severity \"fatal\""},
+		{"[severity\n", "test.conf:1: malformed section header"},
+		{"[foo]\n", "test.conf:1: unknown section \"foo\""},
+		{"SA1000 = warning", "test.conf:1: unknown key \"SA1000\""},
+		{"[severity]\nSA1000", "test.conf:2: expected key = value"},
+	}
+	for _, tt := range tests {
+		_, err := Parse("test.conf", strings.NewReader(tt.src))
+		if err == nil || err.Error() != tt.err {
+			t.Errorf("Parse(%q) returned error %v, want %q", tt.src, err, tt.err)
+		}
+	}
+}
+
+func TestLoad(t *testing.T) {
+	root, err := ioutil.TempDir("", "config")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(root)
+	sub := filepath.Join(root, "a", "b")
+	if err := os.MkdirAll(sub, 0755); err != nil {
+		t.Fatal(err)
+	}
+	write := func(dir, src string) {
+		if err := ioutil.WriteFile(filepath.Join(dir, FileName), []byte(src), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write(root, "[severity]\nSA1000 = warning\nSA1001 = warning\n")
+	write(sub, "[severity]\nSA1001 = info\n")
+
+	cfg, err := Load(sub)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Severity["SA1000"] != lint.SeverityWarning {
+		t.Errorf("severity of SA1000 is %s, want warning", cfg.Severity["SA1000"])
+	}
+	if cfg.Severity["SA1001"] != lint.SeverityInfo {
+		t.Errorf("severity of SA1001 is %s, want info", cfg.Severity["SA1001"])
+	}
+}
+
+func TestFormats(t *testing.T) {
+	srcs := map[string]string{
+		"test.conf": `
+[severity]
+SA1000 = warning
+ST1005 = error
+`,
+		"test.toml": `
+# comment
+[severity] # trailing comment
+SA1000 = "warning"
+"ST1005" = 'error' # trailing comment
+`,
+		"test.json": `{"severity": {"SA1000": "warning", "ST1005": "error"}}`,
+	}
+	want := Config{Severity: map[string]lint.Severity{
+		"SA1000": lint.SeverityWarning,
+		"ST1005": lint.SeverityError,
+	}}
+	dir, err := ioutil.TempDir("", "config")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	for name, src := range srcs {
+		path := filepath.Join(dir, name)
+		if err := ioutil.WriteFile(path, []byte(src), 0644); err != nil {
+			t.Fatal(err)
+		}
+		cfg, err := ParseFile(path)
+		if err != nil {
+			t.Errorf("%s: %s", name, err)
+			continue
+		}
+		if !reflect.DeepEqual(cfg, want) {
+			t.Errorf("%s: got %v, want %v", name, cfg, want)
+		}
+	}
+}
+
+func TestFormatErrors(t *testing.T) {
+	tests := []struct {
+		parse func(string, io.Reader) (Config, error)
+		src   string
+		err   string
+	}{
+		{ParseTOML, "[severity]\nSA1000 = warning", "test:2: unsupported value warning, only strings are supported"},
+		{ParseTOML, "[severity]\nSA1000 = \"warning", "test:2: unterminated string"},
+		{ParseTOML, "[severity]\nSA1000 = \"warning\" x", "test:2: unexpected \"x\" after value"},
+		{ParseTOML, "[severity]\nSA1000 = \"fatal\"", "test:2: unknown severity \"fatal\""},
+		{ParseJSON, `{"foo": {}}`, "test: unknown section \"foo\""},
+		{ParseJSON, `{"severity": {"SA1000": "fatal"}}`, "test: severity.SA1000: unknown severity \"fatal\""},
+	}
+	for _, tt := range tests {
+		_, err := tt.parse("test", strings.NewReader(tt.src))
+		if err == nil || err.Error() != tt.err {
+			t.Errorf("parsing %q returned error %v, want %q", tt.src, err, tt.err)
+		}
+	}
+}
+
+func TestLoadMultipleFormats(t *testing.T) {
+	dir, err := ioutil.TempDir("", "config")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	if err := ioutil.WriteFile(filepath.Join(dir, "staticcheck.json"), []byte(`{"severity": {"SA1000": "info"}}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := Load(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Severity["SA1000"] != lint.SeverityInfo {
+		t.Errorf("severity of SA1000 is %s, want info", cfg.Severity["SA1000"])
+	}
+
+	if err := ioutil.WriteFile(filepath.Join(dir, FileName), []byte("[severity]\nSA1000 = warning\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(dir); err == nil {
+		t.Error("Load succeeded despite two configuration files in the same directory")
+	}
+}
+
+func TestParseTests(t *testing.T) {
+	src := "[tests]\nenable = SA9*, ST1014\ndisable =\n"
+	cfg, err := Parse("test.conf", strings.NewReader(src))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"SA9*", "ST1014"}; !reflect.DeepEqual(cfg.TestEnabled, want) {
+		t.Errorf("got enabled checks %q, want %q", cfg.TestEnabled, want)
+	}
+	if cfg.TestDisabled == nil || len(cfg.TestDisabled) != 0 {
+		t.Errorf("got disabled checks %#v, want an empty list", cfg.TestDisabled)
+	}
+
+	// An empty list in a deeper directory resets the list.
+	merged := Config{TestDisabled: []string{"ST1003"}}.Merge(cfg)
+	if len(merged.TestDisabled) != 0 {
+		t.Errorf("got disabled checks %q after merging, want none", merged.TestDisabled)
+	}
+	merged = cfg.Merge(Config{})
+	if !reflect.DeepEqual(merged.TestEnabled, cfg.TestEnabled) {
+		t.Errorf("got enabled checks %q after merging, want %q", merged.TestEnabled, cfg.TestEnabled)
+	}
+}
+
+func TestParseChecks(t *testing.T) {
+	src := "[checks]\nselect = ^SA1, -SA1019, -^SA10[01], ST1005\n"
+	cfg, err := Parse("test.conf", strings.NewReader(src))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"^SA1", "-SA1019", "-^SA10[01]", "ST1005"}; !reflect.DeepEqual(cfg.Checks, want) {
+		t.Errorf("got checks %q, want %q", cfg.Checks, want)
+	}
+
+	// A deeper directory replaces the selection.
+	merged := cfg.Merge(Config{Checks: []string{"all"}})
+	if want := []string{"all"}; !reflect.DeepEqual(merged.Checks, want) {
+		t.Errorf("got checks %q after merging, want %q", merged.Checks, want)
+	}
+	merged = cfg.Merge(Config{})
+	if !reflect.DeepEqual(merged.Checks, cfg.Checks) {
+		t.Errorf("got checks %q after merging, want %q", merged.Checks, cfg.Checks)
+	}
+
+	tests := []struct {
+		src string
+		err string
+	}{
+		{"[checks]\nselect = all, ^SA(1", "test.conf:2: invalid regular expression \"^SA(1\": error parsing regexp: missing closing ): `^SA(1`"},
+		{"[checks]\nselect = all, -", "test.conf:2: invalid check \"-\": missing check name"},
+		{"[checks]\nenable = all", "test.conf:2: unknown key \"enable\""},
+	}
+	for _, tt := range tests {
+		_, err := Parse("test.conf", strings.NewReader(tt.src))
+		if err == nil || err.Error() != tt.err {
+			t.Errorf("Parse(%q) returned error %v, want %q", tt.src, err, tt.err)
+		}
+	}
+}
+
+func TestParseMaxPerCheck(t *testing.T) {
+	cfg, err := Parse("test.conf", strings.NewReader("[checks]\nmax-per-check = 20\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.MaxPerCheck == nil || *cfg.MaxPerCheck != 20 {
+		t.Fatalf("got limit %v, want 20", cfg.MaxPerCheck)
+	}
+
+	// A deeper directory can lift the limit again.
+	zero := 0
+	merged := cfg.Merge(Config{MaxPerCheck: &zero})
+	if *merged.MaxPerCheck != 0 {
+		t.Errorf("got limit %d after merging, want 0", *merged.MaxPerCheck)
+	}
+	merged = cfg.Merge(Config{})
+	if *merged.MaxPerCheck != 20 {
+		t.Errorf("got limit %d after merging, want 20", *merged.MaxPerCheck)
+	}
+
+	for _, src := range []string{"[checks]\nmax-per-check = many", "[checks]\nmax-per-check = -1"} {
+		if _, err := Parse("test.conf", strings.NewReader(src)); err == nil {
+			t.Errorf("Parse(%q) succeeded, want an error", src)
+		}
+	}
+}
+
+func TestParseMinConfidence(t *testing.T) {
+	cfg, err := Parse("test.conf", strings.NewReader("[checks]\nmin-confidence = 0.8\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.MinConfidence == nil || *cfg.MinConfidence != 0.8 {
+		t.Fatalf("got confidence %v, want 0.8", cfg.MinConfidence)
+	}
+	if merged := cfg.Merge(Config{}); *merged.MinConfidence != 0.8 {
+		t.Errorf("got confidence %v after merging, want 0.8", *merged.MinConfidence)
+	}
+
+	for _, src := range []string{"[checks]\nmin-confidence = high", "[checks]\nmin-confidence = 1.5", "[checks]\nmin-confidence = -0.1"} {
+		if _, err := Parse("test.conf", strings.NewReader(src)); err == nil {
+			t.Errorf("Parse(%q) succeeded, want an error", src)
+		}
+	}
+}
+
+func TestParseGenerated(t *testing.T) {
+	src := "[generated]\nexclude = true\npattern = ^// Generated by gen\\.go\n"
+	cfg, err := Parse("test.conf", strings.NewReader(src))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.ExcludeGenerated == nil || !*cfg.ExcludeGenerated {
+		t.Errorf("got exclude %v, want true", cfg.ExcludeGenerated)
+	}
+	if cfg.GeneratedPattern == nil || cfg.GeneratedPattern.String() != `^// Generated by gen\.go` {
+		t.Errorf("got pattern %v, want %q", cfg.GeneratedPattern, `^// Generated by gen\.go`)
+	}
+
+	// A deeper directory can turn the exclusion off again, while
+	// keeping the pattern.
+	no := false
+	merged := cfg.Merge(Config{ExcludeGenerated: &no})
+	if *merged.ExcludeGenerated || merged.GeneratedPattern != cfg.GeneratedPattern {
+		t.Errorf("got exclude %v and pattern %v after merging", *merged.ExcludeGenerated, merged.GeneratedPattern)
+	}
+
+	for _, src := range []string{"[generated]\nexclude = maybe", "[generated]\npattern = (", "[generated]\nfoo = bar"} {
+		if _, err := Parse("test.conf", strings.NewReader(src)); err == nil {
+			t.Errorf("Parse(%q) succeeded, want an error", src)
+		}
+	}
+}
+
+func TestParseNames(t *testing.T) {
+	cfg, err := Parse("test.conf", strings.NewReader("[names]\nST1003 = Id, *Url\nSA4006 =\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string][]string{
+		"ST1003": {"Id", "*Url"},
+		"SA4006": {},
+	}
+	if !reflect.DeepEqual(cfg.AllowedNames, want) {
+		t.Errorf("got %v, want %v", cfg.AllowedNames, want)
+	}
+
+	// Deeper directories replace the names of individual checks.
+	merged := cfg.Merge(Config{AllowedNames: map[string][]string{"ST1003": {"Uid"}}})
+	if got := merged.AllowedNames["ST1003"]; !reflect.DeepEqual(got, []string{"Uid"}) {
+		t.Errorf("got %v for ST1003 after merging, want [Uid]", got)
+	}
+	if _, ok := merged.AllowedNames["SA4006"]; !ok {
+		t.Errorf("lost SA4006 after merging")
+	}
+}
+
+func TestParseRules(t *testing.T) {
+	src := "[rules]\nR1000 = report CallExpr where Fun == \"fmt.Println\": \"don't print\"\n"
+	cfg, err := Parse("test.conf", strings.NewReader(src))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{"R1000": `report CallExpr where Fun == "fmt.Println": "don't print"`}
+	if !reflect.DeepEqual(cfg.Rules, want) {
+		t.Errorf("got %v, want %v", cfg.Rules, want)
+	}
+
+	for _, src := range []string{
+		"[rules]\nCUSTOM = report CallExpr\n",
+		"[rules]\nR1000 = report Call\n",
+	} {
+		if _, err := Parse("test.conf", strings.NewReader(src)); err == nil {
+			t.Errorf("no error parsing %q", src)
+		}
+	}
+}
+
+func TestParseIgnores(t *testing.T) {
+	src := `
+# comment
+gen.go SA4006
+gen/*.go:10-20 SA1000,ST*
+vendored/foo.go:42 SA1019
+`
+	dir := filepath.FromSlash("/root")
+	got, err := ParseIgnores("staticcheck.ignore", strings.NewReader(src), dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []*lint.RangeIgnore{
+		{Pattern: filepath.Join(dir, "gen.go"), Start: 1, Checks: []string{"SA4006"}},
+		{Pattern: filepath.Join(dir, "gen", "*.go"), Start: 10, End: 20, Checks: []string{"SA1000", "ST*"}},
+		{Pattern: filepath.Join(dir, "vendored", "foo.go"), Start: 42, End: 42, Checks: []string{"SA1019"}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		for _, ig := range got {
+			t.Logf("%+v", *ig)
+		}
+		t.Errorf("got different ignores than expected")
+	}
+
+	errs := []struct {
+		src string
+		err string
+	}{
+		{"gen.go", "staticcheck.ignore:1: expected file pattern and checks"},
+		{"gen.go:0 SA4006", `staticcheck.ignore:1: malformed line number "0"`},
+		{"gen.go:20-10 SA4006", `staticcheck.ignore:1: malformed line range "20-10"`},
+		{"gen[.go SA4006", `staticcheck.ignore:1: malformed pattern "gen[.go"`},
+	}
+	for _, tt := range errs {
+		_, err := ParseIgnores("staticcheck.ignore", strings.NewReader(tt.src), dir)
+		if err == nil || err.Error() != tt.err {
+			t.Errorf("ParseIgnores(%q) returned error %v, want %q", tt.src, err, tt.err)
+		}
+	}
+}
+
+func TestLoadIgnores(t *testing.T) {
+	root, err := ioutil.TempDir("", "config")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(root)
+	sub := filepath.Join(root, "a")
+	if err := os.MkdirAll(sub, 0755); err != nil {
+		t.Fatal(err)
+	}
+	for dir, src := range map[string]string{root: "a/gen.go:1-10 SA4006\n", sub: "gen.go:5-20 S1000\n"} {
+		if err := ioutil.WriteFile(filepath.Join(dir, IgnoreFileName), []byte(src), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cfg, err := Load(sub)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cfg.Ignores) != 2 {
+		t.Fatalf("got %d ignores, want 2", len(cfg.Ignores))
+	}
+	// both ignore files refer to the same file
+	for _, ig := range cfg.Ignores {
+		if ig.Pattern != filepath.Join(sub, "gen.go") {
+			t.Errorf("got pattern %s, want %s", ig.Pattern, filepath.Join(sub, "gen.go"))
+		}
+	}
+}
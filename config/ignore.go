@@ -0,0 +1,110 @@
+package config
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"honnef.co/go/tools/lint"
+)
+
+// IgnoreFileName is the name of ignore files, which are loaded
+// together with configuration files.
+//
+// Each line of an ignore file consists of a file pattern, optionally
+// followed by a colon and a line or range of lines, and a
+// comma-separated list of checks whose problems are ignored there.
+// Patterns use the syntax of filepath.Match and are relative to the
+// directory of the ignore file. Empty lines and lines starting with #
+// are ignored:
+//
+//	# the whole file
+//	parser.go SA4006,S1000
+//	# lines 10 through 250 of all files in gen
+//	gen/*.go:10-250 ST*
+//	# only line 42
+//	vendored/foo.go:42 SA1019
+const IgnoreFileName = "staticcheck.ignore"
+
+func isDigit(b byte) bool { return b >= '0' && b <= '9' }
+
+// parseLineRange parses a line number or a range of line numbers of
+// the form start-end.
+func parseLineRange(s string) (start, end int, err error) {
+	parts := strings.SplitN(s, "-", 2)
+	start, err = strconv.Atoi(parts[0])
+	if err != nil || start < 1 {
+		return 0, 0, fmt.Errorf("malformed line number %q", parts[0])
+	}
+	end = start
+	if len(parts) == 2 {
+		end, err = strconv.Atoi(parts[1])
+		if err != nil || end < start {
+			return 0, 0, fmt.Errorf("malformed line range %q", s)
+		}
+	}
+	return start, end, nil
+}
+
+// ParseIgnores parses an ignore file. Patterns are interpreted
+// relative to dir. The name is only used in error messages.
+func ParseIgnores(name string, r io.Reader, dir string) ([]*lint.RangeIgnore, error) {
+	var out []*lint.RangeIgnore
+	scanner := bufio.NewScanner(r)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("%s:%d: expected file pattern and checks", name, n)
+		}
+		ig := &lint.RangeIgnore{Start: 1}
+		pattern := fields[0]
+		if i := strings.LastIndex(pattern, ":"); i != -1 && i+1 < len(pattern) && isDigit(pattern[i+1]) {
+			var err error
+			ig.Start, ig.End, err = parseLineRange(pattern[i+1:])
+			if err != nil {
+				return nil, fmt.Errorf("%s:%d: %s", name, n, err)
+			}
+			pattern = pattern[:i]
+		}
+		if !filepath.IsAbs(pattern) {
+			pattern = filepath.Join(dir, pattern)
+		}
+		if _, err := filepath.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("%s:%d: malformed pattern %q", name, n, fields[0])
+		}
+		ig.Pattern = pattern
+		for _, check := range strings.Split(fields[1], ",") {
+			if check != "" {
+				ig.Checks = append(ig.Checks, check)
+			}
+		}
+		out = append(out, ig)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %s", name, err)
+	}
+	return out, nil
+}
+
+// ParseIgnoreFile parses the named ignore file. Patterns are
+// interpreted relative to the file's directory.
+func ParseIgnoreFile(name string) ([]*lint.RangeIgnore, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	dir, err := filepath.Abs(filepath.Dir(name))
+	if err != nil {
+		return nil, err
+	}
+	return ParseIgnores(name, f, dir)
+}
@@ -0,0 +1,75 @@
+package lint
+
+import (
+	"fmt"
+	"go/format"
+	"go/token"
+	"sort"
+)
+
+// A TextEdit replaces the text between Pos and End with NewText. The
+// positions refer to the actual files, ignoring //line directives.
+type TextEdit struct {
+	Pos     token.Position
+	End     token.Position
+	NewText string
+}
+
+// A SuggestedFix is a change to the source code that fixes a
+// problem. All of its edits have to be in the same file.
+type SuggestedFix struct {
+	Message string
+	Edits   []TextEdit
+}
+
+// Edit returns an edit that replaces the source code between pos and
+// end with newText.
+func (j *Job) Edit(pos, end token.Pos, newText string) TextEdit {
+	fset := j.Program.SSA.Fset
+	return TextEdit{
+		Pos:     fset.PositionFor(pos, false),
+		End:     fset.PositionFor(end, false),
+		NewText: newText,
+	}
+}
+
+// ApplyFixes applies fixes to src, the contents of a single file, and
+// formats the result with gofmt, which takes care of indentation.
+// Fixes that overlap with previously applied fixes are skipped. It
+// returns the new source and the number of fixes that were applied.
+func ApplyFixes(src []byte, fixes []SuggestedFix) ([]byte, int, error) {
+	var edits []TextEdit
+	applied := 0
+fixLoop:
+	for _, fix := range fixes {
+		for _, e1 := range fix.Edits {
+			if e1.Pos.Offset > e1.End.Offset || e1.End.Offset > len(src) {
+				return nil, 0, fmt.Errorf("invalid edit %s-%s", e1.Pos, e1.End)
+			}
+			for _, e2 := range edits {
+				if e1.Pos.Offset < e2.End.Offset && e2.Pos.Offset < e1.End.Offset {
+					continue fixLoop
+				}
+			}
+		}
+		edits = append(edits, fix.Edits...)
+		applied++
+	}
+	sort.SliceStable(edits, func(i, j int) bool {
+		return edits[i].Pos.Offset < edits[j].Pos.Offset
+	})
+
+	var out []byte
+	last := 0
+	for _, e := range edits {
+		out = append(out, src[last:e.Pos.Offset]...)
+		out = append(out, e.NewText...)
+		last = e.End.Offset
+	}
+	out = append(out, src[last:]...)
+	out, err := format.Source(out)
+	if err != nil {
+		return nil, 0, err
+	}
+	return out, applied, nil
+}
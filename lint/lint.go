@@ -8,15 +8,20 @@
 package lint // import "honnef.co/go/tools/lint"
 
 import (
+	"context"
 	"fmt"
 	"go/ast"
 	"go/build"
 	"go/token"
 	"go/types"
 	"path/filepath"
+	"regexp"
+	"runtime"
 	"sort"
+	"strconv"
 	"strings"
 	"sync"
+	"time"
 	"unicode"
 
 	"golang.org/x/tools/go/loader"
@@ -30,6 +35,46 @@ type Job struct {
 	checker  string
 	check    string
 	problems []Problem
+
+	// silent is set for checks that only run because other checks
+	// require them. Their problems are discarded.
+	silent bool
+	deps   map[string]*Job
+	done   chan struct{}
+	result interface{}
+
+	ctx context.Context
+	// skipped is set for checks that didn't finish, either because
+	// they timed out or because one of their requirements did.
+	skipped bool
+	// skipReason describes why the check was skipped.
+	skipReason string
+}
+
+// Context returns a context that is canceled once the check has run
+// for longer than the linter's timeout, or once the linter has found
+// its first problem when failing fast. Long-running checks should
+// return early when it is done; their problems get discarded either
+// way.
+func (j *Job) Context() context.Context {
+	return j.ctx
+}
+
+// SetResult sets the result of the check, which can be accessed by
+// checks that require it.
+func (j *Job) SetResult(v interface{}) {
+	j.result = v
+}
+
+// Result returns the result of a check that the current check
+// requires, as set by SetResult. It panics if the current check
+// doesn't require the other check.
+func (j *Job) Result(check string) interface{} {
+	dep, ok := j.deps[check]
+	if !ok {
+		panic(fmt.Sprintf("check %s doesn't require %s", j.check, check))
+	}
+	return dep.result
 }
 
 type Ignore interface {
@@ -65,9 +110,34 @@ func (li *LineIgnore) String() string {
 	return fmt.Sprintf("%s:%d %s (%s)", li.File, li.Line, strings.Join(li.Checks, ", "), matched)
 }
 
+// A LineEnable enables opt-in checks for the lines of the node that
+// a //lint:enable directive is attached to, without enabling them
+// anywhere else.
+type LineEnable struct {
+	File    string
+	Line    int
+	EndLine int
+	Checks  []string
+	matched bool
+	pos     token.Pos
+}
+
+func (le *LineEnable) Match(p Problem) bool {
+	if p.Position.Filename != le.File || p.Position.Line < le.Line || p.Position.Line > le.EndLine {
+		return false
+	}
+	if matchAny(le.Checks, p.Check) {
+		le.matched = true
+		return true
+	}
+	return false
+}
+
 type FileIgnore struct {
-	File   string
-	Checks []string
+	File    string
+	Checks  []string
+	matched bool
+	pos     token.Pos
 }
 
 func (fi *FileIgnore) Match(p Problem) bool {
@@ -75,6 +145,40 @@ func (fi *FileIgnore) Match(p Problem) bool {
 		return false
 	}
 	for _, c := range fi.Checks {
+		if m, _ := filepath.Match(c, p.Check); m {
+			fi.matched = true
+			return true
+		}
+	}
+	return false
+}
+
+// A RangeIgnore ignores problems in a range of lines of all files
+// matching a pattern. Unlike line and file ignores, range ignores
+// aren't created from linter directives, which makes them suitable
+// for code that can't be annotated, such as generated code.
+type RangeIgnore struct {
+	// Pattern is matched against the absolute file names of
+	// problems, with the syntax of filepath.Match.
+	Pattern string
+	// Start and End are the first and last ignored lines. An End of
+	// 0 ignores all lines from Start to the end of the file.
+	Start, End int
+	Checks     []string
+}
+
+func (ri *RangeIgnore) Match(p Problem) bool {
+	name := p.Position.Filename
+	if abs, err := filepath.Abs(name); err == nil {
+		name = abs
+	}
+	if m, _ := filepath.Match(ri.Pattern, name); !m {
+		return false
+	}
+	if p.Position.Line < ri.Start || (ri.End != 0 && p.Position.Line > ri.End) {
+		return false
+	}
+	for _, c := range ri.Checks {
 		if m, _ := filepath.Match(c, p.Check); m {
 			return true
 		}
@@ -116,6 +220,8 @@ type Program struct {
 	Files            []*ast.File
 	Info             *types.Info
 	GoVersion        int
+	// Sizes is the types.Sizes the program was type-checked with.
+	Sizes types.Sizes
 
 	tokenFileMap map[*token.File]*ast.File
 	astFileMap   map[*ast.File]*Pkg
@@ -125,13 +231,75 @@ type Func func(*Job)
 
 // Problem represents a problem in some source code.
 type Problem struct {
-	pos      token.Pos
-	Position token.Position // position in source file
-	Text     string         // the prose that describes the problem
-	Check    string
-	Checker  string
-	Package  *types.Package
-	Ignored  bool
+	pos        token.Pos
+	Position   token.Position // position in source file
+	End        token.Position // end of the code the problem is about, if known
+	Text       string         // the prose that describes the problem
+	Check      string
+	Checker    string
+	Package    *types.Package
+	Ignored    bool
+	Confidence float64 // a value in (0,1]; 1 unless the check is a heuristic
+	Severity   Severity
+	Fixes      []SuggestedFix
+	Since      string // the release that first included the check, if known
+	Name       string // the identifier the problem is about, if any
+	// Related lists other locations that the problem refers to,
+	// such as where a lock was acquired that is never released.
+	Related []RelatedInformation
+}
+
+// RelatedInformation describes a location, other than its own
+// position, that a problem refers to.
+type RelatedInformation struct {
+	Position token.Position
+	End      token.Position // end of the code, if known
+	Text     string
+}
+
+// CheckSkipped is the name of the pseudo check that notes about
+// skipped checks are reported as. The notes have no position and
+// default to SeverityWarning, but their severity can be configured
+// like that of any other check.
+const CheckSkipped = "skipped"
+
+// Severity describes how serious a problem is. Only problems of
+// severity SeverityError cause the command line tools to exit with a
+// non-zero status.
+type Severity int
+
+const (
+	SeverityError Severity = iota
+	SeverityWarning
+	SeverityInfo
+)
+
+func (s Severity) String() string {
+	switch s {
+	case SeverityError:
+		return "error"
+	case SeverityWarning:
+		return "warning"
+	case SeverityInfo:
+		return "info"
+	default:
+		return fmt.Sprintf("Severity(%d)", int(s))
+	}
+}
+
+// ParseSeverity parses the name of a severity, as returned by
+// Severity.String.
+func ParseSeverity(s string) (Severity, error) {
+	switch s {
+	case "error":
+		return SeverityError, nil
+	case "warning":
+		return SeverityWarning, nil
+	case "info":
+		return SeverityInfo, nil
+	default:
+		return 0, fmt.Errorf("unknown severity %q", s)
+	}
 }
 
 func (p *Problem) String() string {
@@ -148,14 +316,395 @@ type Checker interface {
 	Funcs() map[string]Func
 }
 
+// CheckInfo describes properties of a check that aren't captured by
+// its implementation.
+type CheckInfo struct {
+	// Requires lists checks that have to run before this check,
+	// because it uses their results. Required checks always run,
+	// but their problems are only reported if they are enabled.
+	// Because all packages are checked at once, results cover the
+	// entire program, not just individual packages.
+	Requires []string
+	// OptIn marks checks that don't run unless explicitly enabled,
+	// usually because they are opinionated or prone to false
+	// positives.
+	OptIn bool
+	// Fixable marks checks that may offer suggested fixes for the
+	// problems they find.
+	Fixable bool
+	// Since is the release that first included the check, such as
+	// 2019.1. Checks without it predate all releases.
+	Since string
+}
+
+// An InfoChecker is a Checker that provides additional information
+// about some or all of its checks.
+type InfoChecker interface {
+	Checker
+	Info() map[string]CheckInfo
+}
+
 // A Linter lints Go source code.
 type Linter struct {
 	Checker       Checker
 	Ignores       []Ignore
 	GoVersion     int
 	ReturnIgnored bool
+	// Enabled lists opt-in checks that should be run. Entries may
+	// use globbing, e.g. SA9*.
+	Enabled []string
+	// Checks, if not nil, selects the checks to run, replacing the
+	// distinction between default and opt-in checks. It is a list
+	// of tokens that are applied in order, each selecting the
+	// checks it matches, or deselecting them if it starts with a
+	// dash. The token "all" matches all checks, tokens starting with
+	// ^ are regular expressions, and all other tokens are check
+	// names, which support globbing. For example, "all", "-ST1000"
+	// runs all checks but ST1000, and "^SA1", "-SA1019" runs all
+	// SA1xxx checks but SA1019. Enabled and the other ways of
+	// enabling checks still apply. Checks has to be valid; see
+	// ValidateChecks.
+	Checks []string
+	// TestEnabled lists opt-in checks that should be run only for
+	// tests. Their problems are only reported in _test.go files.
+	// Entries may use globbing.
+	TestEnabled []string
+	// TestDisabled lists checks whose problems shouldn't be reported
+	// in _test.go files. Entries may use globbing.
+	TestDisabled []string
+	// MinConfidence causes problems with a lower confidence to be
+	// discarded.
+	MinConfidence float64
+	// Progress, if set, is called each time a check has finished
+	// running, with the number of finished checks and the total
+	// number of checks. Calls are serialized.
+	Progress func(done, total int)
+	// Timeout, if non-zero, limits how long each check may run.
+	// Checks that take longer, and checks requiring them, are
+	// skipped, which is reported as a problem.
+	Timeout time.Duration
+	// Deadline, if not zero, is when Lint has to return. Checks that
+	// are still running at the deadline are skipped like those that
+	// exceed Timeout, and so are checks that haven't started yet.
+	// The problems of the checks that finished are returned.
+	Deadline time.Time
+	// Context, if not nil, stops Lint when it is canceled, in the
+	// same way as reaching the Deadline.
+	Context context.Context
+	// OnProblem, if set, is called with each problem that Lint is
+	// going to return, as soon as the check that found it has
+	// finished, which allows streaming problems to a consumer. Checks
+	// run in parallel, so it may be called concurrently from
+	// multiple goroutines.
+	OnProblem func(Problem)
+	// OnlyFixable causes only checks that are marked as fixable to
+	// run, which is useful for applying all available fixes to a
+	// code base.
+	OnlyFixable bool
+	// NewSince, if set, causes only checks that were added in
+	// releases after NewSince to run, which helps with adopting new
+	// checks gradually. It has to be a valid release; see
+	// ValidRelease.
+	NewSince string
+	// ExcludeGenerated causes problems in generated files to be
+	// discarded. Generated files are still analyzed like all other
+	// files, as checks may need them to understand the rest of the
+	// code; only the problems found in them are filtered out. Files
+	// are generated if a comment before their package clause has a
+	// line matching DefaultGeneratedPattern or, if it isn't nil,
+	// GeneratedPattern, which identifies the files of generators
+	// that don't follow the Go convention.
+	ExcludeGenerated bool
+	GeneratedPattern *regexp.Regexp
+	// FailFast causes Lint to stop as soon as the first problem that
+	// isn't ignored has been found. Checks that haven't started yet
+	// don't run, running checks are asked to stop via their
+	// contexts, and their problems are discarded. Lint then returns
+	// only that problem.
+	FailFast bool
+	// MaxPerCheck, if positive, limits the number of problems that
+	// are reported for each check. The problems of a check beyond
+	// the first MaxPerCheck, in order of their positions, are
+	// replaced by a single problem saying how many more there are.
+	// Ignored problems don't count towards the limit.
+	MaxPerCheck int
+	// Costs, if not nil, maps checks to how long they take to run,
+	// for example on average in previous runs. Checks are then
+	// started in order of increasing cost, with checks that have no
+	// cost last, and at most GOMAXPROCS of them run at once. Checks
+	// still start after the checks they require. Together with
+	// FailFast, this lets cheap checks find the first problem before
+	// expensive ones have started. Without Costs, all checks start
+	// at once, in the order of their requirements.
+	Costs map[string]time.Duration
+	// OnCheckDone, if set, is called with the time each check took
+	// to run, as soon as it has finished. Checks that have been
+	// skipped or didn't run aren't reported. It may be called
+	// concurrently from multiple goroutines.
+	OnCheckDone func(check string, d time.Duration)
+	// AllowedNames maps checks to glob patterns of identifier names
+	// that they shouldn't report problems about, for example to
+	// exempt a legacy name like Id from the initialisms check. It
+	// applies to problems that checks report on identifiers.
+	AllowedNames map[string][]string
+	// Filter, if set, is called with each problem, and problems for
+	// which it returns false are discarded. Discarded problems don't
+	// count towards MaxPerCheck and don't stop Lint under FailFast.
+	Filter func(Problem) bool
 
 	automaticIgnores []Ignore
+	automaticEnables []*LineEnable
+	// selected is the set of checks selected by Checks, or nil.
+	selected map[string]bool
+}
+
+func (l *Linter) enabled(check string, infos map[string]CheckInfo) bool {
+	if l.OnlyFixable && !infos[check].Fixable {
+		return false
+	}
+	if l.NewSince != "" && compareReleases(infos[check].Since, l.NewSince) <= 0 {
+		return false
+	}
+	if l.selected != nil {
+		if l.selected[check] {
+			return true
+		}
+	} else if !infos[check].OptIn {
+		return true
+	}
+	if matchAny(l.Enabled, check) || matchAny(l.TestEnabled, check) {
+		return true
+	}
+	for _, le := range l.automaticEnables {
+		if matchAny(le.Checks, check) {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidateChecks returns an error if checks isn't a valid list of
+// checks, as used by Linter.Checks, such as when it contains an
+// invalid regular expression.
+func ValidateChecks(checks []string) error {
+	_, err := selectChecks(checks, nil)
+	return err
+}
+
+// selectChecks returns which of names the list of checks selects.
+func selectChecks(checks []string, names []string) (map[string]bool, error) {
+	out := map[string]bool{}
+	for _, tok := range checks {
+		enable := !strings.HasPrefix(tok, "-")
+		pattern := strings.TrimPrefix(tok, "-")
+		var match func(string) bool
+		switch {
+		case pattern == "":
+			return nil, fmt.Errorf("invalid check %q: missing check name", tok)
+		case pattern == "all":
+			match = func(string) bool { return true }
+		case strings.HasPrefix(pattern, "^"):
+			rx, err := regexp.Compile(pattern)
+			if err != nil {
+				return nil, fmt.Errorf("invalid regular expression %q: %s", pattern, err)
+			}
+			match = rx.MatchString
+		default:
+			if _, err := filepath.Match(pattern, ""); err != nil {
+				return nil, fmt.Errorf("invalid check %q: %s", tok, err)
+			}
+			match = func(name string) bool { return matchAny([]string{pattern}, name) }
+		}
+		for _, name := range names {
+			if match(name) {
+				out[name] = enable
+			}
+		}
+	}
+	return out, nil
+}
+
+// ValidRelease reports whether s is a valid release, consisting of
+// dot-separated numbers, such as 2019.1 or 2019.1.1.
+func ValidRelease(s string) bool {
+	_, ok := parseRelease(s)
+	return ok
+}
+
+func parseRelease(s string) ([]int, bool) {
+	var out []int
+	for _, f := range strings.Split(s, ".") {
+		n, err := strconv.Atoi(f)
+		if err != nil || n < 0 {
+			return nil, false
+		}
+		out = append(out, n)
+	}
+	return out, true
+}
+
+// compareReleases returns -1, 0 or 1 if release a is older than, the
+// same as or newer than release b. Invalid releases, including the
+// empty string, are older than all valid releases. Missing
+// components count as zero, so 2019.1 is the same as 2019.1.0.
+func compareReleases(a, b string) int {
+	ra, okA := parseRelease(a)
+	rb, okB := parseRelease(b)
+	switch {
+	case !okA && !okB:
+		return 0
+	case !okA:
+		return -1
+	case !okB:
+		return 1
+	}
+	for i := 0; i < len(ra) || i < len(rb); i++ {
+		var x, y int
+		if i < len(ra) {
+			x = ra[i]
+		}
+		if i < len(rb) {
+			y = rb[i]
+		}
+		switch {
+		case x < y:
+			return -1
+		case x > y:
+			return 1
+		}
+	}
+	return 0
+}
+
+// matchAny reports whether check matches any of the glob patterns.
+func matchAny(patterns []string, check string) bool {
+	for _, c := range patterns {
+		if m, _ := filepath.Match(c, check); m {
+			return true
+		}
+	}
+	return false
+}
+
+// reportedIn reports whether p should be reported, given the selected
+// checks, the checks enabled and disabled for tests, the allowed
+// names and the checks enabled for individual lines.
+func (l *Linter) reportedIn(p Problem, infos map[string]CheckInfo) bool {
+	test := strings.HasSuffix(p.Position.Filename, "_test.go")
+	if test && matchAny(l.TestDisabled, p.Check) {
+		return false
+	}
+	if p.Name != "" && matchAny(l.AllowedNames[p.Check], p.Name) {
+		return false
+	}
+	if l.selected != nil {
+		if l.selected[p.Check] {
+			return true
+		}
+	} else if !infos[p.Check].OptIn {
+		return true
+	}
+	if matchAny(l.Enabled, p.Check) {
+		return true
+	}
+	if test && matchAny(l.TestEnabled, p.Check) {
+		return true
+	}
+	matched := false
+	for _, le := range l.automaticEnables {
+		// We cannot short-circuit these, as we want to record, for
+		// each enable, whether it matched or not.
+		if le.Match(p) {
+			matched = true
+		}
+	}
+	return matched
+}
+
+// jobs returns the jobs for running checks, as well as for all the
+// checks they transitively require. It panics if a check requires
+// an unknown check or if requirements form a cycle.
+func (l *Linter) jobs(prog *Program, checks []string, funcs map[string]Func, infos map[string]CheckInfo) []*Job {
+	enabled := map[string]bool{}
+	for _, check := range checks {
+		enabled[check] = true
+	}
+	byCheck := map[string]*Job{}
+	var jobs []*Job
+	// visiting tracks the checks whose requirements are currently
+	// being resolved, to detect cycles
+	visiting := map[string]bool{}
+	var add func(check string) *Job
+	add = func(check string) *Job {
+		if j, ok := byCheck[check]; ok {
+			return j
+		}
+		if visiting[check] {
+			panic(fmt.Sprintf("requirements of check %s form a cycle", check))
+		}
+		visiting[check] = true
+		j := &Job{
+			Program: prog,
+			checker: l.Checker.Name(),
+			check:   check,
+			silent:  !enabled[check],
+			deps:    map[string]*Job{},
+			done:    make(chan struct{}),
+			ctx:     context.Background(),
+		}
+		for _, req := range infos[check].Requires {
+			if _, ok := funcs[req]; !ok {
+				panic(fmt.Sprintf("check %s requires unknown check %s", check, req))
+			}
+			j.deps[req] = add(req)
+		}
+		delete(visiting, check)
+		byCheck[check] = j
+		jobs = append(jobs, j)
+		return j
+	}
+	for _, check := range checks {
+		add(check)
+	}
+	return jobs
+}
+
+// scheduleJobs returns jobs ordered by the costs of their checks,
+// cheapest first, followed by the jobs whose checks have no cost, in
+// their original order. Jobs always come after the jobs they require.
+func scheduleJobs(jobs []*Job, costs map[string]time.Duration) []*Job {
+	sorted := make([]*Job, len(jobs))
+	copy(sorted, jobs)
+	sort.SliceStable(sorted, func(i, k int) bool {
+		ci, iok := costs[sorted[i].check]
+		ck, kok := costs[sorted[k].check]
+		if iok != kok {
+			return iok
+		}
+		return ci < ck
+	})
+	out := make([]*Job, 0, len(jobs))
+	added := map[*Job]bool{}
+	var add func(j *Job)
+	add = func(j *Job) {
+		if added[j] {
+			return
+		}
+		added[j] = true
+		var deps []string
+		for check := range j.deps {
+			deps = append(deps, check)
+		}
+		sort.Strings(deps)
+		for _, check := range deps {
+			add(j.deps[check])
+		}
+		out = append(out, j)
+	}
+	for _, j := range sorted {
+		add(j)
+	}
+	return out
 }
 
 func (l *Linter) ignore(p Problem) bool {
@@ -213,6 +762,9 @@ func (ps byPosition) Less(i int, j int) bool {
 	if pi.Column != pj.Column {
 		return pi.Column < pj.Column
 	}
+	if ps.ps[i].Check != ps.ps[j].Check {
+		return ps.ps[i].Check < ps.ps[j].Check
+	}
 
 	return ps.ps[i].Text < ps.ps[j].Text
 }
@@ -265,9 +817,15 @@ func (l *Linter) Lint(lprog *loader.Program, conf *loader.Config) []Problem {
 		Packages:     pkgs,
 		Info:         &types.Info{},
 		GoVersion:    l.GoVersion,
+		Sizes:        conf.TypeChecker.Sizes,
 		tokenFileMap: map[*token.File]*ast.File{},
 		astFileMap:   map[*ast.File]*Pkg{},
 	}
+	if prog.Sizes == nil {
+		// go/types falls back to the same default when no Sizes have
+		// been provided.
+		prog.Sizes = &types.StdSizes{WordSize: 8, MaxAlign: 8}
+	}
 
 	initial := map[*types.Package]struct{}{}
 	for _, pkg := range pkgs {
@@ -298,8 +856,41 @@ func (l *Linter) Lint(lprog *loader.Program, conf *loader.Config) []Problem {
 		}
 	}
 
+	// deadlineCtx is canceled at the Deadline or together with
+	// Context, and stopCtx is additionally canceled once FailFast
+	// has found its problem.
+	deadlineCtx := context.Background()
+	if l.Context != nil {
+		deadlineCtx = l.Context
+	}
+	if !l.Deadline.IsZero() {
+		var cancel context.CancelFunc
+		deadlineCtx, cancel = context.WithDeadline(deadlineCtx, l.Deadline)
+		defer cancel()
+	}
+	stopCtx, stop := context.WithCancel(deadlineCtx)
+	defer stop()
 	var out []Problem
+	stopped := false
+	outMu := &sync.Mutex{}
+	emit := func(p Problem) {
+		outMu.Lock()
+		if stopped {
+			outMu.Unlock()
+			return
+		}
+		out = append(out, p)
+		if l.FailFast && !p.Ignored {
+			stopped = true
+			stop()
+		}
+		outMu.Unlock()
+		if l.OnProblem != nil {
+			l.OnProblem(p)
+		}
+	}
 	l.automaticIgnores = nil
+	l.automaticEnables = nil
 	for _, pkginfo := range lprog.InitialPackages() {
 		for _, f := range pkginfo.Files {
 			cm := ast.NewCommentMap(lprog.Fset, f, f.Comments)
@@ -321,16 +912,57 @@ func (l *Linter) Lint(lprog *loader.Program, conf *loader.Config) []Problem {
 									Check:    "",
 									Checker:  l.Checker.Name(),
 									Package:  nil,
+
+									Confidence: 1,
 								}
-								out = append(out, p)
+								emit(p)
+								continue
+							}
+						case "enable":
+							if len(args) < 1 || args[0] == "" {
+								p := Problem{
+									pos:      c.Pos(),
+									Position: prog.DisplayPosition(c.Pos()),
+									Text:     "malformed linter directive; missing the checks to enable?",
+									Check:    "",
+									Checker:  l.Checker.Name(),
+									Package:  nil,
+
+									Confidence: 1,
+								}
+								emit(p)
 								continue
 							}
 						default:
 							// unknown directive, ignore
 							continue
 						}
+						if cmd == "file-ignore" && c.Pos() > f.Package && cg != f.Comments[0] {
+							p := Problem{
+								pos:      c.Pos(),
+								Position: prog.DisplayPosition(c.Pos()),
+								Text:     "file-ignore directive has to appear before the package clause or in the first comment block of the file",
+								Check:    "",
+								Checker:  l.Checker.Name(),
+								Package:  nil,
+
+								Confidence: 1,
+							}
+							emit(p)
+							continue
+						}
 						checks := strings.Split(args[0], ",")
 						pos := prog.DisplayPosition(node.Pos())
+						if cmd == "enable" {
+							l.automaticEnables = append(l.automaticEnables, &LineEnable{
+								File:    pos.Filename,
+								Line:    pos.Line,
+								EndLine: prog.DisplayPosition(node.End()).Line,
+								Checks:  checks,
+								pos:     c.Pos(),
+							})
+							continue
+						}
 						var ig Ignore
 						switch cmd {
 						case "ignore":
@@ -344,6 +976,7 @@ func (l *Linter) Lint(lprog *loader.Program, conf *loader.Config) []Problem {
 							ig = &FileIgnore{
 								File:   pos.Filename,
 								Checks: checks,
+								pos:    c.Pos(),
 							}
 						}
 						l.automaticIgnores = append(l.automaticIgnores, ig)
@@ -398,53 +1031,261 @@ func (l *Linter) Lint(lprog *loader.Program, conf *loader.Config) []Problem {
 	l.Checker.Init(prog)
 
 	funcs := l.Checker.Funcs()
+	var infos map[string]CheckInfo
+	if ic, ok := l.Checker.(InfoChecker); ok {
+		infos = ic.Info()
+	}
+	l.selected = nil
+	if l.Checks != nil {
+		var names []string
+		for k := range funcs {
+			names = append(names, k)
+		}
+		var err error
+		l.selected, err = selectChecks(l.Checks, names)
+		if err != nil {
+			panic(fmt.Sprintf("invalid list of checks: %s", err))
+		}
+	}
 	var keys []string
 	for k := range funcs {
+		if !l.enabled(k, infos) {
+			continue
+		}
 		keys = append(keys, k)
 	}
 	sort.Strings(keys)
 
-	var jobs []*Job
-	for _, k := range keys {
-		j := &Job{
-			Program: prog,
-			checker: l.Checker.Name(),
-			check:   k,
+	// Problems in generated files are found like all others and
+	// discarded when they are reported.
+	generated := map[*token.File]bool{}
+	if l.ExcludeGenerated {
+		patterns := []*regexp.Regexp{DefaultGeneratedPattern}
+		if l.GeneratedPattern != nil {
+			patterns = append(patterns, l.GeneratedPattern)
 		}
-		jobs = append(jobs, j)
-	}
-	wg := &sync.WaitGroup{}
-	for _, j := range jobs {
-		wg.Add(1)
-		go func(j *Job) {
-			defer wg.Done()
-			fn := funcs[j.check]
-			if fn == nil {
-				return
+		for _, f := range prog.Files {
+			for _, pattern := range patterns {
+				if isGenerated(f, pattern) {
+					generated[prog.Prog.Fset.File(f.Pos())] = true
+					break
+				}
 			}
-			fn(j)
-		}(j)
+		}
 	}
-	wg.Wait()
 
-	for _, j := range jobs {
+	// ignoreMu serializes matching problems against ignores and
+	// line enables, which records which of them matched.
+	ignoreMu := &sync.Mutex{}
+	report := func(j *Job) {
+		if j.skipped {
+			emit(Problem{
+				Text:       fmt.Sprintf("check %s was skipped because %s", j.check, j.skipReason),
+				Check:      CheckSkipped,
+				Checker:    l.Checker.Name(),
+				Confidence: 1,
+				Severity:   SeverityWarning,
+			})
+			return
+		}
+		if j.silent {
+			return
+		}
+		var ps []Problem
 		for _, p := range j.problems {
+			// Match ignores even for discarded problems, so that
+			// their directives aren't reported as unused.
+			ignoreMu.Lock()
 			p.Ignored = l.ignore(p)
+			ignoreMu.Unlock()
+			if p.Confidence < l.MinConfidence {
+				continue
+			}
+			if prog.isCgoGenerated(p.pos) || generated[prog.Prog.Fset.File(p.pos)] {
+				continue
+			}
+			ignoreMu.Lock()
+			reported := l.reportedIn(p, infos)
+			ignoreMu.Unlock()
+			if !reported {
+				continue
+			}
+			if l.Filter != nil && !l.Filter(p) {
+				continue
+			}
+			p.Since = infos[p.Check].Since
 			if l.ReturnIgnored || !p.Ignored {
-				out = append(out, p)
+				ps = append(ps, p)
+			}
+		}
+		if l.MaxPerCheck <= 0 {
+			for _, p := range ps {
+				emit(p)
+			}
+			return
+		}
+		// Sort the problems, so that the same ones are kept in every
+		// run. The note about the suppressed problems is placed at
+		// the first of them.
+		sort.Sort(byPosition{nil, ps})
+		kept := 0
+		var note *Problem
+		suppressed := 0
+		for i, p := range ps {
+			if !p.Ignored {
+				if kept == l.MaxPerCheck {
+					if note == nil {
+						note = &ps[i]
+					}
+					suppressed++
+					continue
+				}
+				kept++
+			}
+			emit(p)
+		}
+		if note != nil {
+			p := *note
+			p.Text = fmt.Sprintf("(+%d more)", suppressed)
+			p.End = token.Position{}
+			p.Fixes = nil
+			p.Related = nil
+			emit(p)
+		}
+	}
+
+	jobs := l.jobs(prog, keys, funcs, infos)
+	wg := &sync.WaitGroup{}
+	progressMu := &sync.Mutex{}
+	done := 0
+	run := func(j *Job) {
+		defer report(j)
+		if l.Progress != nil {
+			defer func() {
+				progressMu.Lock()
+				done++
+				l.Progress(done, len(jobs))
+				progressMu.Unlock()
+			}()
+		}
+		defer close(j.done)
+		for _, dep := range j.deps {
+			<-dep.done
+		}
+		for _, dep := range j.deps {
+			if dep.skipped {
+				j.skipped = true
+				j.skipReason = fmt.Sprintf("it requires check %s, which was skipped", dep.check)
+				return
+			}
+		}
+		fn := funcs[j.check]
+		if fn == nil {
+			return
+		}
+		if stopCtx.Err() != nil {
+			if deadlineCtx.Err() != nil {
+				j.skipped = true
+				j.skipReason = "linting didn't finish before the deadline"
 			}
+			return
+		}
+		if l.OnCheckDone != nil {
+			start := time.Now()
+			defer func() {
+				if !j.skipped {
+					l.OnCheckDone(j.check, time.Since(start))
+				}
+			}()
+		}
+		if l.Timeout <= 0 && !l.FailFast && l.Deadline.IsZero() && l.Context == nil {
+			fn(j)
+			return
+		}
+
+		ctx := stopCtx
+		if l.Timeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, l.Timeout)
+			defer cancel()
+		}
+		j.ctx = ctx
+		finished := make(chan struct{})
+		go func() {
+			defer close(finished)
+			fn(j)
+		}()
+		select {
+		case <-finished:
+		case <-ctx.Done():
+			// We can't stop the check, but we can stop waiting
+			// for it. It keeps running in the background and
+			// its problems are never looked at.
+			j.skipped = true
+			switch {
+			case deadlineCtx.Err() != nil:
+				j.skipReason = "linting didn't finish before the deadline"
+			case stopCtx.Err() != nil:
+				j.skipReason = "linting stopped at the first problem"
+			default:
+				j.skipReason = fmt.Sprintf("it didn't finish within %s", l.Timeout)
+			}
+		}
+	}
+	if l.Costs == nil {
+		for _, j := range jobs {
+			wg.Add(1)
+			go func(j *Job) {
+				defer wg.Done()
+				run(j)
+			}(j)
+		}
+	} else {
+		// Workers take jobs in order, and jobs come after their
+		// requirements, so a job that waits for its requirements
+		// waits for jobs that have already been taken.
+		queue := make(chan *Job, len(jobs))
+		for _, j := range scheduleJobs(jobs, l.Costs) {
+			queue <- j
+		}
+		close(queue)
+		for i := 0; i < runtime.GOMAXPROCS(0); i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for j := range queue {
+					run(j)
+				}
+			}()
 		}
 	}
+	wg.Wait()
 
+	type directive struct {
+		checks []string
+		pos    token.Pos
+	}
+	var unmatched []directive
 	for _, ig := range l.automaticIgnores {
-		ig, ok := ig.(*LineIgnore)
-		if !ok {
-			continue
+		switch ig := ig.(type) {
+		case *LineIgnore:
+			if !ig.matched {
+				unmatched = append(unmatched, directive{ig.Checks, ig.pos})
+			}
+		case *FileIgnore:
+			if !ig.matched {
+				unmatched = append(unmatched, directive{ig.Checks, ig.pos})
+			}
 		}
-		if ig.matched {
-			continue
+	}
+	for _, le := range l.automaticEnables {
+		// enables of checks that are enabled anyway never match
+		if !le.matched {
+			unmatched = append(unmatched, directive{le.Checks, le.pos})
 		}
-		for _, c := range ig.Checks {
+	}
+	for _, d := range unmatched {
+		for _, c := range d.checks {
 			idx := strings.IndexFunc(c, func(r rune) bool {
 				return unicode.IsNumber(r)
 			})
@@ -456,15 +1297,22 @@ func (l *Linter) Lint(lprog *loader.Program, conf *loader.Config) []Problem {
 				// not for this checker
 				continue
 			}
+			if _, ok := funcs[c]; ok && !l.enabled(c, infos) {
+				// the check didn't run, so the directive couldn't
+				// have matched anything
+				continue
+			}
 			p := Problem{
-				pos:      ig.pos,
-				Position: prog.DisplayPosition(ig.pos),
+				pos:      d.pos,
+				Position: prog.DisplayPosition(d.pos),
 				Text:     "this linter directive didn't match anything; should it be removed?",
 				Check:    "",
 				Checker:  l.Checker.Name(),
 				Package:  nil,
+
+				Confidence: 1,
 			}
-			out = append(out, p)
+			emit(p)
 		}
 	}
 
@@ -519,6 +1367,47 @@ func (prog *Program) DisplayPosition(p token.Pos) token.Position {
 	return prog.Prog.Fset.PositionFor(p, false)
 }
 
+// DefaultGeneratedPattern matches the comment that marks generated
+// files according to the Go convention, see
+// https://golang.org/s/generatedcode.
+var DefaultGeneratedPattern = regexp.MustCompile(`^// Code generated .* DO NOT EDIT\.$`)
+
+// isGenerated reports whether one of the comments before the package
+// clause of f has a line matching pattern.
+func isGenerated(f *ast.File, pattern *regexp.Regexp) bool {
+	for _, cg := range f.Comments {
+		if cg.Pos() > f.Package {
+			break
+		}
+		for _, c := range cg.List {
+			for _, line := range strings.Split(c.Text, "\n") {
+				if pattern.MatchString(strings.TrimSuffix(line, "\r")) {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// isCgoGenerated reports whether p is in code that cgo generated for
+// a package, such as the wrappers in _cgo_gotypes.go, as opposed to
+// code in the package's source files. cgo writes the files it
+// generates to a temporary directory, which is gone by the time
+// problems are reported, and problems in them can't be fixed by the
+// user.
+func (prog *Program) isCgoGenerated(p token.Pos) bool {
+	tf := prog.Prog.Fset.File(p)
+	if tf == nil {
+		return false
+	}
+	pkg := prog.astFileMap[prog.tokenFileMap[tf]]
+	if pkg == nil || pkg.BuildPkg == nil || len(pkg.BuildPkg.CgoFiles) == 0 {
+		return false
+	}
+	return filepath.Dir(prog.DisplayPosition(p).Filename) != pkg.BuildPkg.Dir
+}
+
 func (j *Job) Errorf(n Positioner, format string, args ...interface{}) *Problem {
 	tf := j.Program.SSA.Fset.File(n.Pos())
 	f := j.Program.tokenFileMap[tf]
@@ -526,17 +1415,37 @@ func (j *Job) Errorf(n Positioner, format string, args ...interface{}) *Problem
 
 	pos := j.Program.DisplayPosition(n.Pos())
 	problem := Problem{
-		pos:      n.Pos(),
-		Position: pos,
-		Text:     fmt.Sprintf(format, args...),
-		Check:    j.check,
-		Checker:  j.checker,
-		Package:  pkg,
+		pos:        n.Pos(),
+		Position:   pos,
+		Text:       fmt.Sprintf(format, args...),
+		Check:      j.check,
+		Checker:    j.checker,
+		Package:    pkg,
+		Confidence: 1,
+	}
+	if n, ok := n.(interface{ End() token.Pos }); ok && n.End().IsValid() {
+		problem.End = j.Program.DisplayPosition(n.End())
+	}
+	if ident, ok := n.(*ast.Ident); ok {
+		problem.Name = ident.Name
 	}
 	j.problems = append(j.problems, problem)
 	return &j.problems[len(j.problems)-1]
 }
 
+// Related returns information about the location of n, to be added
+// to the Related field of a problem returned by Errorf.
+func (j *Job) Related(n Positioner, format string, args ...interface{}) RelatedInformation {
+	info := RelatedInformation{
+		Position: j.Program.DisplayPosition(n.Pos()),
+		Text:     fmt.Sprintf(format, args...),
+	}
+	if n, ok := n.(interface{ End() token.Pos }); ok && n.End().IsValid() {
+		info.End = j.Program.DisplayPosition(n.End())
+	}
+	return info
+}
+
 func (j *Job) NodePackage(node Positioner) *Pkg {
 	f := j.File(node)
 	return j.Program.astFileMap[f]
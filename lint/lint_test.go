@@ -1,10 +1,25 @@
 package lint_test
 
 import (
+	"context"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"log"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"runtime"
+	"sort"
+	"strings"
 	"testing"
+	"time"
 
 	. "honnef.co/go/tools/lint"
 	"honnef.co/go/tools/lint/testutil"
+
+	"golang.org/x/tools/go/loader"
 )
 
 type testChecker struct{}
@@ -32,3 +47,884 @@ func TestAll(t *testing.T) {
 	c := testChecker{}
 	testutil.TestAll(t, c, "")
 }
+
+// optInChecker has an opt-in check that flags all functions.
+type optInChecker struct{ testChecker }
+
+func (optInChecker) Funcs() map[string]Func {
+	return map[string]Func{
+		"TEST5000": testLint,
+	}
+}
+
+func (optInChecker) Info() map[string]CheckInfo {
+	return map[string]CheckInfo{
+		"TEST5000": {OptIn: true},
+	}
+}
+
+func TestLineEnables(t *testing.T) {
+	testutil.TestDefaults(t, optInChecker{}, "line-enables")
+}
+
+// manyOptInChecker has several opt-in checks that flag all
+// functions, so that line enables are matched concurrently.
+type manyOptInChecker struct{ testChecker }
+
+func (manyOptInChecker) Funcs() map[string]Func {
+	funcs := map[string]Func{}
+	for i := 0; i < 8; i++ {
+		funcs[fmt.Sprintf("TEST700%d", i)] = testLint
+	}
+	return funcs
+}
+
+func (c manyOptInChecker) Info() map[string]CheckInfo {
+	infos := map[string]CheckInfo{}
+	for check := range c.Funcs() {
+		infos[check] = CheckInfo{OptIn: true}
+	}
+	return infos
+}
+
+func TestConcurrentLineEnables(t *testing.T) {
+	const src = `package pkg
+
+func fn1() {}
+
+//lint:enable TEST7*
+func fn2() {}
+`
+	conf := &loader.Config{ParserMode: parser.ParseComments}
+	f, err := conf.ParseFile("pkg.go", src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	conf.CreateFromFiles("pkg", f)
+	lprog, err := conf.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	l := &Linter{Checker: manyOptInChecker{}}
+	ps := l.Lint(lprog, conf)
+	if len(ps) != 8 {
+		t.Fatalf("got %d problems, want 8: %v", len(ps), ps)
+	}
+	for _, p := range ps {
+		if p.Position.Line != 6 || p.Text != "This is a test problem" {
+			t.Errorf("got problem %q at line %d, want only problems in fn2", p.Text, p.Position.Line)
+		}
+	}
+}
+
+// depChecker has a check that uses the results of another check.
+type depChecker struct{}
+
+func (depChecker) Name() string       { return "depchecker" }
+func (depChecker) Prefix() string     { return "TEST" }
+func (depChecker) Init(prog *Program) {}
+
+func (depChecker) Funcs() map[string]Func {
+	return map[string]Func{
+		"TEST2000": findPureFuncs,
+		"TEST2001": findPureCalls,
+	}
+}
+
+func (depChecker) Info() map[string]CheckInfo {
+	return map[string]CheckInfo{
+		"TEST2000": {OptIn: true},
+		"TEST2001": {Requires: []string{"TEST2000"}},
+	}
+}
+
+func findPureFuncs(j *Job) {
+	pure := map[string]bool{}
+	for _, f := range j.Program.Files {
+		for _, decl := range f.Decls {
+			if fn, ok := decl.(*ast.FuncDecl); ok && strings.HasPrefix(fn.Name.Name, "pure") {
+				pure[fn.Name.Name] = true
+				j.Errorf(fn, "pure function")
+			}
+		}
+	}
+	j.SetResult(pure)
+}
+
+func findPureCalls(j *Job) {
+	pure := j.Result("TEST2000").(map[string]bool)
+	for _, f := range j.Program.Files {
+		ast.Inspect(f, func(node ast.Node) bool {
+			if call, ok := node.(*ast.CallExpr); ok {
+				if ident, ok := call.Fun.(*ast.Ident); ok && pure[ident.Name] {
+					j.Errorf(call, "call to pure function")
+				}
+			}
+			return true
+		})
+	}
+}
+
+func TestRequires(t *testing.T) {
+	const src = `package pkg
+
+func pureFn() int { return 0 }
+func impureFn()   {}
+
+func fn() {
+	pureFn()
+	impureFn()
+	_ = pureFn()
+}
+`
+	conf := &loader.Config{ParserMode: parser.ParseComments}
+	f, err := conf.ParseFile("pkg.go", src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	conf.CreateFromFiles("pkg", f)
+	lprog, err := conf.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	l := &Linter{Checker: depChecker{}}
+	ps := l.Lint(lprog, conf)
+	var lines []int
+	for _, p := range ps {
+		if p.Check != "TEST2001" {
+			t.Errorf("unexpected problem from %s: %s", p.Check, p.Text)
+			continue
+		}
+		lines = append(lines, p.Position.Line)
+	}
+	if len(lines) != 2 || lines[0] != 7 || lines[1] != 9 {
+		t.Errorf("got problems on lines %v, want [7 9]", lines)
+	}
+}
+
+// slowChecker has a check that never finishes on its own.
+type slowChecker struct{}
+
+func (slowChecker) Name() string       { return "slowchecker" }
+func (slowChecker) Prefix() string     { return "TEST" }
+func (slowChecker) Init(prog *Program) {}
+
+func (slowChecker) Funcs() map[string]Func {
+	return map[string]Func{
+		"TEST3000": func(j *Job) {
+			j.Errorf(j.Program.Files[0], "problem from a slow check")
+			<-j.Context().Done()
+		},
+		"TEST3001": func(j *Job) {
+			j.Errorf(j.Program.Files[0], "problem from a dependent check")
+		},
+		"TEST3002": func(j *Job) {
+			j.Errorf(j.Program.Files[0], "problem from a fast check")
+		},
+	}
+}
+
+func (slowChecker) Info() map[string]CheckInfo {
+	return map[string]CheckInfo{
+		"TEST3001": {Requires: []string{"TEST3000"}},
+	}
+}
+
+func TestTimeout(t *testing.T) {
+	conf := &loader.Config{}
+	f, err := conf.ParseFile("pkg.go", "package pkg\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	conf.CreateFromFiles("pkg", f)
+	lprog, err := conf.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	l := &Linter{Checker: slowChecker{}, Timeout: 10 * time.Millisecond}
+	var texts []string
+	for _, p := range l.Lint(lprog, conf) {
+		texts = append(texts, p.Text)
+	}
+	want := map[string]bool{
+		"check TEST3000 was skipped because it didn't finish within 10ms":                  true,
+		"check TEST3001 was skipped because it requires check TEST3000, which was skipped": true,
+		"problem from a fast check": true,
+	}
+	if len(texts) != len(want) {
+		t.Fatalf("got problems %q, want %d problems", texts, len(want))
+	}
+	for _, text := range texts {
+		if !want[text] {
+			t.Errorf("unexpected problem %q", text)
+		}
+	}
+}
+
+// cancelingChecker has a check that cancels linting once the fast
+// check it requires has finished, and that then keeps running until
+// release is closed.
+type cancelingChecker struct {
+	cancel  context.CancelFunc
+	release chan struct{}
+}
+
+func (cancelingChecker) Name() string       { return "cancelingchecker" }
+func (cancelingChecker) Prefix() string     { return "TEST" }
+func (cancelingChecker) Init(prog *Program) {}
+
+func (c cancelingChecker) Funcs() map[string]Func {
+	return map[string]Func{
+		"TEST3000": func(j *Job) {
+			j.Errorf(j.Program.Files[0], "problem from a slow check")
+			c.cancel()
+			<-c.release
+		},
+		"TEST3001": func(j *Job) {
+			j.Errorf(j.Program.Files[0], "problem from a dependent check")
+		},
+		"TEST3002": func(j *Job) {
+			j.Errorf(j.Program.Files[0], "problem from a fast check")
+		},
+	}
+}
+
+func (cancelingChecker) Info() map[string]CheckInfo {
+	return map[string]CheckInfo{
+		"TEST3000": {Requires: []string{"TEST3002"}},
+		"TEST3001": {Requires: []string{"TEST3000"}},
+	}
+}
+
+func TestDeadline(t *testing.T) {
+	conf := &loader.Config{}
+	f, err := conf.ParseFile("pkg.go", "package pkg\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	conf.CreateFromFiles("pkg", f)
+	lprog, err := conf.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Canceling the context stands in for reaching the deadline,
+	// at a point that doesn't depend on how fast the checks run.
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	c := cancelingChecker{cancel: cancel, release: make(chan struct{})}
+	defer close(c.release)
+	l := &Linter{Checker: c, Context: ctx}
+	var texts []string
+	for _, p := range l.Lint(lprog, conf) {
+		texts = append(texts, p.Text)
+	}
+	want := map[string]bool{
+		"check TEST3000 was skipped because linting didn't finish before the deadline":     true,
+		"check TEST3001 was skipped because it requires check TEST3000, which was skipped": true,
+		"problem from a fast check": true,
+	}
+	if len(texts) != len(want) {
+		t.Fatalf("got problems %q, want %d problems", texts, len(want))
+	}
+	for _, text := range texts {
+		if !want[text] {
+			t.Errorf("unexpected problem %q", text)
+		}
+	}
+
+	// Once the deadline has passed, no checks run at all.
+	l = &Linter{Checker: slowChecker{}, Deadline: time.Now().Add(-time.Second)}
+	for _, p := range l.Lint(lprog, conf) {
+		if !strings.HasPrefix(p.Text, "check ") {
+			t.Errorf("unexpected problem %q after the deadline", p.Text)
+		}
+	}
+}
+
+func TestFailFast(t *testing.T) {
+	conf := &loader.Config{}
+	f, err := conf.ParseFile("pkg.go", "package pkg\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	conf.CreateFromFiles("pkg", f)
+	lprog, err := conf.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// without failing fast, the slow check would never finish
+	l := &Linter{Checker: slowChecker{}, FailFast: true}
+	ps := l.Lint(lprog, conf)
+	if len(ps) != 1 {
+		t.Fatalf("got %d problems, want 1", len(ps))
+	}
+	if ps[0].Text != "problem from a fast check" {
+		t.Errorf("unexpected problem %q", ps[0].Text)
+	}
+}
+
+func ExampleLinter_OnProblem() {
+	conf := &loader.Config{}
+	f, err := conf.ParseFile("pkg.go", "package pkg\n\nfunc fn1() {}\n\nfunc fn2() {}\n")
+	if err != nil {
+		log.Fatal(err)
+	}
+	conf.CreateFromFiles("pkg", f)
+	lprog, err := conf.Load()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	ch := make(chan Problem)
+	l := &Linter{
+		Checker: testChecker{},
+		// OnProblem may be called concurrently; sending on a channel
+		// is safe to do from multiple goroutines.
+		OnProblem: func(p Problem) { ch <- p },
+	}
+	go func() {
+		l.Lint(lprog, conf)
+		close(ch)
+	}()
+	for p := range ch {
+		fmt.Printf("%s: %s\n", p.Position, p.Text)
+	}
+	// Unordered output:
+	// pkg.go:3:6: This is a test problem
+	// pkg.go:5:6: This is a test problem
+}
+
+func TestTestChecks(t *testing.T) {
+	conf := &loader.Config{}
+	var files []*ast.File
+	for name, src := range map[string]string{
+		"pkg.go":      "package pkg\n\nfunc pureFn() {}\n",
+		"pkg_test.go": "package pkg\n\nfunc pureTestFn() {}\n",
+	} {
+		f, err := conf.ParseFile(name, src)
+		if err != nil {
+			t.Fatal(err)
+		}
+		files = append(files, f)
+	}
+	conf.CreateFromFiles("pkg", files...)
+	lprog, err := conf.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	problems := func(l *Linter, check string) []string {
+		var out []string
+		for _, p := range l.Lint(lprog, conf) {
+			if p.Check == check {
+				out = append(out, p.Position.Filename)
+			}
+		}
+		return out
+	}
+	l := &Linter{Checker: depChecker{}, TestEnabled: []string{"TEST2000"}}
+	if got := problems(l, "TEST2000"); len(got) != 1 || got[0] != "pkg_test.go" {
+		t.Errorf("test-only check reported problems in %v, want only in pkg_test.go", got)
+	}
+	l = &Linter{Checker: depChecker{}, Enabled: []string{"TEST2000"}, TestEnabled: []string{"TEST2000"}}
+	if got := problems(l, "TEST2000"); len(got) != 2 {
+		t.Errorf("check enabled for all files reported problems in %v, want both files", got)
+	}
+	l = &Linter{Checker: testChecker{}, TestDisabled: []string{"TEST1*"}}
+	if got := problems(l, "TEST1000"); len(got) != 1 || got[0] != "pkg.go" {
+		t.Errorf("check disabled for tests reported problems in %v, want only in pkg.go", got)
+	}
+}
+
+// fixChecker has a check that offers fixes and one that doesn't.
+type fixChecker struct{}
+
+func (fixChecker) Name() string       { return "fixchecker" }
+func (fixChecker) Prefix() string     { return "TEST" }
+func (fixChecker) Init(prog *Program) {}
+
+func (fixChecker) Funcs() map[string]Func {
+	report := func(j *Job) {
+		for _, f := range j.Program.Files {
+			j.Errorf(f.Name, "problem")
+		}
+	}
+	return map[string]Func{
+		"TEST3000": report,
+		"TEST3001": report,
+		"TEST3002": report,
+	}
+}
+
+func (fixChecker) Info() map[string]CheckInfo {
+	return map[string]CheckInfo{
+		"TEST3000": {Fixable: true},
+		"TEST3002": {Fixable: true, OptIn: true},
+	}
+}
+
+func TestOnlyFixable(t *testing.T) {
+	conf := &loader.Config{}
+	f, err := conf.ParseFile("pkg.go", "package pkg\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	conf.CreateFromFiles("pkg", f)
+	lprog, err := conf.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	checks := func(l *Linter) []string {
+		var out []string
+		for _, p := range l.Lint(lprog, conf) {
+			out = append(out, p.Check)
+		}
+		sort.Strings(out)
+		return out
+	}
+	if got, want := checks(&Linter{Checker: fixChecker{}}), []string{"TEST3000", "TEST3001"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+	if got, want := checks(&Linter{Checker: fixChecker{}, OnlyFixable: true}), []string{"TEST3000"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+	l := &Linter{Checker: fixChecker{}, OnlyFixable: true, Enabled: []string{"TEST3*"}}
+	if got, want := checks(l), []string{"TEST3000", "TEST3002"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+	if got := checks(&Linter{Checker: testChecker{}, OnlyFixable: true}); len(got) != 0 {
+		t.Errorf("got %v, want no problems from a checker without fixable checks", got)
+	}
+}
+
+// confidenceChecker has a check that reports problems with
+// differing confidences.
+type confidenceChecker struct{ fixChecker }
+
+func (confidenceChecker) Funcs() map[string]Func {
+	return map[string]Func{
+		"TEST6000": func(j *Job) {
+			for _, f := range j.Program.Files {
+				j.Errorf(f.Name, "certain")
+				j.Errorf(f.Name, "uncertain").Confidence = 0.5
+			}
+		},
+	}
+}
+
+func (confidenceChecker) Info() map[string]CheckInfo { return nil }
+
+func TestMinConfidence(t *testing.T) {
+	conf := &loader.Config{}
+	f, err := conf.ParseFile("pkg.go", "package pkg\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	conf.CreateFromFiles("pkg", f)
+	lprog, err := conf.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	messages := func(min float64) []string {
+		l := &Linter{Checker: confidenceChecker{}, MinConfidence: min}
+		var out []string
+		for _, p := range l.Lint(lprog, conf) {
+			out = append(out, p.Text)
+		}
+		sort.Strings(out)
+		return out
+	}
+	for _, tt := range []struct {
+		min  float64
+		want []string
+	}{
+		{0, []string{"certain", "uncertain"}},
+		{0.5, []string{"certain", "uncertain"}},
+		{0.6, []string{"certain"}},
+		{1, []string{"certain"}},
+	} {
+		if got := messages(tt.min); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("MinConfidence %v: got %v, want %v", tt.min, got, tt.want)
+		}
+	}
+}
+
+// releaseChecker's checks were added in different releases.
+type releaseChecker struct{}
+
+func (releaseChecker) Name() string       { return "releasechecker" }
+func (releaseChecker) Prefix() string     { return "TEST" }
+func (releaseChecker) Init(prog *Program) {}
+
+func (releaseChecker) Funcs() map[string]Func {
+	report := func(j *Job) {
+		for _, f := range j.Program.Files {
+			j.Errorf(f.Name, "problem")
+		}
+	}
+	return map[string]Func{
+		"TEST4000": report,
+		"TEST4001": report,
+		"TEST4002": report,
+		"TEST4003": report,
+		"TEST4004": report,
+	}
+}
+
+func (releaseChecker) Info() map[string]CheckInfo {
+	return map[string]CheckInfo{
+		"TEST4001": {Since: "2018.1"},
+		"TEST4002": {Since: "2019.1"},
+		"TEST4003": {Since: "2019.1.1"},
+		"TEST4004": {Since: "2019.10"},
+	}
+}
+
+func TestExcludeGenerated(t *testing.T) {
+	files := map[string]string{
+		"standard.go":  "// Code generated by gen. DO NOT EDIT.\n\npackage pkg\n\nfunc fn1() {}\n",
+		"block.go":     "/*\nCopyright\n*/\n\n// Code generated by gen. DO NOT EDIT.\npackage pkg\n\nfunc fn2() {}\n",
+		"after.go":     "package pkg\n\n// Code generated by gen. DO NOT EDIT.\nfunc fn3() {}\n",
+		"malformed.go": "// Code generated by gen. Do not edit.\npackage pkg\n\nfunc fn4() {}\n",
+		"custom.go":    "// Automatically generated by mygen\npackage pkg\n\nfunc fn5() {}\n",
+	}
+	conf := &loader.Config{ParserMode: parser.ParseComments}
+	var parsed []*ast.File
+	for name, src := range files {
+		f, err := conf.ParseFile(name, src)
+		if err != nil {
+			t.Fatal(err)
+		}
+		parsed = append(parsed, f)
+	}
+	conf.CreateFromFiles("pkg", parsed...)
+	lprog, err := conf.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	reported := func(l *Linter) []string {
+		var out []string
+		for _, p := range l.Lint(lprog, conf) {
+			out = append(out, p.Position.Filename)
+		}
+		sort.Strings(out)
+		return out
+	}
+	tests := []struct {
+		l    *Linter
+		want []string
+	}{
+		{&Linter{Checker: testChecker{}}, []string{"after.go", "block.go", "custom.go", "malformed.go", "standard.go"}},
+		{&Linter{Checker: testChecker{}, ExcludeGenerated: true}, []string{"after.go", "custom.go", "malformed.go"}},
+		{
+			&Linter{Checker: testChecker{}, ExcludeGenerated: true, GeneratedPattern: regexp.MustCompile(`^// Automatically generated by`)},
+			[]string{"after.go", "malformed.go"},
+		},
+	}
+	for i, tt := range tests {
+		if got := reported(tt.l); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%d: got problems in %v, want %v", i, got, tt.want)
+		}
+	}
+}
+
+func TestNewSince(t *testing.T) {
+	conf := &loader.Config{}
+	f, err := conf.ParseFile("pkg.go", "package pkg\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	conf.CreateFromFiles("pkg", f)
+	lprog, err := conf.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		since string
+		want  []string
+	}{
+		{"", []string{"TEST4000@", "TEST4001@2018.1", "TEST4002@2019.1", "TEST4003@2019.1.1", "TEST4004@2019.10"}},
+		{"2017.2", []string{"TEST4001@2018.1", "TEST4002@2019.1", "TEST4003@2019.1.1", "TEST4004@2019.10"}},
+		{"2018.1", []string{"TEST4002@2019.1", "TEST4003@2019.1.1", "TEST4004@2019.10"}},
+		{"2019.1", []string{"TEST4003@2019.1.1", "TEST4004@2019.10"}},
+		{"2019.1.0", []string{"TEST4003@2019.1.1", "TEST4004@2019.10"}},
+		{"2019.2", []string{"TEST4004@2019.10"}},
+		{"2019.10", nil},
+	}
+	for _, tt := range tests {
+		l := &Linter{Checker: releaseChecker{}, NewSince: tt.since}
+		var got []string
+		for _, p := range l.Lint(lprog, conf) {
+			got = append(got, p.Check+"@"+p.Since)
+		}
+		sort.Strings(got)
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("NewSince %q: got %v, want %v", tt.since, got, tt.want)
+		}
+	}
+}
+
+func TestValidRelease(t *testing.T) {
+	for _, s := range []string{"2019.1", "2019.1.1", "2019"} {
+		if !ValidRelease(s) {
+			t.Errorf("ValidRelease(%q) = false, want true", s)
+		}
+	}
+	for _, s := range []string{"", "devel", "2019.", "2019.-1", "v2019.1"} {
+		if ValidRelease(s) {
+			t.Errorf("ValidRelease(%q) = true, want false", s)
+		}
+	}
+}
+
+func TestChecks(t *testing.T) {
+	conf := &loader.Config{}
+	f, err := conf.ParseFile("pkg.go", "package pkg\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	conf.CreateFromFiles("pkg", f)
+	lprog, err := conf.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		checks []string
+		want   []string
+	}{
+		{nil, []string{"TEST4000", "TEST4001", "TEST4002", "TEST4003", "TEST4004"}},
+		{[]string{}, nil},
+		{[]string{"all", "-TEST4001"}, []string{"TEST4000", "TEST4002", "TEST4003", "TEST4004"}},
+		{[]string{"^TEST400[0-2]"}, []string{"TEST4000", "TEST4001", "TEST4002"}},
+		{[]string{"^TEST400", "-TEST4002", "-^TEST400[34]"}, []string{"TEST4000", "TEST4001"}},
+		{[]string{"-TEST4000", "TEST400*"}, []string{"TEST4000", "TEST4001", "TEST4002", "TEST4003", "TEST4004"}},
+		{[]string{"^4000"}, nil},
+	}
+	for _, tt := range tests {
+		l := &Linter{Checker: releaseChecker{}, Checks: tt.checks}
+		var got []string
+		for _, p := range l.Lint(lprog, conf) {
+			got = append(got, p.Check)
+		}
+		sort.Strings(got)
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Checks %q: got %v, want %v", tt.checks, got, tt.want)
+		}
+	}
+
+	// Selecting opt-in checks enables them.
+	l := &Linter{Checker: fixChecker{}, Checks: []string{"all"}}
+	var got []string
+	for _, p := range l.Lint(lprog, conf) {
+		got = append(got, p.Check)
+	}
+	sort.Strings(got)
+	if want := []string{"TEST3000", "TEST3001", "TEST3002"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Checks \"all\": got %v, want %v", got, want)
+	}
+}
+
+// defaultChecker has two checks that are enabled by default and that
+// flag all functions.
+type defaultChecker struct{ testChecker }
+
+func (defaultChecker) Funcs() map[string]Func {
+	return map[string]Func{
+		"TEST8000": testLint,
+		"TEST8001": testLint,
+	}
+}
+
+func (defaultChecker) Info() map[string]CheckInfo { return nil }
+
+func TestChecksWithLineEnables(t *testing.T) {
+	// A default check that Checks deselected only runs because of
+	// the line enable and mustn't be reported anywhere else.
+	const src = `package pkg
+
+func fn1() {}
+
+//lint:enable TEST8000
+func fn2() {}
+`
+	conf := &loader.Config{ParserMode: parser.ParseComments}
+	f, err := conf.ParseFile("pkg.go", src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	conf.CreateFromFiles("pkg", f)
+	lprog, err := conf.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	l := &Linter{Checker: defaultChecker{}, Checks: []string{"TEST8001"}}
+	var got []string
+	for _, p := range l.Lint(lprog, conf) {
+		got = append(got, fmt.Sprintf("%s:%d", p.Check, p.Position.Line))
+	}
+	sort.Strings(got)
+	if want := []string{"TEST8000:6", "TEST8001:3", "TEST8001:6"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestMaxPerCheck(t *testing.T) {
+	conf := &loader.Config{}
+	f, err := conf.ParseFile("pkg.go", "package pkg\n\nfunc a() {}\nfunc b() {}\nfunc c() {}\nfunc d() {}\nfunc e() {}\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	conf.CreateFromFiles("pkg", f)
+	lprog, err := conf.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		max  int
+		want []string
+	}{
+		{0, []string{"3:This is a test problem", "4:This is a test problem", "5:This is a test problem", "6:This is a test problem", "7:This is a test problem"}},
+		{2, []string{"3:This is a test problem", "4:This is a test problem", "5:(+3 more)"}},
+		{5, []string{"3:This is a test problem", "4:This is a test problem", "5:This is a test problem", "6:This is a test problem", "7:This is a test problem"}},
+	}
+	for _, tt := range tests {
+		l := &Linter{Checker: testChecker{}, MaxPerCheck: tt.max}
+		var got []string
+		for _, p := range l.Lint(lprog, conf) {
+			got = append(got, fmt.Sprintf("%d:%s", p.Position.Line, p.Text))
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("MaxPerCheck %d: got %q, want %q", tt.max, got, tt.want)
+		}
+	}
+
+	// the note doesn't repeat the related information of the problem
+	// it is placed at
+	l := &Linter{Checker: relatedChecker{}, MaxPerCheck: 1}
+	for _, p := range l.Lint(lprog, conf) {
+		if p.Text == "(+4 more)" && len(p.Related) != 0 {
+			t.Errorf("got related information %v for the note", p.Related)
+		} else if p.Text != "(+4 more)" && len(p.Related) != 1 {
+			t.Errorf("got related information %v, want one entry", p.Related)
+		}
+	}
+}
+
+// relatedChecker flags all functions, with related information.
+type relatedChecker struct{ testChecker }
+
+func (relatedChecker) Funcs() map[string]Func {
+	return map[string]Func{
+		"TEST9000": func(j *Job) {
+			for _, fn := range j.Program.InitialFunctions {
+				if fn.Synthetic == "" {
+					p := j.Errorf(fn, "problem")
+					p.Related = append(p.Related, j.Related(fn, "related"))
+				}
+			}
+		},
+	}
+}
+
+func TestCosts(t *testing.T) {
+	// with a single worker, checks run one after the other
+	defer runtime.GOMAXPROCS(runtime.GOMAXPROCS(1))
+
+	conf := &loader.Config{}
+	f, err := conf.ParseFile("pkg.go", "package pkg\n\nfunc pureFn() int { return 0 }\n\nfunc fn() { pureFn() }\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	conf.CreateFromFiles("pkg", f)
+	lprog, err := conf.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the cheapest check finds the first problem
+	for _, cheap := range []string{"TEST3000", "TEST3001"} {
+		costs := map[string]time.Duration{"TEST3000": time.Second, "TEST3001": time.Second}
+		costs[cheap] = time.Millisecond
+		l := &Linter{Checker: fixChecker{}, FailFast: true, Costs: costs}
+		ps := l.Lint(lprog, conf)
+		if len(ps) != 1 || ps[0].Check != cheap {
+			t.Errorf("got problems %v with costs %v, want a single problem of %s", ps, costs, cheap)
+		}
+	}
+
+	// requirements run first, no matter their costs
+	var order []string
+	l := &Linter{
+		Checker: depChecker{},
+		Costs:   map[string]time.Duration{"TEST2000": time.Hour, "TEST2001": time.Millisecond},
+		OnCheckDone: func(check string, d time.Duration) {
+			order = append(order, check)
+		},
+	}
+	l.Lint(lprog, conf)
+	if want := []string{"TEST2000", "TEST2001"}; !reflect.DeepEqual(order, want) {
+		t.Errorf("checks ran in order %q, want %q", order, want)
+	}
+}
+
+func TestValidateChecks(t *testing.T) {
+	for _, checks := range [][]string{nil, {"all", "-SA1019"}, {"^SA1", "-^SA10"}, {"SA9*"}} {
+		if err := ValidateChecks(checks); err != nil {
+			t.Errorf("ValidateChecks(%q) returned error %v", checks, err)
+		}
+	}
+	for _, checks := range [][]string{{"^SA(1"}, {"-"}, {""}, {"SA[1"}} {
+		if err := ValidateChecks(checks); err == nil {
+			t.Errorf("ValidateChecks(%q) succeeded, want an error", checks)
+		}
+	}
+}
+
+func TestRangeIgnore(t *testing.T) {
+	abs, err := filepath.Abs("gen.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// overlapping ranges, both of which apply to lines 5 to 10
+	ignores := []*RangeIgnore{
+		{Pattern: abs, Start: 1, End: 10, Checks: []string{"SA4006"}},
+		{Pattern: abs, Start: 5, Checks: []string{"S1000", "SA4006"}},
+	}
+	tests := []struct {
+		line    int
+		check   string
+		ignored bool
+	}{
+		{1, "SA4006", true},
+		{1, "S1000", false},
+		{5, "S1000", true},
+		{7, "SA4006", true},
+		{10, "SA1000", false},
+		{11, "SA4006", true},
+		{1000, "S1000", true},
+	}
+	for _, tt := range tests {
+		p := Problem{Position: token.Position{Filename: "gen.go", Line: tt.line}, Check: tt.check}
+		ignored := false
+		for _, ig := range ignores {
+			if ig.Match(p) {
+				ignored = true
+			}
+		}
+		if ignored != tt.ignored {
+			t.Errorf("%s at line %d: got ignored = %t, want %t", tt.check, tt.line, ignored, tt.ignored)
+		}
+	}
+	other := Problem{Position: token.Position{Filename: "other.go", Line: 5}, Check: "SA4006"}
+	if ignores[0].Match(other) || ignores[1].Match(other) {
+		t.Errorf("problem in other file was ignored")
+	}
+}
@@ -0,0 +1,66 @@
+package lintdsl
+
+import (
+	"go/ast"
+	"go/token"
+	"reflect"
+)
+
+var (
+	posType    = reflect.TypeOf(token.NoPos)
+	objectType = reflect.TypeOf((*ast.Object)(nil))
+	scopeType  = reflect.TypeOf((*ast.Scope)(nil))
+)
+
+// CloneNode returns a deep copy of the AST rooted at node, so that the
+// copy can be modified without affecting the original. If keepPos is
+// false, all positions in the copy are cleared, which causes go/printer
+// to lay out the copy as if it was newly created code.
+//
+// Resolution information, i.e. *ast.Object and *ast.Scope values, is
+// shared between the original and the copy.
+func CloneNode(node ast.Node, keepPos bool) ast.Node {
+	if node == nil {
+		return nil
+	}
+	return cloneValue(reflect.ValueOf(node), keepPos).Interface().(ast.Node)
+}
+
+func cloneValue(v reflect.Value, keepPos bool) reflect.Value {
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() || v.Type() == objectType || v.Type() == scopeType {
+			return v
+		}
+		out := reflect.New(v.Type().Elem())
+		out.Elem().Set(cloneValue(v.Elem(), keepPos))
+		return out
+	case reflect.Interface:
+		if v.IsNil() {
+			return v
+		}
+		out := reflect.New(v.Type()).Elem()
+		out.Set(cloneValue(v.Elem(), keepPos))
+		return out
+	case reflect.Slice:
+		if v.IsNil() {
+			return v
+		}
+		out := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+		for i := 0; i < v.Len(); i++ {
+			out.Index(i).Set(cloneValue(v.Index(i), keepPos))
+		}
+		return out
+	case reflect.Struct:
+		out := reflect.New(v.Type()).Elem()
+		for i := 0; i < v.NumField(); i++ {
+			out.Field(i).Set(cloneValue(v.Field(i), keepPos))
+		}
+		return out
+	default:
+		if !keepPos && v.Type() == posType {
+			return reflect.Zero(posType)
+		}
+		return v
+	}
+}
@@ -0,0 +1,71 @@
+package lintdsl
+
+import (
+	"bytes"
+	"go/ast"
+	"go/parser"
+	"go/printer"
+	"go/token"
+	"reflect"
+	"testing"
+)
+
+const cloneSrc = `package pkg
+
+// fn does things.
+func fn(x int) (int, error) {
+	if x > 0 {
+		return x * 2, nil
+	}
+	m := map[string][]int{"a": {1, 2}}
+	_ = m
+	return 0, nil
+}
+`
+
+func TestCloneNode(t *testing.T) {
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "clone.go", cloneSrc, parser.ParseComments)
+	if err != nil {
+		t.Fatal(err)
+	}
+	decl := f.Decls[0].(*ast.FuncDecl)
+	render := func(node ast.Node) string {
+		var buf bytes.Buffer
+		if err := printer.Fprint(&buf, fset, node); err != nil {
+			t.Fatal(err)
+		}
+		return buf.String()
+	}
+	orig := render(decl)
+
+	clone := CloneNode(decl, true).(*ast.FuncDecl)
+	if clone == decl || clone.Body == decl.Body {
+		t.Fatal("clone shares nodes with the original")
+	}
+	if !reflect.DeepEqual(clone, decl) {
+		t.Error("clone isn't structurally equal to the original")
+	}
+	if got := render(clone); got != orig {
+		t.Errorf("clone renders as\n%s\nwant\n%s", got, orig)
+	}
+
+	clone.Name.Name = "fn2"
+	clone.Body.List = clone.Body.List[1:]
+	ret := clone.Body.List[len(clone.Body.List)-1].(*ast.ReturnStmt)
+	ret.Results[0].(*ast.BasicLit).Value = "1"
+	if got := render(decl); got != orig {
+		t.Errorf("modifying the clone changed the original to\n%s", got)
+	}
+
+	clone = CloneNode(decl, false).(*ast.FuncDecl)
+	ast.Inspect(clone, func(node ast.Node) bool {
+		if node != nil && node.Pos().IsValid() {
+			t.Errorf("%T at %s still has a position", node, fset.Position(node.Pos()))
+		}
+		return true
+	})
+	if clone.Body.List[0].(*ast.IfStmt).Cond.(*ast.BinaryExpr).Op != token.GTR {
+		t.Error("clearing positions changed other fields")
+	}
+}
@@ -0,0 +1,73 @@
+package lintdsl
+
+import (
+	"go/ast"
+	"go/constant"
+	"go/types"
+)
+
+// Unparen returns expr with all parentheses around it removed, such
+// that ((x)) becomes x. Parentheses inside of expr, as in (a)+b, are
+// kept.
+func Unparen(expr ast.Expr) ast.Expr {
+	for {
+		paren, ok := expr.(*ast.ParenExpr)
+		if !ok {
+			return expr
+		}
+		expr = paren.X
+	}
+}
+
+// IsNilExpr reports whether expr, ignoring parentheses, is the
+// predeclared identifier nil, as opposed to a variable or constant
+// that shadows it. info has to contain the type checker's Uses for
+// expr.
+func IsNilExpr(info *types.Info, expr ast.Expr) bool {
+	ident, ok := Unparen(expr).(*ast.Ident)
+	if !ok {
+		return false
+	}
+	_, ok = info.Uses[ident].(*types.Nil)
+	return ok
+}
+
+// IsZeroValue reports whether expr, ignoring parentheses, denotes the
+// zero value of its type: a constant that is 0, false or the empty
+// string, the predeclared nil, or a struct or array literal without
+// elements, such as T{}. Empty slice and map literals, such as
+// []int{}, aren't nil and thus not zero values. Neither are
+// expressions that merely evaluate to a zero value, such as variables
+// and function calls, or the blank identifier, which has no value.
+// info has to contain the type checker's Types and Uses for expr.
+func IsZeroValue(info *types.Info, expr ast.Expr) bool {
+	expr = Unparen(expr)
+	if IsNilExpr(info, expr) {
+		return true
+	}
+	if lit, ok := expr.(*ast.CompositeLit); ok {
+		if len(lit.Elts) != 0 || info.Types[lit].Type == nil {
+			return false
+		}
+		switch info.Types[lit].Type.Underlying().(type) {
+		case *types.Struct, *types.Array:
+			return true
+		default:
+			return false
+		}
+	}
+	val := info.Types[expr].Value
+	if val == nil {
+		return false
+	}
+	switch val.Kind() {
+	case constant.Bool:
+		return !constant.BoolVal(val)
+	case constant.String:
+		return constant.StringVal(val) == ""
+	case constant.Int, constant.Float, constant.Complex:
+		return constant.Sign(val) == 0
+	default:
+		return false
+	}
+}
@@ -0,0 +1,112 @@
+package lintdsl
+
+import (
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"testing"
+)
+
+const exprSrc = `package pkg
+
+type T struct{ x int }
+
+const zero = 0
+
+func fn(v int, p *int, s []int, f func() int) {
+	var _ = (((v)))
+	var _ *int = (nil)
+	var _ = []int(nil)
+	var _ = 0
+	var _ = (0.0)
+	var _ = 0i
+	var _ = ""
+	var _ = false
+	var _ = zero
+	var _ = T{}
+	var _ = &T{}
+	var _ = []int{}
+	var _ = map[string]int{}
+	var _ = [2]int{}
+	var _ = T{x: 0}
+	var _ = 1
+	var _ = "x"
+	var _ = true
+	var _ = v
+	var _ = f()
+	var _ = (p)
+	var _ = (v) + 1
+}
+
+func shadow() {
+	nil := 0
+	var _ = nil
+}
+`
+
+func TestUnparen(t *testing.T) {
+	for _, src := range []string{"x", "(x)", "((x))", "(((x)))"} {
+		expr, err := parser.ParseExpr(src)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if id, ok := Unparen(expr).(*ast.Ident); !ok || id.Name != "x" {
+			t.Errorf("Unparen(%s) = %#v, want x", src, Unparen(expr))
+		}
+	}
+	for _, src := range []string{"(a)+b", "_", "(_)"} {
+		expr, err := parser.ParseExpr(src)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, ok := Unparen(expr).(*ast.ParenExpr); ok {
+			t.Errorf("Unparen(%s) returned a parenthesized expression", src)
+		}
+	}
+}
+
+func TestIsZeroValue(t *testing.T) {
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "expr.go", exprSrc, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	info := &types.Info{
+		Types: map[ast.Expr]types.TypeAndValue{},
+		Uses:  map[*ast.Ident]types.Object{},
+		Defs:  map[*ast.Ident]types.Object{},
+	}
+	conf := types.Config{Importer: importer.Default()}
+	if _, err := conf.Check("pkg", fset, []*ast.File{f}, info); err != nil {
+		t.Fatal(err)
+	}
+
+	// the values of all var _ = ... declarations, by line
+	values := map[int]ast.Expr{}
+	ast.Inspect(f, func(node ast.Node) bool {
+		if spec, ok := node.(*ast.ValueSpec); ok && len(spec.Values) == 1 {
+			values[fset.Position(spec.Pos()).Line] = spec.Values[0]
+		}
+		return true
+	})
+
+	nils := map[int]bool{9: true}
+	zeros := map[int]bool{9: true, 11: true, 12: true, 13: true, 14: true, 15: true, 16: true, 17: true, 21: true}
+	for line := 8; line <= 29; line++ {
+		expr := values[line]
+		if got := IsNilExpr(info, expr); got != nils[line] {
+			t.Errorf("line %d: IsNilExpr = %t, want %t", line, got, nils[line])
+		}
+		if got := IsZeroValue(info, expr); got != zeros[line] {
+			t.Errorf("line %d: IsZeroValue = %t, want %t", line, got, zeros[line])
+		}
+	}
+
+	// a variable that shadows nil is neither nil nor a zero value
+	shadowed := values[34]
+	if IsNilExpr(info, shadowed) || IsZeroValue(info, shadowed) {
+		t.Errorf("shadowed nil is treated like the predeclared nil")
+	}
+}
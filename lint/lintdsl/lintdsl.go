@@ -40,6 +40,16 @@ func CallName(call *ssa.CallCommon) string {
 func IsCallTo(call *ssa.CallCommon, name string) bool { return CallName(call) == name }
 func IsType(T types.Type, name string) bool           { return types.TypeString(T, nil) == name }
 
+func IsCallToAny(call *ssa.CallCommon, names ...string) bool {
+	q := CallName(call)
+	for _, name := range names {
+		if q == name {
+			return true
+		}
+	}
+	return false
+}
+
 func FilterDebug(instr []ssa.Instruction) []ssa.Instruction {
 	var out []ssa.Instruction
 	for _, ins := range instr {
@@ -0,0 +1,134 @@
+package lintdsl
+
+import (
+	"fmt"
+	"go/ast"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+type queryStep struct {
+	name       string
+	descendant bool
+}
+
+func parseQuery(selector string) []queryStep {
+	var steps []queryStep
+	descendant := true
+	s := selector
+	for {
+		name := s
+		i := strings.Index(s, "/")
+		if i != -1 {
+			name = s[:i]
+		}
+		if name == "" {
+			panic(fmt.Sprintf("malformed query %q", selector))
+		}
+		steps = append(steps, queryStep{name: name, descendant: descendant})
+		if i == -1 {
+			return steps
+		}
+		s = s[i+1:]
+		descendant = strings.HasPrefix(s, "/")
+		if descendant {
+			s = s[1:]
+		}
+	}
+}
+
+// NodeName returns the name of a node's type, without the package
+// qualifier, e.g. "IfStmt" for an *ast.IfStmt.
+func NodeName(node ast.Node) string {
+	T := reflect.TypeOf(node)
+	if T.Kind() == reflect.Ptr {
+		T = T.Elem()
+	}
+	return T.Name()
+}
+
+func (step queryStep) matches(node ast.Node) bool {
+	return step.name == "*" || step.name == NodeName(node)
+}
+
+// children returns the direct children of node, in source order.
+func children(node ast.Node) []ast.Node {
+	var out []ast.Node
+	ast.Inspect(node, func(n ast.Node) bool {
+		if n == node {
+			return true
+		}
+		if n != nil {
+			out = append(out, n)
+		}
+		return false
+	})
+	return out
+}
+
+// descendants returns all nodes below node, in source order,
+// excluding node itself.
+func descendants(node ast.Node) []ast.Node {
+	var out []ast.Node
+	ast.Inspect(node, func(n ast.Node) bool {
+		if n != nil && n != node {
+			out = append(out, n)
+		}
+		return true
+	})
+	return out
+}
+
+// Query returns all nodes in the tree rooted at root, including root
+// itself, that match selector, ordered by their position.
+//
+// A selector is a list of node type names, such as IfStmt or
+// CallExpr, separated by slashes. Each name has to match a direct
+// child of the node matched by the preceding name, so that
+// "FuncDecl/BlockStmt/IfStmt" matches if statements at the top level
+// of function bodies. Separating names with two slashes instead
+// matches descendants at any depth, so "FuncDecl//IfStmt" matches all
+// if statements in functions. The first name may match any node. The
+// wildcard * matches nodes of any type.
+//
+// Query panics if selector is malformed.
+func Query(root ast.Node, selector string) []ast.Node {
+	steps := parseQuery(selector)
+	set := []ast.Node{root}
+	set = append(set, descendants(root)...)
+	set = filterStep(set, steps[0])
+	for _, step := range steps[1:] {
+		seen := map[ast.Node]bool{}
+		var next []ast.Node
+		for _, node := range set {
+			var candidates []ast.Node
+			if step.descendant {
+				candidates = descendants(node)
+			} else {
+				candidates = children(node)
+			}
+			for _, c := range candidates {
+				if !seen[c] && step.matches(c) {
+					seen[c] = true
+					next = append(next, c)
+				}
+			}
+		}
+		set = next
+	}
+	sort.SliceStable(set, func(i, j int) bool {
+		return set[i].Pos() < set[j].Pos()
+	})
+	return set
+}
+
+func filterStep(nodes []ast.Node, step queryStep) []ast.Node {
+	var out []ast.Node
+	for _, node := range nodes {
+		if step.matches(node) {
+			out = append(out, node)
+		}
+	}
+	return out
+}
@@ -0,0 +1,87 @@
+package lintdsl
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"testing"
+)
+
+const querySrc = `package pkg
+
+func fn1() {
+	if true {
+		if false {
+		}
+	}
+	for {
+		if true {
+		}
+	}
+}
+
+func fn2() {
+	if true {
+	}
+}
+
+var _ = func() {
+	if true {
+	}
+}
+`
+
+func TestQuery(t *testing.T) {
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "query.go", querySrc, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := func(nodes []ast.Node) []int {
+		var out []int
+		for _, node := range nodes {
+			out = append(out, fset.Position(node.Pos()).Line)
+		}
+		return out
+	}
+
+	tests := []struct {
+		selector string
+		lines    []int
+	}{
+		{"IfStmt", []int{4, 5, 9, 15, 20}},
+		{"FuncDecl/BlockStmt/IfStmt", []int{4, 15}},
+		{"FuncDecl//IfStmt", []int{4, 5, 9, 15}},
+		{"FuncDecl/BlockStmt/*/BlockStmt/IfStmt", []int{5, 9}},
+		{"ForStmt/BlockStmt/IfStmt", []int{9}},
+		{"FuncLit//IfStmt", []int{20}},
+		{"File/FuncDecl", []int{3, 14}},
+		{"SwitchStmt", nil},
+	}
+	for _, tt := range tests {
+		got := lines(Query(f, tt.selector))
+		if len(got) != len(tt.lines) {
+			t.Errorf("Query(%q) matched lines %v, want %v", tt.selector, got, tt.lines)
+			continue
+		}
+		for i := range got {
+			if got[i] != tt.lines[i] {
+				t.Errorf("Query(%q) matched lines %v, want %v", tt.selector, got, tt.lines)
+				break
+			}
+		}
+	}
+}
+
+func TestQueryMalformed(t *testing.T) {
+	for _, selector := range []string{"", "/", "IfStmt/", "IfStmt///BlockStmt"} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("Query(%q) didn't panic", selector)
+				}
+			}()
+			Query(&ast.File{Name: &ast.Ident{}}, selector)
+		}()
+	}
+}
@@ -0,0 +1,35 @@
+package lintdsl
+
+import (
+	"go/ast"
+	"go/types"
+)
+
+// RenameIdent renames the local variable that ident declares or
+// refers to, in all of body, and returns the renamed identifiers.
+// Identifiers are matched by the objects they denote, so other
+// variables of the same name, such as ones shadowing the variable in
+// inner scopes, aren't renamed. info has to contain the type
+// checker's Defs and Uses for body, which is modified in place. If
+// ident doesn't denote a local variable, nothing is renamed.
+//
+// RenameIdent doesn't check whether the new name is already in use,
+// which would change the meaning of the code.
+func RenameIdent(info *types.Info, body ast.Node, ident *ast.Ident, name string) []*ast.Ident {
+	v, ok := info.ObjectOf(ident).(*types.Var)
+	if !ok || v.IsField() || v.Parent() == nil || v.Parent() == v.Pkg().Scope() {
+		return nil
+	}
+	var renamed []*ast.Ident
+	ast.Inspect(body, func(node ast.Node) bool {
+		id, ok := node.(*ast.Ident)
+		if ok && info.ObjectOf(id) == v {
+			renamed = append(renamed, id)
+		}
+		return true
+	})
+	for _, id := range renamed {
+		id.Name = name
+	}
+	return renamed
+}
@@ -0,0 +1,79 @@
+package lintdsl
+
+import (
+	"bytes"
+	"go/ast"
+	"go/parser"
+	"go/printer"
+	"go/token"
+	"go/types"
+	"testing"
+)
+
+const renameSrc = `package pkg
+
+var global int
+
+func fn(x int) int {
+	y := x + global
+	if y > 0 {
+		x := y * 2
+		y = x
+	}
+	f := func(x int) int { return x + y }
+	return f(x) + y
+}
+`
+
+const renamedSrc = `func fn(x int) int {
+	z := x + global
+	if z > 0 {
+		x := z * 2
+		z = x
+	}
+	f := func(x int) int { return x + z }
+	return f(x) + z
+}`
+
+func TestRenameIdent(t *testing.T) {
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "rename.go", renameSrc, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	info := &types.Info{
+		Defs: map[*ast.Ident]types.Object{},
+		Uses: map[*ast.Ident]types.Object{},
+	}
+	if _, err := (&types.Config{}).Check("pkg", fset, []*ast.File{f}, info); err != nil {
+		t.Fatal(err)
+	}
+	decl := f.Decls[1].(*ast.FuncDecl)
+	// the declaration of y
+	ident := decl.Body.List[0].(*ast.AssignStmt).Lhs[0].(*ast.Ident)
+
+	renamed := RenameIdent(info, decl, ident, "z")
+	if len(renamed) != 6 {
+		t.Errorf("renamed %d identifiers, want 6", len(renamed))
+	}
+	var buf bytes.Buffer
+	if err := printer.Fprint(&buf, fset, decl); err != nil {
+		t.Fatal(err)
+	}
+	if got := buf.String(); got != renamedSrc {
+		t.Errorf("got\n%s\nwant\n%s", got, renamedSrc)
+	}
+
+	// the parameter x, which is shadowed in the if statement and
+	// the function literal
+	param := decl.Type.Params.List[0].Names[0]
+	if n := len(RenameIdent(info, decl, param, "a")); n != 3 {
+		t.Errorf("renamed %d identifiers, want 3", n)
+	}
+
+	// package-level variables aren't renamed
+	use := decl.Body.List[0].(*ast.AssignStmt).Rhs[0].(*ast.BinaryExpr).Y.(*ast.Ident)
+	if renamed := RenameIdent(info, decl, use, "g"); renamed != nil || use.Name != "global" {
+		t.Errorf("renamed the package-level variable global")
+	}
+}
@@ -0,0 +1,83 @@
+package lintutil
+
+import (
+	"go/ast"
+
+	"honnef.co/go/tools/lint"
+	"honnef.co/go/tools/unused"
+
+	"golang.org/x/tools/go/loader"
+)
+
+// apiDecl is the extent of a declaration in the linted packages, in
+// byte offsets, and whether it is reachable from their exported API.
+type apiDecl struct {
+	start, end int
+	reachable  bool
+}
+
+// apiFilter identifies problems in declarations that aren't reachable
+// from the exported API of the linted packages, as used by -api-only.
+// Reachability is that of the unused checker, with tests, benchmarks
+// and examples not being roots.
+type apiFilter struct {
+	// decls maps file names to their declarations
+	decls map[string][]apiDecl
+}
+
+func newAPIFilter(lprog *loader.Program) *apiFilter {
+	c := unused.NewChecker(unused.CheckAll)
+	c.ExcludeTests = true
+	reachable := c.Reachable(lprog)
+	isReachable := func(pkg *loader.PackageInfo, idents ...*ast.Ident) bool {
+		for _, ident := range idents {
+			// init functions and the like don't define objects
+			// that could be unreachable
+			if obj := pkg.Defs[ident]; obj == nil || reachable[obj] {
+				return true
+			}
+		}
+		return false
+	}
+
+	f := &apiFilter{decls: map[string][]apiDecl{}}
+	for _, pkg := range lprog.InitialPackages() {
+		for _, file := range pkg.Files {
+			name := lprog.Fset.PositionFor(file.Pos(), false).Filename
+			add := func(node ast.Node, reachable bool) {
+				f.decls[name] = append(f.decls[name], apiDecl{
+					start:     lprog.Fset.PositionFor(node.Pos(), false).Offset,
+					end:       lprog.Fset.PositionFor(node.End(), false).Offset,
+					reachable: reachable,
+				})
+			}
+			for _, decl := range file.Decls {
+				switch decl := decl.(type) {
+				case *ast.FuncDecl:
+					add(decl, isReachable(pkg, decl.Name))
+				case *ast.GenDecl:
+					for _, spec := range decl.Specs {
+						switch spec := spec.(type) {
+						case *ast.TypeSpec:
+							add(spec, isReachable(pkg, spec.Name))
+						case *ast.ValueSpec:
+							add(spec, isReachable(pkg, spec.Names...))
+						}
+					}
+				}
+			}
+		}
+	}
+	return f
+}
+
+// keep reports whether p isn't in an unreachable declaration.
+// Problems outside of declarations, such as in imports, are kept.
+func (f *apiFilter) keep(p lint.Problem) bool {
+	for _, decl := range f.decls[p.Position.Filename] {
+		if p.Position.Offset >= decl.start && p.Position.Offset < decl.end {
+			return decl.reachable
+		}
+	}
+	return true
+}
@@ -0,0 +1,73 @@
+package lintutil
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"sync"
+	"time"
+)
+
+// checkCost is the entry of a check in a cost profile, as read and
+// written by -cost-profile.
+type checkCost struct {
+	// Runs is the number of runs that the average is based on
+	Runs    int           `json:"runs"`
+	Average time.Duration `json:"average"`
+}
+
+// costProfile records how long checks take to run on average. It is
+// safe for concurrent use.
+type costProfile struct {
+	mu    sync.Mutex
+	costs map[string]checkCost
+}
+
+// loadCostProfile reads the cost profile in the file name. A missing
+// file results in an empty profile.
+func loadCostProfile(name string) (*costProfile, error) {
+	prof := &costProfile{costs: map[string]checkCost{}}
+	data, err := ioutil.ReadFile(name)
+	if os.IsNotExist(err) {
+		return prof, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &prof.costs); err != nil {
+		return nil, err
+	}
+	return prof, nil
+}
+
+// averages returns the average cost of each check.
+func (prof *costProfile) averages() map[string]time.Duration {
+	prof.mu.Lock()
+	defer prof.mu.Unlock()
+	out := make(map[string]time.Duration, len(prof.costs))
+	for check, c := range prof.costs {
+		out[check] = c.Average
+	}
+	return out
+}
+
+// record adds a run of check that took d to the profile.
+func (prof *costProfile) record(check string, d time.Duration) {
+	prof.mu.Lock()
+	defer prof.mu.Unlock()
+	c := prof.costs[check]
+	c.Average = (c.Average*time.Duration(c.Runs) + d) / time.Duration(c.Runs+1)
+	c.Runs++
+	prof.costs[check] = c
+}
+
+// save writes the profile to the file name.
+func (prof *costProfile) save(name string) error {
+	prof.mu.Lock()
+	defer prof.mu.Unlock()
+	data, err := json.MarshalIndent(prof.costs, "", "\t")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(name, append(data, '\n'), 0644)
+}
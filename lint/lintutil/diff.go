@@ -0,0 +1,60 @@
+package lintutil
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"honnef.co/go/tools/lint"
+)
+
+// changedFiles returns the absolute paths of all files that differ
+// between the git revision ref and the working tree.
+func changedFiles(ref string) (map[string]bool, error) {
+	top, err := git("rev-parse", "--show-toplevel")
+	if err != nil {
+		return nil, err
+	}
+	root := strings.TrimSpace(string(top))
+	out, err := git("diff", "--name-only", "-z", ref, "--")
+	if err != nil {
+		return nil, err
+	}
+	files := map[string]bool{}
+	for _, name := range bytes.Split(out, []byte{0}) {
+		if len(name) == 0 {
+			continue
+		}
+		files[filepath.Join(root, string(name))] = true
+	}
+	return files, nil
+}
+
+func git(args ...string) ([]byte, error) {
+	cmd := exec.Command("git", args...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("git %s: %s: %s", strings.Join(args, " "), err, strings.TrimSpace(stderr.String()))
+	}
+	return out, nil
+}
+
+// filterChanged removes all problems in files that aren't in
+// changed, which has to contain absolute paths.
+func filterChanged(pss [][]lint.Problem, changed map[string]bool) {
+	for i, ps := range pss {
+		var out []lint.Problem
+		for _, p := range ps {
+			name, err := filepath.Abs(p.Position.Filename)
+			if err != nil || !changed[name] {
+				continue
+			}
+			out = append(out, p)
+		}
+		pss[i] = out
+	}
+}
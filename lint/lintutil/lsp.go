@@ -0,0 +1,209 @@
+package lintutil
+
+import (
+	"bytes"
+	"encoding/json"
+	"go/token"
+	"io"
+	"io/ioutil"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+
+	"honnef.co/go/tools/lint"
+)
+
+// LSPOutput formats problems as the diagnostics of the Language
+// Server Protocol, one JSON object per line. Files are identified by
+// file URIs, and positions are zero-based, with columns counted in
+// UTF-16 code units.
+type LSPOutput struct {
+	w io.Writer
+
+	// lines caches the lines of source files, which are needed for
+	// converting byte offsets to UTF-16 offsets
+	lines map[string][][]byte
+}
+
+type lspPosition struct {
+	Line      int `json:"line"`
+	Character int `json:"character"`
+}
+
+type lspRange struct {
+	Start lspPosition `json:"start"`
+	End   lspPosition `json:"end"`
+}
+
+type lspLocation struct {
+	URI   string   `json:"uri"`
+	Range lspRange `json:"range"`
+}
+
+type lspRelatedInformation struct {
+	Location lspLocation `json:"location"`
+	Message  string      `json:"message"`
+}
+
+type lspDiagnostic struct {
+	URI                string                  `json:"uri"`
+	Range              lspRange                `json:"range"`
+	Severity           int                     `json:"severity"`
+	Code               string                  `json:"code,omitempty"`
+	Source             string                  `json:"source"`
+	Message            string                  `json:"message"`
+	RelatedInformation []lspRelatedInformation `json:"relatedInformation,omitempty"`
+}
+
+func (o LSPOutput) Format(r lint.Report) {
+	if o.lines == nil {
+		o.lines = map[string][][]byte{}
+	}
+	enc := json.NewEncoder(o.w)
+	for _, p := range r.Problems {
+		_ = enc.Encode(o.diagnostic(p))
+	}
+}
+
+func (o LSPOutput) diagnostic(p lint.Problem) lspDiagnostic {
+	// LSP severities: 1 is error, 2 is warning, 3 is information
+	severity := 1
+	switch p.Severity {
+	case lint.SeverityWarning:
+		severity = 2
+	case lint.SeverityInfo:
+		severity = 3
+	}
+	start := o.position(p.Position)
+	end := start
+	if p.End.IsValid() {
+		end = o.position(p.End)
+	}
+	d := lspDiagnostic{
+		URI:      fileURI(p.Position.Filename),
+		Range:    lspRange{start, end},
+		Severity: severity,
+		Code:     p.Check,
+		Source:   p.Checker,
+		Message:  p.Text,
+	}
+	for _, rel := range p.Related {
+		start := o.position(rel.Position)
+		end := start
+		if rel.End.IsValid() {
+			end = o.position(rel.End)
+		}
+		d.RelatedInformation = append(d.RelatedInformation, lspRelatedInformation{
+			Location: lspLocation{fileURI(rel.Position.Filename), lspRange{start, end}},
+			Message:  rel.Text,
+		})
+	}
+	return d
+}
+
+// CodeActionOutput formats the suggested fixes of problems as
+// quick-fix code actions of the Language Server Protocol, one JSON
+// object per line and fix. Problems without fixes are omitted. The
+// edits aren't applied; positions use the same conventions as
+// LSPOutput.
+type CodeActionOutput struct {
+	w io.Writer
+}
+
+type lspTextEdit struct {
+	Range   lspRange `json:"range"`
+	NewText string   `json:"newText"`
+}
+
+type lspWorkspaceEdit struct {
+	// Changes maps file URIs to the edits in that file
+	Changes map[string][]lspTextEdit `json:"changes"`
+}
+
+type lspCodeAction struct {
+	Title       string           `json:"title"`
+	Kind        string           `json:"kind"`
+	Diagnostics []lspDiagnostic  `json:"diagnostics"`
+	Edit        lspWorkspaceEdit `json:"edit"`
+}
+
+func (o CodeActionOutput) Format(r lint.Report) {
+	lsp := LSPOutput{lines: map[string][][]byte{}}
+	enc := json.NewEncoder(o.w)
+	for _, p := range r.Problems {
+		for _, fix := range p.Fixes {
+			changes := map[string][]lspTextEdit{}
+			for _, e := range fix.Edits {
+				uri := fileURI(e.Pos.Filename)
+				changes[uri] = append(changes[uri], lspTextEdit{
+					Range:   lspRange{lsp.position(e.Pos), lsp.position(e.End)},
+					NewText: e.NewText,
+				})
+			}
+			_ = enc.Encode(lspCodeAction{
+				Title:       fix.Message,
+				Kind:        "quickfix",
+				Diagnostics: []lspDiagnostic{lsp.diagnostic(p)},
+				Edit:        lspWorkspaceEdit{Changes: changes},
+			})
+		}
+	}
+}
+
+// position converts pos to a zero-based LSP position.
+func (o LSPOutput) position(pos token.Position) lspPosition {
+	if !pos.IsValid() {
+		return lspPosition{}
+	}
+	lines, ok := o.lines[pos.Filename]
+	if !ok {
+		src, err := ioutil.ReadFile(pos.Filename)
+		if err == nil {
+			lines = bytes.Split(src, []byte("\n"))
+		}
+		o.lines[pos.Filename] = lines
+	}
+	col := pos.Column - 1
+	if pos.Line <= len(lines) {
+		col = utf16Column(lines[pos.Line-1], pos.Column)
+	}
+	return lspPosition{Line: pos.Line - 1, Character: col}
+}
+
+// utf16Column converts col, a one-based byte offset into line, to a
+// zero-based offset in UTF-16 code units.
+func utf16Column(line []byte, col int) int {
+	if col-1 < len(line) {
+		line = line[:col-1]
+	}
+	n := 0
+	for len(line) > 0 {
+		r, size := utf8.DecodeRune(line)
+		if r >= 0x10000 {
+			// encoded as a surrogate pair
+			n += 2
+		} else {
+			n++
+		}
+		line = line[size:]
+	}
+	return n
+}
+
+// fileURI returns the file URI of the named file.
+func fileURI(name string) string {
+	if name == "" {
+		return ""
+	}
+	if abs, err := filepath.Abs(name); err == nil {
+		name = abs
+	}
+	name = filepath.ToSlash(name)
+	if !strings.HasPrefix(name, "/") {
+		// Windows paths such as C:/foo
+		name = "/" + name
+	}
+	u := url.URL{Scheme: "file", Path: name}
+	return u.String()
+}
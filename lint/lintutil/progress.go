@@ -0,0 +1,97 @@
+package lintutil
+
+import (
+	"fmt"
+	"go/ast"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/tools/go/loader"
+)
+
+// progress periodically prints the status of a run to a terminal,
+// overwriting the previous status each time.
+type progress struct {
+	w io.Writer
+
+	mu     sync.Mutex
+	status string
+
+	stop chan struct{}
+	wg   sync.WaitGroup
+}
+
+func newProgress(w io.Writer) *progress {
+	p := &progress{
+		w:    w,
+		stop: make(chan struct{}),
+	}
+	p.wg.Add(1)
+	go func() {
+		defer p.wg.Done()
+		t := time.NewTicker(100 * time.Millisecond)
+		defer t.Stop()
+		for {
+			select {
+			case <-t.C:
+				p.print()
+			case <-p.stop:
+				return
+			}
+		}
+	}()
+	return p
+}
+
+func (p *progress) setStatus(format string, args ...interface{}) {
+	p.mu.Lock()
+	p.status = fmt.Sprintf(format, args...)
+	p.mu.Unlock()
+}
+
+// packageCounter returns a loader.Config.AfterTypeCheck hook that
+// reports how many of the total packages to lint, as determined by
+// linted, and how many of their dependencies have been loaded.
+// Test packages count towards the packages they test.
+func (p *progress) packageCounter(linted func(path string) bool, total int) func(info *loader.PackageInfo, files []*ast.File) {
+	var mu sync.Mutex
+	seen := map[string]bool{}
+	loaded, deps := 0, 0
+	return func(info *loader.PackageInfo, files []*ast.File) {
+		path := strings.TrimSuffix(info.Pkg.Path(), "_test")
+		mu.Lock()
+		defer mu.Unlock()
+		if seen[path] {
+			return
+		}
+		seen[path] = true
+		if linted(path) {
+			loaded++
+		} else {
+			deps++
+		}
+		p.setStatus("loading packages: %d/%d, dependencies: %d", loaded, total, deps)
+	}
+}
+
+func (p *progress) print() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	// \r returns to the start of the line, \x1b[K clears it
+	fmt.Fprintf(p.w, "\r\x1b[K%s", p.status)
+}
+
+// close stops printing the status and clears it from the terminal.
+func (p *progress) close() {
+	close(p.stop)
+	p.wg.Wait()
+	fmt.Fprint(p.w, "\r\x1b[K")
+}
+
+func isTerminal(f *os.File) bool {
+	fi, err := f.Stat()
+	return err == nil && fi.Mode()&os.ModeCharDevice != 0
+}
@@ -0,0 +1,81 @@
+package lintutil
+
+import (
+	"bytes"
+	"io/ioutil"
+
+	"honnef.co/go/tools/lint"
+)
+
+// snippetContext is the number of lines shown above and below the
+// lines a problem spans.
+const snippetContext = 1
+
+type jsonSnippet struct {
+	// StartLine is the number of the first line in Lines
+	StartLine int      `json:"start_line,omitempty"`
+	Lines     []string `json:"lines,omitempty"`
+	// Error explains why there is no snippet
+	Error string `json:"error,omitempty"`
+}
+
+// snippetReader reads the source lines that problems refer to,
+// caching the contents of files.
+type snippetReader struct {
+	lines map[string][][]byte
+	errs  map[string]error
+}
+
+func newSnippetReader() *snippetReader {
+	return &snippetReader{
+		lines: map[string][][]byte{},
+		errs:  map[string]error{},
+	}
+}
+
+func (sr *snippetReader) readLines(name string) ([][]byte, error) {
+	if lines, ok := sr.lines[name]; ok {
+		return lines, sr.errs[name]
+	}
+	src, err := ioutil.ReadFile(name)
+	var lines [][]byte
+	if err == nil {
+		lines = bytes.Split(bytes.TrimSuffix(src, []byte("\n")), []byte("\n"))
+	}
+	sr.lines[name] = lines
+	sr.errs[name] = err
+	return lines, err
+}
+
+// snippet returns the lines spanned by p, plus some context. Files
+// that changed since they were analyzed are only detected if the
+// problem's position no longer exists in them.
+func (sr *snippetReader) snippet(p lint.Problem) *jsonSnippet {
+	if !p.Position.IsValid() {
+		return nil
+	}
+	lines, err := sr.readLines(p.Position.Filename)
+	if err != nil {
+		return &jsonSnippet{Error: "couldn't read source: " + err.Error()}
+	}
+	first, last := p.Position.Line, p.Position.Line
+	if p.End.IsValid() && p.End.Filename == p.Position.Filename && p.End.Line > last {
+		last = p.End.Line
+	}
+	if last > len(lines) || p.Position.Column-1 > len(lines[first-1]) {
+		return &jsonSnippet{Error: "file has changed since it was analyzed"}
+	}
+	first -= snippetContext
+	if first < 1 {
+		first = 1
+	}
+	last += snippetContext
+	if last > len(lines) {
+		last = len(lines)
+	}
+	s := &jsonSnippet{StartLine: first}
+	for _, line := range lines[first-1 : last] {
+		s.Lines = append(s.Lines, string(line))
+	}
+	return s
+}
@@ -0,0 +1,141 @@
+package lintutil
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"honnef.co/go/tools/lint"
+)
+
+// SummaryOutput prints the number of problems found by each check,
+// instead of the problems themselves, followed by the total number of
+// problems. Checks are sorted by name, and ignored problems aren't
+// counted.
+type SummaryOutput struct {
+	w io.Writer
+	// json causes the summary to be printed as a JSON object
+	json bool
+}
+
+type summaryCheck struct {
+	Check    string `json:"check"`
+	Count    int    `json:"count"`
+	Severity string `json:"severity"`
+}
+
+type summary struct {
+	Checks []summaryCheck `json:"checks"`
+	Total  int            `json:"total"`
+}
+
+func summarize(r lint.Report) summary {
+	var counted lint.Report
+	for _, p := range r.Problems {
+		if !p.Ignored {
+			counted.Problems = append(counted.Problems, p)
+		}
+	}
+	s := summary{Checks: []summaryCheck{}}
+	for check, g := range counted.GroupByCheck() {
+		// all problems of a check normally share one severity; if
+		// they don't, report the most severe one
+		sev := g.Problems[0].Severity
+		for _, p := range g.Problems[1:] {
+			if p.Severity < sev {
+				sev = p.Severity
+			}
+		}
+		s.Checks = append(s.Checks, summaryCheck{check, len(g.Problems), sev.String()})
+		s.Total += len(g.Problems)
+	}
+	sort.Slice(s.Checks, func(i, j int) bool {
+		return s.Checks[i].Check < s.Checks[j].Check
+	})
+	return s
+}
+
+func (o SummaryOutput) Format(r lint.Report) {
+	s := summarize(r)
+	if o.json {
+		_ = json.NewEncoder(o.w).Encode(s)
+		return
+	}
+	tw := tabwriter.NewWriter(o.w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "check\tcount\tseverity")
+	for _, c := range s.Checks {
+		fmt.Fprintf(tw, "%s\t%d\t%s\n", c.Check, c.Count, c.Severity)
+	}
+	fmt.Fprintf(tw, "total\t%d\n", s.Total)
+	tw.Flush()
+}
+
+// TrendOutput prints a single JSON object that aggregates all
+// problems, meant to be stored for every run so that the number of
+// problems can be tracked over time. Problems are counted per check,
+// per severity and per top-level directory, relative to dir. Keys
+// are sorted, and ignored problems aren't counted.
+type TrendOutput struct {
+	w       io.Writer
+	dir     string
+	version string
+}
+
+// trend's fields are in the order of their keys, so that the
+// top-level keys are sorted, too.
+type trend struct {
+	Checks      map[string]int `json:"checks"`
+	Directories map[string]int `json:"directories"`
+	Severities  map[string]int `json:"severities"`
+	Total       int            `json:"total"`
+	Version     string         `json:"version"`
+}
+
+func (o TrendOutput) Format(r lint.Report) {
+	var counted lint.Report
+	for _, p := range r.Problems {
+		if !p.Ignored {
+			counted.Problems = append(counted.Problems, p)
+		}
+	}
+	t := trend{
+		Version:     o.version,
+		Total:       len(counted.Problems),
+		Checks:      map[string]int{},
+		Severities:  map[string]int{},
+		Directories: map[string]int{},
+	}
+	for check, g := range counted.GroupByCheck() {
+		t.Checks[check] = len(g.Problems)
+	}
+	for sev, g := range counted.GroupBySeverity() {
+		t.Severities[sev.String()] = len(g.Problems)
+	}
+	for file, g := range counted.GroupByFile() {
+		t.Directories[topLevelDir(o.dir, file)] += len(g.Problems)
+	}
+	// encoding/json sorts map keys, which keeps the output stable
+	_ = json.NewEncoder(o.w).Encode(t)
+}
+
+// topLevelDir returns the first element of the path of file relative
+// to dir, or "." if file is directly in dir. Files outside of dir are
+// attributed to their own directories. Relative file names are
+// relative to dir. The result always uses slashes as separators.
+func topLevelDir(dir, file string) string {
+	if !filepath.IsAbs(file) {
+		file = filepath.Join(dir, file)
+	}
+	rel, err := filepath.Rel(dir, file)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return filepath.ToSlash(filepath.Dir(file))
+	}
+	if i := strings.IndexRune(rel, filepath.Separator); i != -1 {
+		return filepath.ToSlash(rel[:i])
+	}
+	return "."
+}
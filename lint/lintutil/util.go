@@ -8,21 +8,30 @@
 package lintutil // import "honnef.co/go/tools/lint/lintutil"
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"flag"
 	"fmt"
+	"go/ast"
 	"go/build"
+	"go/importer"
 	"go/parser"
 	"go/token"
 	"go/types"
 	"io"
+	"io/ioutil"
 	"os"
 	"path/filepath"
+	"regexp"
+	"sort"
 	"strconv"
 	"strings"
+	"time"
 
+	"honnef.co/go/tools/config"
 	"honnef.co/go/tools/lint"
+	"honnef.co/go/tools/rules"
 	"honnef.co/go/tools/version"
 
 	"github.com/kisielk/gotool"
@@ -30,47 +39,186 @@ import (
 )
 
 type OutputFormatter interface {
-	Format(p lint.Problem)
+	Format(r lint.Report)
 }
 
 type TextOutput struct {
 	w io.Writer
 }
 
-func (o TextOutput) Format(p lint.Problem) {
-	fmt.Fprintf(o.w, "%v: %s\n", relativePositionString(p.Position), p.String())
+func (o TextOutput) Format(r lint.Report) {
+	for _, p := range r.Problems {
+		fmt.Fprintf(o.w, "%v: %s\n", relativePositionString(p.Position), p.String())
+		for _, rel := range p.Related {
+			fmt.Fprintf(o.w, "%v: note: %s\n", relativePositionString(rel.Position), rel.Text)
+		}
+	}
+}
+
+// FilesOutput prints the names of the files that contain problems,
+// sorted and once each, instead of the problems themselves, like
+// gofmt -l. Ignored problems aren't taken into account.
+type FilesOutput struct {
+	w io.Writer
+}
+
+func (o FilesOutput) Format(r lint.Report) {
+	for _, name := range problemFiles(r) {
+		fmt.Fprintln(o.w, name)
+	}
+}
+
+// problemFiles returns the sorted names of the files that contain
+// problems that aren't ignored.
+func problemFiles(r lint.Report) []string {
+	seen := map[string]bool{}
+	var names []string
+	for _, p := range r.Problems {
+		if p.Ignored || p.Position.Filename == "" {
+			continue
+		}
+		name := shortPath(p.Position.Filename)
+		if !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
 }
 
 type JSONOutput struct {
 	w io.Writer
+	// snippets causes the source lines of each problem to be
+	// included
+	snippets bool
+}
+
+func (o JSONOutput) Format(r lint.Report) {
+	enc := json.NewEncoder(o.w)
+	var sr *snippetReader
+	if o.snippets {
+		sr = newSnippetReader()
+	}
+	for _, p := range r.Problems {
+		var snippet *jsonSnippet
+		if sr != nil {
+			snippet = sr.snippet(p)
+		}
+		_ = enc.Encode(jsonProblem(p, snippet))
+	}
+}
+
+// GitHubActionsOutput formats problems as GitHub Actions workflow
+// commands, which get displayed as annotations of the affected lines.
+type GitHubActionsOutput struct {
+	w io.Writer
 }
 
-func (o JSONOutput) Format(p lint.Problem) {
+func (o GitHubActionsOutput) Format(r lint.Report) {
+	for _, p := range r.Problems {
+		cmd := "error"
+		switch p.Severity {
+		case lint.SeverityWarning:
+			cmd = "warning"
+		case lint.SeverityInfo:
+			cmd = "notice"
+		}
+		var props []string
+		if name := shortPath(p.Position.Filename); name != "" {
+			props = append(props, "file="+escapeWorkflowProperty(name))
+		}
+		if p.Position.IsValid() {
+			props = append(props,
+				fmt.Sprintf("line=%d", p.Position.Line),
+				fmt.Sprintf("col=%d", p.Position.Column))
+		}
+		if p.Check != "" {
+			props = append(props, "title="+escapeWorkflowProperty(p.Check))
+		}
+		if len(props) > 0 {
+			cmd += " " + strings.Join(props, ",")
+		}
+		fmt.Fprintf(o.w, "::%s::%s\n", cmd, escapeWorkflowData(p.String()))
+	}
+}
+
+var (
+	workflowDataEscaper = strings.NewReplacer(
+		"%", "%25",
+		"\r", "%0D",
+		"\n", "%0A",
+	)
+	workflowPropertyEscaper = strings.NewReplacer(
+		"%", "%25",
+		"\r", "%0D",
+		"\n", "%0A",
+		":", "%3A",
+		",", "%2C",
+	)
+)
+
+// escapeWorkflowData escapes the message of a workflow command.
+func escapeWorkflowData(s string) string { return workflowDataEscaper.Replace(s) }
+
+// escapeWorkflowProperty escapes the value of a workflow command's
+// property, which additionally can't contain colons and commas.
+func escapeWorkflowProperty(s string) string { return workflowPropertyEscaper.Replace(s) }
+
+func jsonProblem(p lint.Problem, snippet *jsonSnippet) interface{} {
 	type location struct {
 		File   string `json:"file"`
 		Line   int    `json:"line"`
 		Column int    `json:"column"`
 	}
+	type related struct {
+		Location location  `json:"location"`
+		End      *location `json:"end,omitempty"`
+		Message  string    `json:"message"`
+	}
 	jp := struct {
-		Checker  string   `json:"checker"`
-		Code     string   `json:"code"`
-		Severity string   `json:"severity,omitempty"`
-		Location location `json:"location"`
-		Message  string   `json:"message"`
-		Ignored  bool     `json:"ignored"`
+		Checker    string       `json:"checker"`
+		Code       string       `json:"code"`
+		Severity   string       `json:"severity,omitempty"`
+		Location   location     `json:"location"`
+		End        *location    `json:"end,omitempty"`
+		Message    string       `json:"message"`
+		Confidence float64      `json:"confidence"`
+		Ignored    bool         `json:"ignored"`
+		Since      string       `json:"since,omitempty"`
+		Snippet    *jsonSnippet `json:"snippet,omitempty"`
+		Related    []related    `json:"related,omitempty"`
 	}{
 		p.Checker,
 		p.Check,
-		"", // TODO(dh): support severity
+		p.Severity.String(),
 		location{
 			p.Position.Filename,
 			p.Position.Line,
 			p.Position.Column,
 		},
+		nil,
 		p.Text,
+		p.Confidence,
 		p.Ignored,
+		p.Since,
+		snippet,
+		nil,
 	}
-	_ = json.NewEncoder(o.w).Encode(jp)
+	if p.End.IsValid() {
+		jp.End = &location{p.End.Filename, p.End.Line, p.End.Column}
+	}
+	for _, rel := range p.Related {
+		jr := related{
+			Location: location{rel.Position.Filename, rel.Position.Line, rel.Position.Column},
+			Message:  rel.Text,
+		}
+		if rel.End.IsValid() {
+			jr.End = &location{rel.End.Filename, rel.End.Line, rel.End.Column}
+		}
+		jp.Related = append(jp.Related, jr)
+	}
+	return jp
 }
 func usage(name string, flags *flag.FlagSet) func() {
 	return func() {
@@ -90,6 +238,25 @@ type runner struct {
 	ignores       []lint.Ignore
 	version       int
 	returnIgnored bool
+	enabled       []string
+	checks        []string
+	testEnabled   []string
+	testDisabled  []string
+	minConfidence float64
+	progress      func(done, total int)
+	timeout       time.Duration
+	onlyFixable   bool
+	newSince      string
+	failFast      bool
+	maxPerCheck   int
+	ctx           context.Context
+	costs         map[string]time.Duration
+	onCheckDone   func(check string, d time.Duration)
+
+	excludeGenerated bool
+	generatedPattern *regexp.Regexp
+	allowedNames     map[string][]string
+	filter           func(lint.Problem) bool
 }
 
 func resolveRelative(importPaths []string, tags []string) (goFiles bool, err error) {
@@ -133,6 +300,45 @@ func parseIgnore(s string) ([]lint.Ignore, error) {
 	return out, nil
 }
 
+// selectedChecks returns the checks to run, given the value of the
+// -checks flag and the configuration. If the flag has been set, it
+// replaces the configuration's selection entirely, instead of being
+// merged with it, so that ad-hoc runs can focus on specific checks.
+// Other ways of enabling checks, such as -enable and the [tests]
+// section of the configuration, still apply.
+func selectedChecks(flag string, cfg config.Config) []string {
+	if flag == "" {
+		return cfg.Checks
+	}
+	var checks []string
+	for _, check := range strings.Split(flag, ",") {
+		if check = strings.TrimSpace(check); check != "" {
+			checks = append(checks, check)
+		}
+	}
+	return checks
+}
+
+// setFlags returns the names of the flags in fs that have been set,
+// with deprecated aliases mapped to the flags they stand for.
+func setFlags(fs *flag.FlagSet) map[string]bool {
+	set := map[string]bool{}
+	fs.Visit(func(f *flag.Flag) {
+		if f.Name == "min_confidence" {
+			set["min-confidence"] = true
+		}
+		set[f.Name] = true
+	})
+	return set
+}
+
+func splitList(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(s, ",")
+}
+
 type versionFlag int
 
 func (v *versionFlag) String() string {
@@ -161,13 +367,37 @@ func (v *versionFlag) Get() interface{} {
 func FlagSet(name string) *flag.FlagSet {
 	flags := flag.NewFlagSet("", flag.ExitOnError)
 	flags.Usage = usage(name, flags)
-	flags.Float64("min_confidence", 0, "Deprecated; use -ignore instead")
 	flags.String("tags", "", "List of `build tags`")
 	flags.String("ignore", "", "Space separated list of checks to ignore, in the following format: 'import/path/file.go:Check1,Check2,...' Both the import path and file name sections support globbing, e.g. 'os/exec/*_test.go'")
 	flags.Bool("tests", true, "Include tests")
 	flags.Bool("version", false, "Print version and exit")
 	flags.Bool("show-ignored", false, "Don't filter ignored problems")
-	flags.String("f", "text", "Output `format` (valid choices are 'text' and 'json')")
+	flags.String("enable", "", "Comma-separated list of opt-in `checks` to run. Check names support globbing, e.g. 'SA9*'")
+	flags.String("checks", "", "Comma-separated list of `checks` to run, e.g. 'all,-ST1000,^SA1', replacing the selection of the configuration file")
+	flags.Float64("min-confidence", 0, "Don't report problems with a `confidence` lower than this value, between 0 and 1")
+	flags.Var(flags.Lookup("min-confidence").Value, "min_confidence", "Deprecated; use -min-confidence instead")
+	flags.Duration("timeout", 0, "Skip checks that take longer than `duration` to run, 0 disables the timeout")
+	flags.Duration("run-timeout", 0, "Stop linting after `duration` and report the problems found so far, exiting with status 3; 0 disables the timeout")
+	flags.Bool("progress", false, "Print progress to stderr if it is a terminal")
+	flags.String("diff-from", "", "Only report problems in files that have changed since the git `revision`")
+	flags.String("fail-on", "error", "Exit with a non-zero status if there are problems of at least this `severity` (valid choices are 'error', 'warning' and 'info')")
+	flags.String("include-deps", "", "Comma-separated list of `import paths` of dependencies, such as vendored packages, to check as well. Import paths support globbing, e.g. 'github.com/foo/*'")
+	flags.Bool("api-only", false, "Only report problems in code that is reachable from the exported API of the packages")
+	flags.Bool("skip-dep-bodies", false, "Load dependencies only for their type information, without checking their function bodies")
+	flags.String("config", "", "Use the configuration `file` instead of looking for configuration files in the current directory and its parents")
+	flags.Int("max-per-check", 0, "Report at most `n` problems per check, followed by a note on how many more there are, 0 disables the limit")
+	flags.String("cost-profile", "", "Run cheap checks first, according to the average costs of checks recorded in `file`, and record the costs of this run in it")
+	flags.Bool("fail-fast", false, "Stop at the first problem and exit with a non-zero status, e.g. for pre-commit hooks")
+	flags.Bool("fix", false, "Apply suggested fixes to the source code")
+	flags.Bool("only-fixable", false, "Only run checks that can suggest fixes, e.g. in combination with -fix")
+	flags.String("new-since", "", "Only run checks that were added after `release`, such as 2019.1")
+	flags.Bool("exclude-generated", false, "Don't report problems in generated files")
+	flags.String("generated-pattern", "", "Also identify generated files by a comment line before the package clause matching `regexp`, besides the standard 'Code generated ... DO NOT EDIT.'")
+	flags.Bool("snippets", false, "Include the source lines of each problem in JSON output")
+	flags.String("cpuprofile", "", "Write a CPU profile to `file`")
+	flags.String("memprofile", "", "Write a memory profile to `file`")
+	flags.Bool("l", false, "Only list the files that contain problems, one per line, and exit with a non-zero status if there are any")
+	flags.String("f", "text", "Output `format` (valid choices are 'text', 'json', 'github-actions', 'lsp', 'code-actions', 'summary', 'summary-json' and 'trend-json')")
 
 	tags := build.Default.ReleaseTags
 	v := tags[len(tags)-1][2:]
@@ -191,54 +421,311 @@ func ProcessFlagSet(confs []CheckerConfig, fs *flag.FlagSet) {
 	tests := fs.Lookup("tests").Value.(flag.Getter).Get().(bool)
 	goVersion := fs.Lookup("go").Value.(flag.Getter).Get().(int)
 	format := fs.Lookup("f").Value.(flag.Getter).Get().(string)
+	listFiles := fs.Lookup("l").Value.(flag.Getter).Get().(bool)
+	snippets := fs.Lookup("snippets").Value.(flag.Getter).Get().(bool)
 	printVersion := fs.Lookup("version").Value.(flag.Getter).Get().(bool)
 	showIgnored := fs.Lookup("show-ignored").Value.(flag.Getter).Get().(bool)
+	enable := fs.Lookup("enable").Value.(flag.Getter).Get().(string)
+	checks := fs.Lookup("checks").Value.(flag.Getter).Get().(string)
+	minConfidence := fs.Lookup("min-confidence").Value.(flag.Getter).Get().(float64)
+	fix := fs.Lookup("fix").Value.(flag.Getter).Get().(bool)
+	failFast := fs.Lookup("fail-fast").Value.(flag.Getter).Get().(bool)
+	costProfile := fs.Lookup("cost-profile").Value.(flag.Getter).Get().(string)
+	maxPerCheck := fs.Lookup("max-per-check").Value.(flag.Getter).Get().(int)
+	onlyFixable := fs.Lookup("only-fixable").Value.(flag.Getter).Get().(bool)
+	newSince := fs.Lookup("new-since").Value.(flag.Getter).Get().(string)
+	excludeGenerated := fs.Lookup("exclude-generated").Value.(flag.Getter).Get().(bool)
+	generatedPattern := fs.Lookup("generated-pattern").Value.(flag.Getter).Get().(string)
+	showProgress := fs.Lookup("progress").Value.(flag.Getter).Get().(bool)
+	timeout := fs.Lookup("timeout").Value.(flag.Getter).Get().(time.Duration)
+	runTimeout := fs.Lookup("run-timeout").Value.(flag.Getter).Get().(time.Duration)
+	skipDepBodies := fs.Lookup("skip-dep-bodies").Value.(flag.Getter).Get().(bool)
+	apiOnly := fs.Lookup("api-only").Value.(flag.Getter).Get().(bool)
+	includeDeps := fs.Lookup("include-deps").Value.(flag.Getter).Get().(string)
+	diffFrom := fs.Lookup("diff-from").Value.(flag.Getter).Get().(string)
+	failOnName := fs.Lookup("fail-on").Value.(flag.Getter).Get().(string)
+	configFile := fs.Lookup("config").Value.(flag.Getter).Get().(string)
+	cpuProfile := fs.Lookup("cpuprofile").Value.(flag.Getter).Get().(string)
+	memProfile := fs.Lookup("memprofile").Value.(flag.Getter).Get().(string)
 
 	if printVersion {
 		version.Print()
 		os.Exit(0)
 	}
 
+	// The run timeout includes loading packages.
+	var deadline time.Time
+	if runTimeout > 0 {
+		deadline = time.Now().Add(runTimeout)
+	}
+
+	stopProfiling, err := startProfiling(cpuProfile, memProfile)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	// exit has to be used instead of os.Exit, so that profiles
+	// get written
+	exit := func(code int) {
+		stopProfiling()
+		os.Exit(code)
+	}
+
+	failOn, err := lint.ParseSeverity(failOnName)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		exit(2)
+	}
+	if newSince != "" && !lint.ValidRelease(newSince) {
+		fmt.Fprintf(os.Stderr, "invalid release %q\n", newSince)
+		exit(2)
+	}
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		exit(1)
+	}
+	cfg, err := loadConfig(cwd, configFile)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		exit(1)
+	}
+	// Flags that have been set take precedence over the
+	// configuration, even when they are set to their defaults.
+	set := setFlags(fs)
+	if !set["max-per-check"] && cfg.MaxPerCheck != nil {
+		maxPerCheck = *cfg.MaxPerCheck
+	}
+	if !set["min-confidence"] && cfg.MinConfidence != nil {
+		minConfidence = *cfg.MinConfidence
+	}
+	if !set["exclude-generated"] && cfg.ExcludeGenerated != nil {
+		excludeGenerated = *cfg.ExcludeGenerated
+	}
+	genPattern := cfg.GeneratedPattern
+	if set["generated-pattern"] {
+		genPattern = nil
+	}
+	if generatedPattern != "" {
+		genPattern, err = regexp.Compile(generatedPattern)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "invalid generated-pattern: %s\n", err)
+			exit(2)
+		}
+	}
+	var changed map[string]bool
+	if diffFrom != "" {
+		changed, err = changedFiles(diffFrom)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			exit(1)
+		}
+	}
+
+	var progressWriter io.Writer
+	if showProgress && isTerminal(os.Stderr) {
+		progressWriter = os.Stderr
+	}
+
 	var cs []lint.Checker
 	for _, conf := range confs {
 		cs = append(cs, conf.Checker)
 	}
+	if len(cfg.Rules) > 0 {
+		// Lint runs the rules as an additional checker, whose
+		// problems are errors. Don't modify the caller's slice.
+		confs = append(confs[:len(confs):len(confs)], CheckerConfig{ExitNonZero: true})
+	}
 	pss, err := Lint(cs, fs.Args(), &Options{
 		Tags:          strings.Fields(tags),
 		LintTests:     tests,
 		Ignores:       ignore,
 		GoVersion:     goVersion,
 		ReturnIgnored: showIgnored,
+		Enabled:       splitList(enable),
+		Checks:        selectedChecks(checks, cfg),
+		TestEnabled:   cfg.TestEnabled,
+		TestDisabled:  cfg.TestDisabled,
+		RangeIgnores:  cfg.Ignores,
+		MinConfidence: minConfidence,
+		Progress:      progressWriter,
+		Timeout:       timeout,
+		OnlyFixable:   onlyFixable,
+		NewSince:      newSince,
+		FailFast:      failFast,
+		MaxPerCheck:   maxPerCheck,
+		CostProfile:   costProfile,
+		APIOnly:       apiOnly,
+		Deadline:      deadline,
+
+		SkipDependencyBodies: skipDepBodies,
+		IncludeDependencies:  splitList(includeDeps),
+		ExcludeGenerated:     excludeGenerated,
+		GeneratedPattern:     genPattern,
+		AllowedNames:         cfg.AllowedNames,
+		Rules:                cfg.Rules,
 	})
-	if err != nil {
+	incomplete, _ := err.(*IncompleteError)
+	if err != nil && incomplete == nil {
 		fmt.Fprintln(os.Stderr, err)
-		os.Exit(1)
+		exit(1)
 	}
 
-	var ps []lint.Problem
-	for _, p := range pss {
-		ps = append(ps, p...)
+	if diffFrom != "" {
+		// Packages still have to be loaded in full, but problems
+		// in unchanged files are of no interest.
+		filterChanged(pss, changed)
 	}
+	applySeverities(pss, confs, cfg)
+	report := newReport(pss)
 
 	var f OutputFormatter
 	switch format {
 	case "text":
 		f = TextOutput{os.Stdout}
 	case "json":
-		f = JSONOutput{os.Stdout}
+		f = JSONOutput{w: os.Stdout, snippets: snippets}
+	case "github-actions":
+		f = GitHubActionsOutput{os.Stdout}
+	case "lsp":
+		f = LSPOutput{w: os.Stdout}
+	case "code-actions":
+		f = CodeActionOutput{w: os.Stdout}
+	case "summary":
+		f = SummaryOutput{w: os.Stdout}
+	case "summary-json":
+		f = SummaryOutput{w: os.Stdout, json: true}
+	case "trend-json":
+		f = TrendOutput{w: os.Stdout, dir: cwd, version: version.Version}
 	default:
 		fmt.Fprintf(os.Stderr, "unsupported output format %q\n", format)
-		os.Exit(2)
+		exit(2)
+	}
+	if listFiles {
+		f = FilesOutput{os.Stdout}
 	}
 
-	for _, p := range ps {
-		f.Format(p)
+	f.Format(report)
+	if fix {
+		if err := applyFixes(report); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			exit(1)
+		}
+	}
+	status := exitStatus(report, failOn, failFast)
+	if listFiles && len(problemFiles(report)) > 0 {
+		status = 1
+	}
+	if incomplete != nil {
+		fmt.Fprintln(os.Stderr, incomplete)
+		status = 3
+	}
+	stopProfiling()
+	if status != 0 {
+		os.Exit(status)
+	}
+}
+
+// loadConfig loads the configuration file name, together with the
+// ignore file in the same directory, or if name is empty, the
+// configuration and ignore files that apply to dir.
+func loadConfig(dir, name string) (config.Config, error) {
+	if name == "" {
+		return config.Load(dir)
+	}
+	cfg, err := config.ParseFile(name)
+	if err != nil {
+		return config.Config{}, err
+	}
+	ignores, err := config.ParseIgnoreFile(filepath.Join(filepath.Dir(name), config.IgnoreFileName))
+	if err != nil && !os.IsNotExist(err) {
+		return config.Config{}, err
+	}
+	cfg.Ignores = ignores
+	return cfg, nil
+}
+
+// newReport returns a report of the problems found by all checkers,
+// sorted by position, so that all output formats are deterministic.
+func newReport(pss [][]lint.Problem) lint.Report {
+	var r lint.Report
+	for _, ps := range pss {
+		r.Problems = append(r.Problems, ps...)
 	}
-	for i, p := range pss {
-		if len(p) != 0 && confs[i].ExitNonZero {
-			os.Exit(1)
+	r.SortByPosition()
+	return r
+}
+
+// applyFixes applies the suggested fixes of all problems that
+// haven't been ignored, rewriting the affected files.
+func applyFixes(r lint.Report) error {
+	files := map[string][]lint.SuggestedFix{}
+	var names []string
+	for _, p := range r.Problems {
+		if p.Ignored {
+			continue
+		}
+		for _, fix := range p.Fixes {
+			if len(fix.Edits) == 0 {
+				continue
+			}
+			name := fix.Edits[0].Pos.Filename
+			if _, ok := files[name]; !ok {
+				names = append(names, name)
+			}
+			files[name] = append(files[name], fix)
 		}
 	}
+	for _, name := range names {
+		src, err := ioutil.ReadFile(name)
+		if err != nil {
+			return err
+		}
+		out, _, err := lint.ApplyFixes(src, files[name])
+		if err != nil {
+			return fmt.Errorf("couldn't fix %s: %s", name, err)
+		}
+		if err := ioutil.WriteFile(name, out, 0644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// applySeverities sets the severity of all problems, based on the
+// default severity of the checker that found them and the overrides
+// in the configuration. Notes about skipped checks are warnings by
+// default.
+func applySeverities(pss [][]lint.Problem, confs []CheckerConfig, cfg config.Config) {
+	for i, ps := range pss {
+		def := lint.SeverityWarning
+		if confs[i].ExitNonZero {
+			def = lint.SeverityError
+		}
+		for j := range ps {
+			ps[j].Severity = def
+			if ps[j].Check == lint.CheckSkipped {
+				ps[j].Severity = lint.SeverityWarning
+			}
+			if sev, ok := cfg.Severity[ps[j].Check]; ok {
+				ps[j].Severity = sev
+			}
+		}
+	}
+}
+
+// exitStatus returns 1 if there are problems at least as severe as
+// failOn, and 0 otherwise. When failing fast, any problem results in
+// 1, as linting stopped before more severe problems could be found.
+func exitStatus(r lint.Report, failOn lint.Severity, failFast bool) int {
+	if failFast && len(r.Problems) > 0 {
+		return 1
+	}
+	if len(r.FilterBySeverity(failOn).Problems) > 0 {
+		return 1
+	}
+	return 0
 }
 
 type Options struct {
@@ -247,6 +734,104 @@ type Options struct {
 	Ignores       string
 	GoVersion     int
 	ReturnIgnored bool
+	Enabled       []string
+	// Checks, if not nil, selects the checks to run; see
+	// lint.Linter.Checks.
+	Checks []string
+	// TestEnabled and TestDisabled control the checks for test
+	// files; see lint.Linter.
+	TestEnabled  []string
+	TestDisabled []string
+	// RangeIgnores are ignores loaded from ignore files, in addition
+	// to those in Ignores.
+	RangeIgnores  []*lint.RangeIgnore
+	MinConfidence float64
+	// Progress, if set, is where the progress of a run is printed
+	// to. It should be a terminal.
+	Progress io.Writer
+	// Timeout limits how long each check may run; see
+	// lint.Linter.Timeout.
+	Timeout time.Duration
+	// OnlyFixable causes only checks that can suggest fixes to run.
+	OnlyFixable bool
+	// NewSince causes only checks added after the release NewSince
+	// to run; see lint.Linter.NewSince.
+	NewSince string
+	// FailFast causes linting to stop at the first problem, without
+	// running the remaining checkers; see lint.Linter.FailFast.
+	FailFast bool
+	// MaxPerCheck limits the number of problems reported for each
+	// check; see lint.Linter.MaxPerCheck.
+	MaxPerCheck int
+	// ExcludeGenerated causes problems in generated files to be
+	// discarded, although they are still analyzed.
+	// GeneratedPattern, if not nil, identifies generated files in
+	// addition to the standard pattern; see lint.Linter.
+	ExcludeGenerated bool
+	GeneratedPattern *regexp.Regexp
+	// AllowedNames maps checks to the identifier names they
+	// shouldn't report problems about; see lint.Linter.AllowedNames.
+	AllowedNames map[string][]string
+	// Rules maps the names of custom checks to their rules, in the
+	// pattern language of package rules. The rules are run by an
+	// additional checker, whose problems come after those of the
+	// checkers that are passed in.
+	Rules map[string]string
+	// SkipDependencyBodies causes dependencies of the linted
+	// packages to be loaded only for their type information, without
+	// type-checking their function bodies. This makes loading faster,
+	// but checks that rely on the implementations of functions in
+	// dependencies, such as whether they are pure, may find fewer
+	// problems.
+	SkipDependencyBodies bool
+	// IncludeDependencies lists import paths of dependencies that
+	// are checked like the packages being linted, for example to
+	// audit a vendored package. Import paths support globbing, and
+	// vendored packages also match by their path inside the vendor
+	// directory. Packages in the standard library are never
+	// included. It is ignored when linting a list of files.
+	IncludeDependencies []string
+	// CostProfile, if not empty, names a file that records how long
+	// each check takes to run on average. Checks are run cheapest
+	// first according to the file, which is useful together with
+	// FailFast, and the costs of this run are added to it. The file
+	// is created if it doesn't exist.
+	CostProfile string
+	// APIOnly causes only problems in code that is reachable from
+	// the exported API of the linted packages to be reported, which
+	// helps with auditing a library's API. Reachability is that of
+	// the unused checker: exported package-level declarations,
+	// init functions and, in package main, the main function are
+	// roots, and anything they refer to, directly or indirectly, is
+	// reachable. Tests, benchmarks and examples aren't roots, so
+	// that problems in them and in helpers only they use are
+	// discarded. Problems outside of declarations, such as in
+	// imports, are always reported.
+	APIOnly bool
+	// Deadline, if not zero, is when linting has to stop. Loading
+	// stops looking for further packages, which fails loading,
+	// checks that are still running are skipped, and checkers that
+	// haven't started don't run. Linting then returns the problems
+	// found so far together with an *IncompleteError.
+	Deadline time.Time
+
+	// ctx, if not nil, stops linting when it is canceled, like
+	// reaching the Deadline. Tests use it to stop linting at a
+	// point that doesn't depend on how fast linting is.
+	ctx context.Context
+}
+
+// context returns the context that is canceled once linting has to
+// stop, because of opt.Deadline or opt.ctx.
+func (opt *Options) context() (context.Context, context.CancelFunc) {
+	ctx := opt.ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if opt.Deadline.IsZero() {
+		return context.WithCancel(ctx)
+	}
+	return context.WithDeadline(ctx, opt.Deadline)
 }
 
 func Lint(cs []lint.Checker, pkgs []string, opt *Options) ([][]lint.Problem, error) {
@@ -257,11 +842,71 @@ func Lint(cs []lint.Checker, pkgs []string, opt *Options) ([][]lint.Problem, err
 	if err != nil {
 		return nil, err
 	}
+	if err := lint.ValidateChecks(opt.Checks); err != nil {
+		return nil, err
+	}
+	cs, err = withRules(cs, opt)
+	if err != nil {
+		return nil, err
+	}
+	for _, ig := range opt.RangeIgnores {
+		ignores = append(ignores, ig)
+	}
+	var costs *costProfile
+	if opt.CostProfile != "" {
+		costs, err = loadCostProfile(opt.CostProfile)
+		if err != nil {
+			return nil, err
+		}
+	}
 	paths := gotool.ImportPaths(pkgs)
 	goFiles, err := resolveRelative(paths, opt.Tags)
 	if err != nil {
 		return nil, err
 	}
+	var pr *progress
+	if opt.Progress != nil {
+		pr = newProgress(opt.Progress)
+		defer pr.close()
+		pr.setStatus("loading packages")
+	}
+	stop, cancel := opt.context()
+	defer cancel()
+	conf := newLoaderConfig(stop, paths, goFiles, opt, pr)
+	lprog, err := conf.Load()
+	if err != nil {
+		if stop.Err() != nil {
+			// None of the checkers ran, but the problems still
+			// have to be aligned with them.
+			var names []string
+			for _, c := range cs {
+				names = append(names, c.Name())
+			}
+			return make([][]lint.Problem, len(cs)), &IncompleteError{Checkers: names, Packages: paths}
+		}
+		return nil, err
+	}
+	return lintProgram(stop, cs, lprog, conf, ignores, opt, costs, pr)
+}
+
+// withRules returns cs followed by a checker for opt.Rules, if there
+// are any. The caller's slice isn't modified.
+func withRules(cs []lint.Checker, opt *Options) ([]lint.Checker, error) {
+	if len(opt.Rules) == 0 {
+		return cs, nil
+	}
+	rc, err := rules.NewChecker(opt.Rules)
+	if err != nil {
+		return nil, err
+	}
+	return append(cs[:len(cs):len(cs)], rc), nil
+}
+
+// newLoaderConfig returns the loader configuration used for loading
+// paths. If goFiles is true, paths are treated as a list of files
+// making up a single package. If pr isn't nil, the number of loaded
+// packages is reported to it.
+func newLoaderConfig(stop context.Context, paths []string, goFiles bool, opt *Options, pr *progress) *loader.Config {
 	ctx := build.Default
 	ctx.BuildTags = opt.Tags
 	hadError := false
@@ -272,8 +917,9 @@ func Lint(cs []lint.Checker, pkgs []string, opt *Options) ([][]lint.Problem, err
 		TypeChecker: types.Config{
 			Sizes: types.SizesFor(ctx.Compiler, ctx.GOARCH),
 			Error: func(err error) {
-				// Only print the first error found
-				if hadError {
+				// Only print the first error found, and none
+				// caused by stopping
+				if hadError || stop.Err() != nil {
 					return
 				}
 				hadError = true
@@ -281,32 +927,474 @@ func Lint(cs []lint.Checker, pkgs []string, opt *Options) ([][]lint.Problem, err
 			},
 		},
 	}
+	// Packages are found one at a time while loading, which is
+	// where loading can be stopped.
+	conf.FindPackage = func(ctxt *build.Context, importPath, fromDir string, mode build.ImportMode) (*build.Package, error) {
+		if err := stop.Err(); err != nil {
+			return nil, err
+		}
+		return ctxt.Import(importPath, fromDir, mode)
+	}
 	if goFiles {
 		conf.CreateFromFilenames("adhoc", paths...)
 	} else {
 		for _, path := range paths {
 			conf.ImportPkgs[path] = opt.LintTests
 		}
+		if len(opt.IncludeDependencies) > 0 {
+			for _, path := range includedDependencies(&ctx, paths, opt) {
+				conf.ImportPkgs[path] = false
+			}
+		}
 	}
-	lprog, err := conf.Load()
+	linted := func(path string) bool {
+		if goFiles {
+			return path == "adhoc"
+		}
+		_, ok := conf.ImportPkgs[strings.TrimSuffix(path, "_test")]
+		return ok
+	}
+	var hooks []func(info *loader.PackageInfo, files []*ast.File)
+	if opt.SkipDependencyBodies {
+		conf.TypeCheckFuncBodies = linted
+		hooks = append(hooks, func(info *loader.PackageInfo, files []*ast.File) {
+			if !linted(info.Pkg.Path()) {
+				stripBodies(files)
+			}
+		})
+	}
+	if pr != nil {
+		total := len(conf.ImportPkgs)
+		if goFiles {
+			total = 1
+		}
+		hooks = append(hooks, pr.packageCounter(linted, total))
+	}
+	if len(hooks) > 0 {
+		conf.AfterTypeCheck = func(info *loader.PackageInfo, files []*ast.File) {
+			for _, hook := range hooks {
+				hook(info, files)
+			}
+		}
+	}
+	return conf
+}
+
+// includedDependencies returns the import paths of the dependencies
+// of paths that match opt.IncludeDependencies. Packages that can't be
+// imported are skipped; the loader reports their errors.
+func includedDependencies(ctx *build.Context, paths []string, opt *Options) []string {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return nil
+	}
+	seen := map[string]bool{}
+	for _, path := range paths {
+		seen[path] = true
+	}
+	var out []string
+	var visit func(imports []string, dir string)
+	visit = func(imports []string, dir string) {
+		for _, imp := range imports {
+			if imp == "C" || imp == "unsafe" {
+				continue
+			}
+			bp, err := ctx.Import(imp, dir, 0)
+			if err != nil || bp.Goroot || seen[bp.ImportPath] {
+				continue
+			}
+			seen[bp.ImportPath] = true
+			if matchDependency(bp.ImportPath, opt.IncludeDependencies) {
+				out = append(out, bp.ImportPath)
+			}
+			visit(bp.Imports, bp.Dir)
+		}
+	}
+	for _, path := range paths {
+		bp, err := ctx.Import(path, cwd, 0)
+		if err != nil {
+			continue
+		}
+		visit(bp.Imports, bp.Dir)
+		if opt.LintTests {
+			visit(bp.TestImports, bp.Dir)
+			visit(bp.XTestImports, bp.Dir)
+		}
+	}
+	return out
+}
+
+// matchDependency reports whether the import path matches any of the
+// patterns. Vendored packages also match by the path following the
+// innermost vendor directory.
+func matchDependency(path string, patterns []string) bool {
+	names := []string{path}
+	if i := strings.LastIndex(path, "/vendor/"); i != -1 {
+		names = append(names, path[i+len("/vendor/"):])
+	} else if strings.HasPrefix(path, "vendor/") {
+		names = append(names, strings.TrimPrefix(path, "vendor/"))
+	}
+	for _, pattern := range patterns {
+		for _, name := range names {
+			if m, _ := filepath.Match(pattern, name); m {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// stripBodies removes the bodies of all functions in files, which
+// haven't been type-checked, so that they don't get converted to SSA.
+// Function declarations become external functions. Function literals
+// need a body, which gets replaced by an empty infinite loop, as it
+// doesn't need any type information.
+func stripBodies(files []*ast.File) {
+	for _, f := range files {
+		ast.Inspect(f, func(node ast.Node) bool {
+			switch node := node.(type) {
+			case *ast.FuncDecl:
+				node.Body = nil
+			case *ast.FuncLit:
+				node.Body = &ast.BlockStmt{
+					Lbrace: node.Body.Lbrace,
+					List: []ast.Stmt{&ast.ForStmt{
+						For:  node.Body.Lbrace,
+						Body: &ast.BlockStmt{Lbrace: node.Body.Lbrace, Rbrace: node.Body.Rbrace},
+					}},
+					Rbrace: node.Body.Rbrace,
+				}
+				return false
+			}
+			return true
+		})
+	}
+}
+
+// LintProgram runs the checkers on a program that has already been
+// loaded, skipping the loading phase performed by Lint. This allows
+// tools that embed the linters to reuse their own loader.Program.
+//
+// The program has to be complete: it needs a FileSet, and each of the
+// initial packages, which are the ones that will be checked, has to
+// have been parsed with comments and fully type-checked, with all
+// maps in its types.Info populated. All transitive dependencies of
+// the initial packages have to appear in AllPackages, although they
+// do not need syntax. The Tags and LintTests options are ignored, as
+// they only affect loading.
+func LintProgram(cs []lint.Checker, lprog *loader.Program, opt *Options) ([][]lint.Problem, error) {
+	if opt == nil {
+		opt = &Options{}
+	}
+	ctx := build.Default
+	ctx.BuildTags = opt.Tags
+	return lintLoadedProgram(cs, lprog, &ctx, opt)
+}
+
+// lintLoadedProgram implements LintProgram. ctx is used for finding
+// the directories of the linted packages.
+func lintLoadedProgram(cs []lint.Checker, lprog *loader.Program, ctx *build.Context, opt *Options) ([][]lint.Problem, error) {
+	ignores, err := parseIgnore(opt.Ignores)
 	if err != nil {
 		return nil, err
 	}
+	if err := lint.ValidateChecks(opt.Checks); err != nil {
+		return nil, err
+	}
+	cs, err = withRules(cs, opt)
+	if err != nil {
+		return nil, err
+	}
+	for _, ig := range opt.RangeIgnores {
+		ignores = append(ignores, ig)
+	}
+	var costs *costProfile
+	if opt.CostProfile != "" {
+		costs, err = loadCostProfile(opt.CostProfile)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if err := validateProgram(lprog); err != nil {
+		return nil, err
+	}
+	conf := &loader.Config{
+		Build: ctx,
+		Fset:  lprog.Fset,
+		TypeChecker: types.Config{
+			Sizes: types.SizesFor(ctx.Compiler, ctx.GOARCH),
+		},
+	}
+	var pr *progress
+	if opt.Progress != nil {
+		pr = newProgress(opt.Progress)
+		defer pr.close()
+	}
+	stop, cancel := opt.context()
+	defer cancel()
+	return lintProgram(stop, cs, lprog, conf, ignores, opt, costs, pr)
+}
+
+// LintFiles runs the checkers on a single package made up of files,
+// which don't have to exist on disk, for example because they have
+// been generated in memory. The files have to have been parsed, or
+// constructed, with comments, and their positions have to belong to
+// fset.
+//
+// If pkg and info are nil, the files are type-checked in isolation,
+// resolving imports with importer.Default. Otherwise, they have to
+// be the result of fully type-checking the files, with all maps in
+// info populated. The Tags and LintTests options are ignored.
+func LintFiles(cs []lint.Checker, fset *token.FileSet, files []*ast.File, pkg *types.Package, info *types.Info, opt *Options) ([][]lint.Problem, error) {
+	if fset == nil {
+		return nil, errors.New("missing FileSet")
+	}
+	if len(files) == 0 {
+		return nil, errors.New("no files")
+	}
+	if (pkg == nil) != (info == nil) {
+		return nil, errors.New("type information requires both a package and its types.Info")
+	}
+	if opt == nil {
+		opt = &Options{}
+	}
+	ctx := inMemoryContext()
+	sizes := types.SizesFor(ctx.Compiler, ctx.GOARCH)
+	if pkg == nil {
+		info = &types.Info{
+			Types:      map[ast.Expr]types.TypeAndValue{},
+			Defs:       map[*ast.Ident]types.Object{},
+			Uses:       map[*ast.Ident]types.Object{},
+			Implicits:  map[ast.Node]types.Object{},
+			Selections: map[*ast.SelectorExpr]*types.Selection{},
+			Scopes:     map[ast.Node]*types.Scope{},
+		}
+		tc := &types.Config{
+			Importer: importer.Default(),
+			Sizes:    sizes,
+		}
+		var err error
+		pkg, err = tc.Check(files[0].Name.Name, fset, files, info)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	pkginfo := &loader.PackageInfo{
+		Pkg:                   pkg,
+		Importable:            true,
+		TransitivelyErrorFree: true,
+		Files:                 files,
+		Info:                  *info,
+	}
+	lprog := &loader.Program{
+		Fset:        fset,
+		Created:     []*loader.PackageInfo{pkginfo},
+		Imported:    map[string]*loader.PackageInfo{},
+		AllPackages: map[*types.Package]*loader.PackageInfo{pkg: pkginfo},
+	}
+	// dependencies only provide type information
+	var addImports func(pkg *types.Package)
+	addImports = func(pkg *types.Package) {
+		for _, imp := range pkg.Imports() {
+			if _, ok := lprog.AllPackages[imp]; ok {
+				continue
+			}
+			lprog.AllPackages[imp] = &loader.PackageInfo{
+				Pkg:                   imp,
+				Importable:            true,
+				TransitivelyErrorFree: true,
+			}
+			addImports(imp)
+		}
+	}
+	addImports(pkg)
+	return lintLoadedProgram(cs, lprog, ctx, opt)
+}
 
+// inMemoryContext returns a copy of build.Default that doesn't find
+// any directories, so that looking up the directories of packages
+// linted by LintFiles doesn't access the file system.
+func inMemoryContext() *build.Context {
+	ctx := build.Default
+	ctx.IsDir = func(string) bool { return false }
+	ctx.HasSubdir = func(root, dir string) (string, bool) { return "", false }
+	ctx.ReadDir = func(dir string) ([]os.FileInfo, error) {
+		return nil, &os.PathError{Op: "readdir", Path: dir, Err: os.ErrNotExist}
+	}
+	ctx.OpenFile = func(path string) (io.ReadCloser, error) {
+		return nil, &os.PathError{Op: "open", Path: path, Err: os.ErrNotExist}
+	}
+	return &ctx
+}
+
+func validateProgram(lprog *loader.Program) error {
+	if lprog == nil {
+		return errors.New("incomplete program: program is nil")
+	}
+	if lprog.Fset == nil {
+		return errors.New("incomplete program: missing FileSet")
+	}
+	initial := lprog.InitialPackages()
+	if len(initial) == 0 {
+		return errors.New("incomplete program: no initial packages")
+	}
+	for _, pkginfo := range initial {
+		if pkginfo.Pkg == nil {
+			return errors.New("incomplete program: initial package has no types.Package")
+		}
+		path := pkginfo.Pkg.Path()
+		if !pkginfo.Pkg.Complete() {
+			return fmt.Errorf("incomplete program: package %s hasn't been fully type-checked", path)
+		}
+		if len(pkginfo.Files) == 0 {
+			return fmt.Errorf("incomplete program: package %s has no syntax", path)
+		}
+		info := pkginfo.Info
+		if info.Types == nil || info.Defs == nil || info.Uses == nil ||
+			info.Implicits == nil || info.Selections == nil || info.Scopes == nil {
+			return fmt.Errorf("incomplete program: package %s is missing type information", path)
+		}
+	}
+	seen := map[*types.Package]bool{}
+	var checkImports func(pkg *types.Package) error
+	checkImports = func(pkg *types.Package) error {
+		for _, imp := range pkg.Imports() {
+			if seen[imp] {
+				continue
+			}
+			seen[imp] = true
+			if _, ok := lprog.AllPackages[imp]; !ok {
+				return fmt.Errorf("incomplete program: dependency %s of %s is missing from AllPackages", imp.Path(), pkg.Path())
+			}
+			if err := checkImports(imp); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	for _, pkginfo := range initial {
+		if err := checkImports(pkginfo.Pkg); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// lintProgram runs the checkers on lprog until stop is canceled. If
+// costs isn't nil, it schedules the checks and records their costs,
+// and is saved to opt.CostProfile afterwards.
+func lintProgram(stop context.Context, cs []lint.Checker, lprog *loader.Program, conf *loader.Config, ignores []lint.Ignore, opt *Options, costs *costProfile, pr *progress) ([][]lint.Problem, error) {
+	var filter func(lint.Problem) bool
+	if opt.APIOnly {
+		filter = newAPIFilter(lprog).keep
+	}
+	// pastDeadline reports whether linting has to stop
+	pastDeadline := func() bool {
+		return stop.Err() != nil
+	}
+	var incomplete []string
 	var problems [][]lint.Problem
 	for _, c := range cs {
+		if pastDeadline() {
+			// keep the problems aligned with the checkers
+			problems = append(problems, nil)
+			incomplete = append(incomplete, c.Name())
+			continue
+		}
+		var progress func(done, total int)
+		if pr != nil {
+			name := c.Name()
+			pkgs := len(lprog.InitialPackages())
+			pr.setStatus("%s: preparing %d packages", name, pkgs)
+			progress = func(done, total int) {
+				pr.setStatus("%s: ran %d/%d checks on %d packages", name, done, total, pkgs)
+			}
+		}
 		runner := &runner{
 			checker:       c,
 			tags:          opt.Tags,
 			ignores:       ignores,
 			version:       opt.GoVersion,
 			returnIgnored: opt.ReturnIgnored,
+			enabled:       opt.Enabled,
+			checks:        opt.Checks,
+			testEnabled:   opt.TestEnabled,
+			testDisabled:  opt.TestDisabled,
+			minConfidence: opt.MinConfidence,
+			progress:      progress,
+			timeout:       opt.Timeout,
+			onlyFixable:   opt.OnlyFixable,
+			newSince:      opt.NewSince,
+			failFast:      opt.FailFast,
+			maxPerCheck:   opt.MaxPerCheck,
+			ctx:           stop,
+
+			excludeGenerated: opt.ExcludeGenerated,
+			generatedPattern: opt.GeneratedPattern,
+			allowedNames:     opt.AllowedNames,
+			filter:           filter,
+		}
+		if costs != nil {
+			runner.costs = costs.averages()
+			runner.onCheckDone = costs.record
+		}
+		ps := runner.lint(lprog, conf)
+		problems = append(problems, ps)
+		if pastDeadline() {
+			incomplete = append(incomplete, c.Name())
+		}
+		if opt.FailFast && hasUnignored(ps) {
+			break
+		}
+	}
+	if costs != nil {
+		if err := costs.save(opt.CostProfile); err != nil {
+			return nil, err
 		}
-		problems = append(problems, runner.lint(lprog, conf))
+	}
+	if incomplete != nil {
+		// Checks analyze all packages at once, so an unfinished
+		// check hasn't covered any of them.
+		var pkgs []string
+		for _, info := range lprog.InitialPackages() {
+			pkgs = append(pkgs, info.Pkg.Path())
+		}
+		sort.Strings(pkgs)
+		return problems, &IncompleteError{Checkers: incomplete, Packages: pkgs}
 	}
 	return problems, nil
 }
 
+// IncompleteError is returned, together with the problems that have
+// been found, when linting didn't finish before Options.Deadline.
+type IncompleteError struct {
+	// Checkers lists the checkers that didn't finish. Their skipped
+	// checks are reported as problems, checkers that didn't start
+	// at all have no problems.
+	Checkers []string
+	// Packages lists the packages being linted, which the checkers
+	// in Checkers haven't fully analyzed, as checks analyze all
+	// packages at once. If loading was stopped, it lists them as
+	// they were passed to Lint.
+	Packages []string
+}
+
+func (err *IncompleteError) Error() string {
+	return fmt.Sprintf("linting didn't finish before the deadline, results of %s for %s are incomplete",
+		strings.Join(err.Checkers, ", "), strings.Join(err.Packages, ", "))
+}
+
+// hasUnignored reports whether any of ps isn't ignored.
+func hasUnignored(ps []lint.Problem) bool {
+	for _, p := range ps {
+		if !p.Ignored {
+			return true
+		}
+	}
+	return false
+}
+
 func shortPath(path string) string {
 	cwd, err := os.Getwd()
 	if err != nil {
@@ -345,6 +1433,25 @@ func (runner *runner) lint(lprog *loader.Program, conf *loader.Config) []lint.Pr
 		Ignores:       runner.ignores,
 		GoVersion:     runner.version,
 		ReturnIgnored: runner.returnIgnored,
+		Enabled:       runner.enabled,
+		Checks:        runner.checks,
+		TestEnabled:   runner.testEnabled,
+		TestDisabled:  runner.testDisabled,
+		MinConfidence: runner.minConfidence,
+		Progress:      runner.progress,
+		Timeout:       runner.timeout,
+		OnlyFixable:   runner.onlyFixable,
+		NewSince:      runner.newSince,
+		FailFast:      runner.failFast,
+		MaxPerCheck:   runner.maxPerCheck,
+		Context:       runner.ctx,
+		Costs:         runner.costs,
+		OnCheckDone:   runner.onCheckDone,
+
+		ExcludeGenerated: runner.excludeGenerated,
+		GeneratedPattern: runner.generatedPattern,
+		AllowedNames:     runner.allowedNames,
+		Filter:           runner.filter,
 	}
 	return l.Lint(lprog, conf)
 }
@@ -0,0 +1,1216 @@
+package lintutil
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"flag"
+	"go/ast"
+	"go/build"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"honnef.co/go/tools/config"
+	"honnef.co/go/tools/lint"
+	"honnef.co/go/tools/ssa"
+	"honnef.co/go/tools/stylecheck"
+)
+
+func TestSeverityOverrides(t *testing.T) {
+	confs := []CheckerConfig{
+		{ExitNonZero: true},
+		{ExitNonZero: false},
+	}
+	problems := func() [][]lint.Problem {
+		return [][]lint.Problem{
+			{{Check: "SA1000"}},
+			{{Check: "S1000"}},
+		}
+	}
+	tests := []struct {
+		name     string
+		severity map[string]lint.Severity
+		status   int
+	}{
+		{"defaults", nil, 1},
+		{"demotion", map[string]lint.Severity{"SA1000": lint.SeverityWarning}, 0},
+		{"promotion", map[string]lint.Severity{"SA1000": lint.SeverityInfo, "S1000": lint.SeverityError}, 1},
+	}
+	for _, tt := range tests {
+		pss := problems()
+		applySeverities(pss, confs, config.Config{Severity: tt.severity})
+		var r lint.Report
+		for _, ps := range pss {
+			r.Problems = append(r.Problems, ps...)
+		}
+		if status := exitStatus(r, lint.SeverityError, false); status != tt.status {
+			t.Errorf("%s: got exit status %d, want %d", tt.name, status, tt.status)
+		}
+	}
+
+	// Notes about skipped checks are warnings, even from checkers
+	// that exit non-zero, unless configured otherwise.
+	for _, sev := range []lint.Severity{lint.SeverityWarning, lint.SeverityError} {
+		pss := [][]lint.Problem{{{Check: lint.CheckSkipped}}}
+		cfg := config.Config{}
+		if sev != lint.SeverityWarning {
+			cfg.Severity = map[string]lint.Severity{lint.CheckSkipped: sev}
+		}
+		applySeverities(pss, confs[:1], cfg)
+		if got := pss[0][0].Severity; got != sev {
+			t.Errorf("got severity %s for a skipped check, want %s", got, sev)
+		}
+	}
+}
+
+func TestLoadConfig(t *testing.T) {
+	dir, err := ioutil.TempDir("", "config")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	files := map[string]string{
+		"staticcheck.conf":    "[severity]\nSA1000 = info\nS1000 = info\n",
+		"ci/staticcheck.toml": "[severity]\nSA1000 = \"error\"\n",
+	}
+	for name, src := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(path, []byte(src), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cfg, err := loadConfig(dir, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Severity["SA1000"] != lint.SeverityInfo || cfg.Severity["S1000"] != lint.SeverityInfo {
+		t.Errorf("got severities %v from the discovered configuration", cfg.Severity)
+	}
+
+	// an explicit configuration file replaces discovered ones,
+	// instead of being merged with them
+	cfg, err = loadConfig(dir, filepath.Join(dir, "ci", "staticcheck.toml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]lint.Severity{"SA1000": lint.SeverityError}
+	if !reflect.DeepEqual(cfg.Severity, want) {
+		t.Errorf("got severities %v, want %v", cfg.Severity, want)
+	}
+
+	if _, err := loadConfig(dir, filepath.Join(dir, "missing.conf")); err == nil {
+		t.Errorf("loading a missing configuration file succeeded")
+	}
+}
+
+func TestRules(t *testing.T) {
+	dir, err := ioutil.TempDir("", "config")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	conf := "[rules]\nR1000 = report CallExpr where Fun == \"println\": \"don't print\"\n"
+	if err := ioutil.WriteFile(filepath.Join(dir, "staticcheck.conf"), []byte(conf), 0644); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := loadConfig(dir, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	src := "package a\n\nfunc f() {\n\tprintln(\"x\")\n}\n"
+	check := func(t *testing.T, pss [][]lint.Problem, filename string) {
+		// the problems of the rules come after those of the
+		// checkers that were passed in
+		if len(pss) != 2 {
+			t.Fatalf("got problems of %d checkers, want 2", len(pss))
+		}
+		if len(pss[1]) != 1 {
+			t.Fatalf("got problems %v from the rules, want a single problem", pss[1])
+		}
+		p := pss[1][0]
+		if p.Check != "R1000" || p.Text != "don't print" || filepath.Base(p.Position.Filename) != filename || p.Position.Line != 4 {
+			t.Errorf("got problem %s %q at %s, want R1000 \"don't print\" at %s:4", p.Check, p.Text, p.Position, filename)
+		}
+	}
+
+	t.Run("Lint", func(t *testing.T) {
+		_, cleanup := tempGOPATH(t, map[string]string{"a/a.go": src})
+		defer cleanup()
+		pss, err := Lint([]lint.Checker{funcChecker{}}, []string{"a"}, &Options{Rules: cfg.Rules})
+		if err != nil {
+			t.Fatal(err)
+		}
+		check(t, pss, "a.go")
+	})
+
+	t.Run("LintFiles", func(t *testing.T) {
+		fset := token.NewFileSet()
+		f, err := parser.ParseFile(fset, "a.go", src, parser.ParseComments)
+		if err != nil {
+			t.Fatal(err)
+		}
+		pss, err := LintFiles([]lint.Checker{funcChecker{}}, fset, []*ast.File{f}, nil, nil, &Options{Rules: cfg.Rules})
+		if err != nil {
+			t.Fatal(err)
+		}
+		check(t, pss, "a.go")
+	})
+
+	if _, err := Lint(nil, []string{"a"}, &Options{Rules: map[string]string{"R1000": "report Call"}}); err == nil {
+		t.Error("Lint accepted an invalid rule")
+	}
+}
+
+func TestChecksFlag(t *testing.T) {
+	cfg, err := config.Parse("test.conf", strings.NewReader("[checks]\nselect = all, -TEST1000\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if checks := selectedChecks("", cfg); !reflect.DeepEqual(checks, cfg.Checks) {
+		t.Errorf("got checks %q without the flag, want those of the configuration, %q", checks, cfg.Checks)
+	}
+	// the flag replaces the configuration's selection instead of
+	// being merged with it
+	if checks, want := selectedChecks("TEST1000, -ST*", cfg), []string{"TEST1000", "-ST*"}; !reflect.DeepEqual(checks, want) {
+		t.Errorf("got checks %q, want %q", checks, want)
+	}
+
+	_, cleanup := tempGOPATH(t, map[string]string{
+		"a/a.go": "package a\n\nfunc A() {}\n",
+	})
+	defer cleanup()
+	for _, tt := range []struct {
+		flag string
+		want int
+	}{
+		{"", 0},
+		{"TEST1000", 1},
+	} {
+		pss, err := Lint([]lint.Checker{funcChecker{}}, []string{"a"}, &Options{Checks: selectedChecks(tt.flag, cfg)})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(pss) != 1 || len(pss[0]) != tt.want {
+			t.Errorf("got problems %v with -checks=%q, want %d", pss, tt.flag, tt.want)
+		}
+	}
+}
+
+func TestMinConfidenceAlias(t *testing.T) {
+	fs := FlagSet("test")
+	if err := fs.Parse([]string{"-min_confidence", "0.6"}); err != nil {
+		t.Fatal(err)
+	}
+	if got := fs.Lookup("min-confidence").Value.(flag.Getter).Get().(float64); got != 0.6 {
+		t.Errorf("got -min-confidence %v after setting -min_confidence, want 0.6", got)
+	}
+}
+
+func TestSetFlags(t *testing.T) {
+	fs := FlagSet("test")
+	if err := fs.Parse([]string{"-max-per-check", "0", "-min_confidence", "0.6", "-exclude-generated=false"}); err != nil {
+		t.Fatal(err)
+	}
+	set := setFlags(fs)
+	// flags set to their defaults still override the configuration
+	for _, name := range []string{"max-per-check", "min-confidence", "exclude-generated"} {
+		if !set[name] {
+			t.Errorf("-%s isn't reported as set", name)
+		}
+	}
+	if set["generated-pattern"] {
+		t.Error("-generated-pattern is reported as set")
+	}
+}
+
+func TestFailOn(t *testing.T) {
+	r := lint.Report{Problems: []lint.Problem{
+		{Check: "SA1000", Severity: lint.SeverityWarning},
+		{Check: "S1000", Severity: lint.SeverityInfo},
+	}}
+	tests := []struct {
+		failOn lint.Severity
+		status int
+	}{
+		{lint.SeverityError, 0},
+		{lint.SeverityWarning, 1},
+		{lint.SeverityInfo, 1},
+	}
+	for _, tt := range tests {
+		if status := exitStatus(r, tt.failOn, false); status != tt.status {
+			t.Errorf("-fail-on %s: got exit status %d, want %d", tt.failOn, status, tt.status)
+		}
+	}
+
+	r = lint.Report{Problems: []lint.Problem{{Check: "S1000", Severity: lint.SeverityInfo}}}
+	if status := exitStatus(r, lint.SeverityWarning, false); status != 0 {
+		t.Errorf("-fail-on warning with only info problems: got exit status %d, want 0", status)
+	}
+}
+
+func TestFilterChanged(t *testing.T) {
+	problem := func(file string) lint.Problem {
+		return lint.Problem{Position: token.Position{Filename: file, Line: 1}}
+	}
+	pss := [][]lint.Problem{
+		{problem("/src/pkg/a.go"), problem("/src/pkg/b.go")},
+		{problem("/src/pkg/b.go"), problem("/src/pkg/c.go")},
+	}
+	changed := map[string]bool{
+		"/src/pkg/b.go":   true,
+		"/src/other/c.go": true,
+	}
+	filterChanged(pss, changed)
+	for i, ps := range pss {
+		if len(ps) != 1 || ps[0].Position.Filename != "/src/pkg/b.go" {
+			t.Errorf("checker %d: got %v, want only problems in /src/pkg/b.go", i, ps)
+		}
+	}
+}
+
+func TestGitHubActionsOutput(t *testing.T) {
+	r := lint.Report{Problems: []lint.Problem{
+		{
+			Position: token.Position{Filename: "/src/a,b:c.go", Line: 3, Column: 7},
+			Text:     "100% wrong\nreally",
+			Check:    "SA1000",
+			Severity: lint.SeverityError,
+		},
+		{
+			Position: token.Position{Filename: "/src/d.go", Line: 1, Column: 1},
+			Text:     "a warning",
+			Check:    "SA1001",
+			Severity: lint.SeverityWarning,
+		},
+		{
+			Text:     "a notice",
+			Severity: lint.SeverityInfo,
+		},
+	}}
+	var buf bytes.Buffer
+	GitHubActionsOutput{&buf}.Format(r)
+	want := "::error file=/src/a%2Cb%3Ac.go,line=3,col=7,title=SA1000::100%25 wrong%0Areally (SA1000)\n" +
+		"::warning file=/src/d.go,line=1,col=1,title=SA1001::a warning (SA1001)\n" +
+		"::notice::a notice\n"
+	if got := buf.String(); got != want {
+		t.Errorf("got\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestUTF16Column(t *testing.T) {
+	tests := []struct {
+		line string
+		col  int
+		want int
+	}{
+		{"x := 1", 1, 0},
+		{"x := 1", 6, 5},
+		{"s := \"é\"; x", 12, 10},
+		{"s := \"😀\"; x", 14, 11},
+		{"s := \"日本\"; x", 16, 11},
+		// columns past the end of the line
+		{"abc", 5, 3},
+	}
+	for _, tt := range tests {
+		if got := utf16Column([]byte(tt.line), tt.col); got != tt.want {
+			t.Errorf("utf16Column(%q, %d) = %d, want %d", tt.line, tt.col, got, tt.want)
+		}
+	}
+}
+
+func TestLSPOutput(t *testing.T) {
+	dir, err := ioutil.TempDir("", "lsp")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	name := filepath.Join(dir, "a b.go")
+	src := "package pkg\n\nvar s = \"😀\" + x\n"
+	if err := ioutil.WriteFile(name, []byte(src), 0644); err != nil {
+		t.Fatal(err)
+	}
+	r := lint.Report{Problems: []lint.Problem{{
+		// the byte column of x
+		Position: token.Position{Filename: name, Line: 3, Column: 18},
+		Text:     "a problem",
+		Check:    "SA1000",
+		Checker:  "staticcheck",
+		Severity: lint.SeverityWarning,
+	}, {
+		// the string literal, which ends before x
+		Position: token.Position{Filename: name, Line: 3, Column: 9},
+		End:      token.Position{Filename: name, Line: 3, Column: 15},
+		Text:     "another problem",
+		Check:    "SA1001",
+		Checker:  "staticcheck",
+		Severity: lint.SeverityError,
+	}}}
+	var buf bytes.Buffer
+	LSPOutput{w: &buf}.Format(r)
+	uri := `"uri":"file://` + filepath.ToSlash(dir) + `/a%20b.go"`
+	want := `{` + uri + `,"range":{"start":{"line":2,"character":15},"end":{"line":2,"character":15}},"severity":2,"code":"SA1000","source":"staticcheck","message":"a problem"}` + "\n" +
+		`{` + uri + `,"range":{"start":{"line":2,"character":8},"end":{"line":2,"character":12}},"severity":1,"code":"SA1001","source":"staticcheck","message":"another problem"}` + "\n"
+	if got := buf.String(); got != want {
+		t.Errorf("got\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestCodeActionOutput(t *testing.T) {
+	dir, err := ioutil.TempDir("", "codeactions")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	name := filepath.Join(dir, "a.go")
+	src := "package pkg\n\nvar s = \"😀\"[0:len(\"😀\")]\n"
+	if err := ioutil.WriteFile(name, []byte(src), 0644); err != nil {
+		t.Fatal(err)
+	}
+	pos := func(line, col int) token.Position {
+		return token.Position{Filename: name, Line: line, Column: col}
+	}
+	r := lint.Report{Problems: []lint.Problem{
+		{
+			// the byte columns of 0 and of the closing bracket
+			Position: pos(3, 16),
+			Text:     "should omit both indices",
+			Check:    "S1010",
+			Checker:  "gosimple",
+			Severity: lint.SeverityWarning,
+			Fixes: []lint.SuggestedFix{{
+				Message: "omit both indices",
+				Edits:   []lint.TextEdit{{Pos: pos(3, 16), End: pos(3, 29), NewText: ":"}},
+			}},
+		},
+		{
+			Position: pos(3, 1),
+			Text:     "a problem without a fix",
+			Check:    "SA1000",
+			Checker:  "staticcheck",
+			Severity: lint.SeverityError,
+		},
+	}}
+	var buf bytes.Buffer
+	CodeActionOutput{w: &buf}.Format(r)
+	uri := "file://" + filepath.ToSlash(dir) + "/a.go"
+	want := `{"title":"omit both indices","kind":"quickfix",` +
+		`"diagnostics":[{"uri":"` + uri + `","range":{"start":{"line":2,"character":13},"end":{"line":2,"character":13}},"severity":2,"code":"S1010","source":"gosimple","message":"should omit both indices"}],` +
+		`"edit":{"changes":{"` + uri + `":[{"range":{"start":{"line":2,"character":13},"end":{"line":2,"character":24}},"newText":":"}]}}}` + "\n"
+	if got := buf.String(); got != want {
+		t.Errorf("got\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestJSONSnippets(t *testing.T) {
+	dir, err := ioutil.TempDir("", "snippets")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	name := filepath.Join(dir, "a.go")
+	src := "package pkg\n\nfunc fn() {\n\tprintln()\n}\n"
+	if err := ioutil.WriteFile(name, []byte(src), 0644); err != nil {
+		t.Fatal(err)
+	}
+	pos := func(line, col int) token.Position {
+		return token.Position{Filename: name, Line: line, Column: col}
+	}
+	tests := []struct {
+		name string
+		p    lint.Problem
+		want *jsonSnippet
+	}{
+		{"single line", lint.Problem{Position: pos(4, 2)},
+			&jsonSnippet{StartLine: 3, Lines: []string{"func fn() {", "\tprintln()", "}"}}},
+		{"multiple lines", lint.Problem{Position: pos(3, 1), End: pos(5, 2)},
+			&jsonSnippet{StartLine: 2, Lines: []string{"", "func fn() {", "\tprintln()", "}"}}},
+		{"first line", lint.Problem{Position: pos(1, 1), End: pos(1, 12)},
+			&jsonSnippet{StartLine: 1, Lines: []string{"package pkg", ""}}},
+		{"changed file", lint.Problem{Position: pos(4, 2), End: pos(9, 2)},
+			&jsonSnippet{Error: "file has changed since it was analyzed"}},
+		{"changed line", lint.Problem{Position: pos(5, 10)},
+			&jsonSnippet{Error: "file has changed since it was analyzed"}},
+		{"no position", lint.Problem{}, nil},
+	}
+	sr := newSnippetReader()
+	for _, tt := range tests {
+		if got := sr.snippet(tt.p); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: got %+v, want %+v", tt.name, got, tt.want)
+		}
+	}
+
+	missing := lint.Problem{Position: token.Position{Filename: filepath.Join(dir, "b.go"), Line: 1, Column: 1}}
+	if got := sr.snippet(missing); got == nil || got.Error == "" {
+		t.Errorf("got %+v for a missing file, want an error", got)
+	}
+
+	var buf bytes.Buffer
+	JSONOutput{w: &buf, snippets: true}.Format(lint.Report{Problems: []lint.Problem{tests[0].p}})
+	if want := `"snippet":{"start_line":3,"lines":["func fn() {","\tprintln()","}"]}`; !strings.Contains(buf.String(), want) {
+		t.Errorf("got %s, want it to contain %s", buf.String(), want)
+	}
+}
+
+func TestRelatedInformation(t *testing.T) {
+	pos := func(line, col int) token.Position {
+		return token.Position{Filename: "/src/a.go", Line: line, Column: col}
+	}
+	r := lint.Report{Problems: []lint.Problem{{
+		Position: pos(3, 2),
+		Text:     "argument x is overwritten before first use",
+		Check:    "SA4009",
+		Checker:  "staticcheck",
+		Related: []lint.RelatedInformation{
+			{Position: pos(5, 2), End: pos(5, 7), Text: "x is overwritten here"},
+		},
+	}}}
+
+	var buf bytes.Buffer
+	TextOutput{&buf}.Format(r)
+	want := "/src/a.go:3:2: argument x is overwritten before first use (SA4009)\n/src/a.go:5:2: note: x is overwritten here\n"
+	if got := buf.String(); got != want {
+		t.Errorf("got\n%s\nwant\n%s", got, want)
+	}
+
+	buf.Reset()
+	JSONOutput{w: &buf}.Format(r)
+	want = `"related":[{"location":{"file":"/src/a.go","line":5,"column":2},"end":{"file":"/src/a.go","line":5,"column":7},"message":"x is overwritten here"}]`
+	if got := buf.String(); !strings.Contains(got, want) {
+		t.Errorf("got %s, want it to contain %s", got, want)
+	}
+
+	buf.Reset()
+	LSPOutput{w: &buf}.Format(r)
+	want = `"relatedInformation":[{"location":{"uri":"file:///src/a.go","range":{"start":{"line":4,"character":1},"end":{"line":4,"character":6}}},"message":"x is overwritten here"}]`
+	if got := buf.String(); !strings.Contains(got, want) {
+		t.Errorf("got %s, want it to contain %s", got, want)
+	}
+}
+
+func TestFilesOutput(t *testing.T) {
+	pos := func(name string, line int) token.Position {
+		return token.Position{Filename: name, Line: line, Column: 1}
+	}
+	r := lint.Report{Problems: []lint.Problem{
+		{Position: pos("/src/b.go", 3), Check: "SA4006"},
+		{Position: pos("/src/a.go", 10), Check: "SA1000"},
+		{Position: pos("/src/b.go", 1), Check: "S1000"},
+		{Position: pos("/src/a.go", 2), Check: "SA4006"},
+		{Position: pos("/src/c.go", 1), Check: "S1000", Ignored: true},
+	}}
+	var buf bytes.Buffer
+	FilesOutput{&buf}.Format(r)
+	want := "/src/a.go\n/src/b.go\n"
+	if got := buf.String(); got != want {
+		t.Errorf("got\n%s\nwant\n%s", got, want)
+	}
+
+	buf.Reset()
+	FilesOutput{&buf}.Format(lint.Report{})
+	if got := buf.String(); got != "" {
+		t.Errorf("got %q for an empty report, want no output", got)
+	}
+}
+
+func TestSummaryOutput(t *testing.T) {
+	r := lint.Report{Problems: []lint.Problem{
+		{Check: "SA4006", Severity: lint.SeverityWarning},
+		{Check: "SA1000", Severity: lint.SeverityError},
+		{Check: "SA4006", Severity: lint.SeverityWarning},
+		{Check: "S1000", Severity: lint.SeverityInfo},
+		{Check: "S1000", Severity: lint.SeverityInfo, Ignored: true},
+	}}
+	var buf bytes.Buffer
+	SummaryOutput{w: &buf}.Format(r)
+	want := `check   count  severity
+S1000   1      info
+SA1000  1      error
+SA4006  2      warning
+total   4
+`
+	if got := buf.String(); got != want {
+		t.Errorf("got\n%s\nwant\n%s", got, want)
+	}
+
+	buf.Reset()
+	SummaryOutput{w: &buf, json: true}.Format(r)
+	want = `{"checks":[{"check":"S1000","count":1,"severity":"info"},{"check":"SA1000","count":1,"severity":"error"},{"check":"SA4006","count":2,"severity":"warning"}],"total":4}` + "\n"
+	if got := buf.String(); got != want {
+		t.Errorf("got\n%s\nwant\n%s", got, want)
+	}
+
+	buf.Reset()
+	SummaryOutput{w: &buf, json: true}.Format(lint.Report{})
+	want = `{"checks":[],"total":0}` + "\n"
+	if got := buf.String(); got != want {
+		t.Errorf("got\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestTrendOutput(t *testing.T) {
+	dir := filepath.FromSlash("/src/project")
+	pos := func(file string) token.Position {
+		return token.Position{Filename: filepath.FromSlash(file), Line: 1, Column: 1}
+	}
+	r := lint.Report{Problems: []lint.Problem{
+		{Position: pos("/src/project/a/x.go"), Check: "SA4006", Severity: lint.SeverityWarning},
+		{Position: pos("/src/project/a/b/y.go"), Check: "SA1000", Severity: lint.SeverityError},
+		{Position: pos("/src/project/main.go"), Check: "SA4006", Severity: lint.SeverityWarning},
+		{Position: pos("/src/project/c/z.go"), Check: "S1000", Severity: lint.SeverityInfo},
+		{Position: pos("c/z.go"), Check: "S1000", Severity: lint.SeverityInfo},
+		{Position: pos("/src/project/c/z.go"), Check: "S1000", Severity: lint.SeverityInfo, Ignored: true},
+		{Position: pos("/usr/lib/go/src/fmt/print.go"), Check: "ST1000", Severity: lint.SeverityWarning},
+	}}
+	var buf bytes.Buffer
+	TrendOutput{w: &buf, dir: dir, version: "2019.1"}.Format(r)
+	var got map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]interface{}{
+		"version": "2019.1",
+		"total":   6.0,
+		"checks": map[string]interface{}{
+			"S1000":  2.0,
+			"SA1000": 1.0,
+			"SA4006": 2.0,
+			"ST1000": 1.0,
+		},
+		"severities": map[string]interface{}{
+			"error":   1.0,
+			"warning": 3.0,
+			"info":    2.0,
+		},
+		"directories": map[string]interface{}{
+			"a":                   2.0,
+			"c":                   2.0,
+			".":                   1.0,
+			"/usr/lib/go/src/fmt": 1.0,
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+	// keys are sorted, including the top-level ones
+	if !strings.HasPrefix(buf.String(), `{"checks":{"S1000":2,"SA1000":1,"SA4006":2,"ST1000":1},"directories":`) {
+		t.Errorf("checks aren't sorted in %s", buf.String())
+	}
+	if !strings.HasSuffix(buf.String(), `"total":6,"version":"2019.1"}`+"\n") {
+		t.Errorf("top-level keys aren't sorted in %s", buf.String())
+	}
+}
+
+// funcChecker flags all functions that have been converted to SSA,
+// including those in dependencies.
+type funcChecker struct{}
+
+func (funcChecker) Name() string            { return "funcchecker" }
+func (funcChecker) Prefix() string          { return "TEST" }
+func (funcChecker) Init(prog *lint.Program) {}
+
+func (funcChecker) Funcs() map[string]lint.Func {
+	return map[string]lint.Func{
+		"TEST1000": func(j *lint.Job) {
+			for _, fn := range j.Program.AllFunctions {
+				if _, ok := fn.Syntax().(*ast.FuncDecl); ok && fn.Blocks != nil {
+					j.Errorf(fn, "function %s", fn.Name())
+				}
+			}
+		},
+	}
+}
+
+// tempGOPATH creates a GOPATH containing files, which are keyed by
+// their paths relative to its src directory, and makes it the GOPATH
+// of build.Default. The returned function undoes this.
+func tempGOPATH(t *testing.T, files map[string]string) (string, func()) {
+	gopath, err := ioutil.TempDir("", "gopath")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, src := range files {
+		path := filepath.Join(gopath, "src", name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(path, []byte(src), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	old := build.Default.GOPATH
+	build.Default.GOPATH = gopath
+	return gopath, func() {
+		build.Default.GOPATH = old
+		os.RemoveAll(gopath)
+	}
+}
+
+func TestProgress(t *testing.T) {
+	_, cleanup := tempGOPATH(t, map[string]string{
+		"a/a.go":      "package a\n\nimport \"b\"\n\nvar A = b.B\n",
+		"a/a_test.go": "package a\n\nvar T = A\n",
+		"a/x_test.go": "package a_test\n\nimport \"a\"\n\nvar X = a.A\n",
+		"b/b.go":      "package b\n\nvar B = 1\n",
+		"c/c.go":      "package c\n",
+	})
+	defer cleanup()
+
+	// don't start printing, only record the status
+	pr := &progress{w: ioutil.Discard}
+	conf := newLoaderConfig(context.Background(), []string{"a", "c"}, false, &Options{LintTests: true}, pr)
+	if _, err := conf.Load(); err != nil {
+		t.Fatal(err)
+	}
+	// the test packages of a count towards a
+	if want := "loading packages: 2/2, dependencies: 1"; pr.status != want {
+		t.Errorf("got status %q, want %q", pr.status, want)
+	}
+}
+
+func TestSkipDependencyBodies(t *testing.T) {
+	_, cleanup := tempGOPATH(t, map[string]string{
+		"dep/dep.go": `package dep
+
+var F = func() int { return 1 }
+
+func G() int {
+	x := 1
+	return x
+}
+`,
+		"a/a.go": `package a
+
+import "dep"
+
+func A() int { return dep.G() + dep.F() }
+`,
+	})
+	defer cleanup()
+
+	lintFuncs := func(opt *Options) []string {
+		pss, err := Lint([]lint.Checker{funcChecker{}}, []string{"a"}, opt)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var names []string
+		for _, p := range pss[0] {
+			names = append(names, p.Text)
+		}
+		return names
+	}
+	if got := lintFuncs(&Options{SkipDependencyBodies: true}); len(got) != 1 || got[0] != "function A" {
+		t.Errorf("got problems %q, want only a problem for A", got)
+	}
+}
+
+// pkgChecker flags all packages that are being checked.
+type pkgChecker struct{}
+
+func (pkgChecker) Name() string            { return "pkgchecker" }
+func (pkgChecker) Prefix() string          { return "TEST" }
+func (pkgChecker) Init(prog *lint.Program) {}
+
+func (pkgChecker) Funcs() map[string]lint.Func {
+	return map[string]lint.Func{
+		"TEST1000": func(j *lint.Job) {
+			for _, pkg := range j.Program.Packages {
+				j.Errorf(pkg.Info.Files[0].Name, "package %s", pkg.Info.Pkg.Path())
+			}
+		},
+	}
+}
+
+func TestIncludeDependencies(t *testing.T) {
+	_, cleanup := tempGOPATH(t, map[string]string{
+		"a/a.go": `package a
+
+import (
+	"fmt"
+
+	"example.com/foo/dep"
+	"example.com/other"
+)
+
+func A() { fmt.Println(dep.Dep(), other.Other()) }
+`,
+		"a/vendor/example.com/foo/dep/dep.go": `package dep
+
+import "example.com/foo/indirect"
+
+func Dep() int { return indirect.Indirect() }
+`,
+		"a/vendor/example.com/foo/indirect/indirect.go": "package indirect\n\nfunc Indirect() int { return 1 }\n",
+		"a/vendor/example.com/other/other.go":           "package other\n\nfunc Other() int { return 2 }\n",
+	})
+	defer cleanup()
+
+	tests := []struct {
+		include []string
+		want    []string
+	}{
+		{nil, []string{"package a"}},
+		{[]string{"example.com/foo/dep"}, []string{"package a", "package a/vendor/example.com/foo/dep"}},
+		{[]string{"example.com/foo/*"}, []string{"package a", "package a/vendor/example.com/foo/dep", "package a/vendor/example.com/foo/indirect"}},
+		{[]string{"a/vendor/example.com/other"}, []string{"package a", "package a/vendor/example.com/other"}},
+		{[]string{"fmt", "example.com/none"}, []string{"package a"}},
+	}
+	for _, tt := range tests {
+		pss, err := Lint([]lint.Checker{pkgChecker{}}, []string{"a"}, &Options{IncludeDependencies: tt.include})
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got []string
+		for _, p := range pss[0] {
+			got = append(got, p.Text)
+		}
+		sort.Strings(got)
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("IncludeDependencies %q: got %q, want %q", tt.include, got, tt.want)
+		}
+	}
+}
+
+func TestStableOutput(t *testing.T) {
+	_, cleanup := tempGOPATH(t, map[string]string{
+		"a/a.go": "package a\n\nfunc A() {}\n",
+		"a/b.go": "package a\n\nfunc B() {}\n\nfunc C() {}\n",
+	})
+	defer cleanup()
+
+	output := func(cs []lint.Checker) []byte {
+		pss, err := Lint(cs, []string{"a"}, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var buf bytes.Buffer
+		r := newReport(pss)
+		TextOutput{&buf}.Format(r)
+		JSONOutput{w: &buf}.Format(r)
+		return buf.Bytes()
+	}
+	// the same problems found by checkers in a different order have
+	// to be output identically
+	first := output([]lint.Checker{funcChecker{}, pkgChecker{}})
+	second := output([]lint.Checker{pkgChecker{}, funcChecker{}})
+	if !bytes.Equal(first, second) {
+		t.Errorf("output differs between runs:\n%s\nand\n%s", first, second)
+	}
+}
+
+func TestCostProfile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "costs")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	name := filepath.Join(dir, "costs.json")
+
+	prof, err := loadCostProfile(name)
+	if err != nil {
+		t.Fatalf("loading a missing profile failed: %s", err)
+	}
+	prof.record("SA1000", 10*time.Millisecond)
+	prof.record("SA1000", 20*time.Millisecond)
+	prof.record("S1000", time.Second)
+	if err := prof.save(name); err != nil {
+		t.Fatal(err)
+	}
+
+	prof, err = loadCostProfile(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	prof.record("SA1000", 30*time.Millisecond)
+	want := map[string]time.Duration{"SA1000": 20 * time.Millisecond, "S1000": time.Second}
+	if got := prof.averages(); !reflect.DeepEqual(got, want) {
+		t.Errorf("got averages %v, want %v", got, want)
+	}
+}
+
+func TestFailFast(t *testing.T) {
+	_, cleanup := tempGOPATH(t, map[string]string{
+		"a/a.go": "package a\n\nfunc A() {}\n\nfunc B() {}\n",
+	})
+	defer cleanup()
+
+	pss, err := Lint([]lint.Checker{funcChecker{}, funcChecker{}}, []string{"a"}, &Options{FailFast: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the second checker mustn't run once the first one found a
+	// problem
+	if len(pss) != 1 || len(pss[0]) != 1 {
+		t.Fatalf("got problems %v, want a single problem", pss)
+	}
+	r := lint.Report{Problems: pss[0]}
+	r.Problems[0].Severity = lint.SeverityInfo
+	if status := exitStatus(r, lint.SeverityError, true); status != 1 {
+		t.Errorf("got exit status %d, want 1", status)
+	}
+}
+
+func TestCgo(t *testing.T) {
+	if !build.Default.CgoEnabled {
+		t.Skip("cgo is not enabled")
+	}
+	gopath, cleanup := tempGOPATH(t, map[string]string{
+		"a/a.go": `package a
+
+// static int add(int a, int b) { return a + b; }
+import "C"
+
+func Add(a, b int) int {
+	return int(C.add(C.int(a), C.int(b)))
+}
+`,
+		"a/b.go": `package a
+
+func Sub(a, b int) int { return a - b }
+`,
+	})
+	defer cleanup()
+
+	pss, err := Lint([]lint.Checker{funcChecker{}}, []string{"a"}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// functions in the files generated by cgo mustn't be reported
+	want := map[string]string{
+		"function Add": filepath.Join(gopath, "src", "a", "a.go") + ":6:6",
+		"function Sub": filepath.Join(gopath, "src", "a", "b.go") + ":3:6",
+	}
+	if len(pss[0]) != len(want) {
+		t.Errorf("got %d problems, want %d", len(pss[0]), len(want))
+	}
+	for _, p := range pss[0] {
+		if pos := p.Position.String(); want[p.Text] != pos {
+			t.Errorf("unexpected problem %q at %s", p.Text, pos)
+		}
+	}
+}
+
+func TestStripBodies(t *testing.T) {
+	const src = `package dep
+
+var F = func() int { return 1 }
+
+func G() int {
+	x := 1
+	return x
+}
+`
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "dep.go", src, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	info := &types.Info{
+		Types:      map[ast.Expr]types.TypeAndValue{},
+		Defs:       map[*ast.Ident]types.Object{},
+		Uses:       map[*ast.Ident]types.Object{},
+		Implicits:  map[ast.Node]types.Object{},
+		Selections: map[*ast.SelectorExpr]*types.Selection{},
+		Scopes:     map[ast.Node]*types.Scope{},
+	}
+	conf := types.Config{IgnoreFuncBodies: true}
+	pkg, err := conf.Check("dep", fset, []*ast.File{f}, info)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stripBodies([]*ast.File{f})
+
+	// building SSA must not trip over the missing type information
+	prog := ssa.NewProgram(fset, 0)
+	ssapkg := prog.CreatePackage(pkg, []*ast.File{f}, info, true)
+	prog.Build()
+	if fn := ssapkg.Func("G"); fn.Blocks != nil {
+		t.Errorf("G still has a body")
+	}
+}
+
+// generatedFile builds the syntax of
+//
+//	package gen
+//
+//	func Gen() {}
+//
+// without parsing it, the way a code generator might.
+func generatedFile(fset *token.FileSet) *ast.File {
+	tf := fset.AddFile("gen.go", -1, 27)
+	tf.SetLines([]int{0, 12, 13})
+	pos := func(off int) token.Pos { return token.Pos(tf.Base() + off) }
+	return &ast.File{
+		Package: pos(0),
+		Name:    &ast.Ident{NamePos: pos(8), Name: "gen"},
+		Decls: []ast.Decl{
+			&ast.FuncDecl{
+				Name: &ast.Ident{NamePos: pos(18), Name: "Gen"},
+				Type: &ast.FuncType{
+					Func:   pos(13),
+					Params: &ast.FieldList{Opening: pos(21), Closing: pos(22)},
+				},
+				Body: &ast.BlockStmt{Lbrace: pos(24), Rbrace: pos(25)},
+			},
+		},
+	}
+}
+
+func TestLintFiles(t *testing.T) {
+	check := func(t *testing.T, pss [][]lint.Problem) {
+		if len(pss) != 1 || len(pss[0]) != 1 {
+			t.Fatalf("got problems %v, want a single problem", pss)
+		}
+		p := pss[0][0]
+		if p.Text != "function Gen" || p.Position.Filename != "gen.go" || p.Position.Line != 3 {
+			t.Errorf("got problem %q at %s, want \"function Gen\" at gen.go:3", p.Text, p.Position)
+		}
+	}
+
+	t.Run("type-checked", func(t *testing.T) {
+		fset := token.NewFileSet()
+		f := generatedFile(fset)
+		pss, err := LintFiles([]lint.Checker{funcChecker{}}, fset, []*ast.File{f}, nil, nil, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		check(t, pss)
+	})
+
+	t.Run("precomputed", func(t *testing.T) {
+		fset := token.NewFileSet()
+		f := generatedFile(fset)
+		info := &types.Info{
+			Types:      map[ast.Expr]types.TypeAndValue{},
+			Defs:       map[*ast.Ident]types.Object{},
+			Uses:       map[*ast.Ident]types.Object{},
+			Implicits:  map[ast.Node]types.Object{},
+			Selections: map[*ast.SelectorExpr]*types.Selection{},
+			Scopes:     map[ast.Node]*types.Scope{},
+		}
+		pkg, err := new(types.Config).Check("gen", fset, []*ast.File{f}, info)
+		if err != nil {
+			t.Fatal(err)
+		}
+		pss, err := LintFiles([]lint.Checker{funcChecker{}}, fset, []*ast.File{f}, pkg, info, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		check(t, pss)
+
+		if _, err := LintFiles([]lint.Checker{funcChecker{}}, fset, []*ast.File{f}, pkg, &types.Info{}, nil); err == nil {
+			t.Error("expected an error for incomplete type information")
+		}
+	})
+}
+
+func TestAllowedNames(t *testing.T) {
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "legacy.go", "package legacy\n\nvar UserId int\nvar ServerUrl string\n", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	names := func(opt *Options) []string {
+		pss, err := LintFiles([]lint.Checker{stylecheck.NewChecker()}, fset, []*ast.File{f}, nil, nil, opt)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var out []string
+		for _, p := range pss[0] {
+			if p.Check == "ST1003" {
+				out = append(out, p.Name)
+			}
+		}
+		sort.Strings(out)
+		return out
+	}
+	if got, want := names(&Options{}), []string{"ServerUrl", "UserId"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got problems about %v, want %v", got, want)
+	}
+	opt := &Options{AllowedNames: map[string][]string{"ST1003": {"*Id"}}}
+	if got, want := names(opt), []string{"ServerUrl"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got problems about %v with UserId allowed, want %v", got, want)
+	}
+	opt = &Options{AllowedNames: map[string][]string{"ST1000": {"UserId", "ServerUrl"}}}
+	if got, want := names(opt), []string{"ServerUrl", "UserId"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got problems about %v with names allowed for another check, want %v", got, want)
+	}
+}
+
+func TestNewSince(t *testing.T) {
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "legacy.go", "// +build linux\n\npackage legacy\n\nvar UserId int\n", parser.ParseComments)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checks := func(opt *Options) map[string]bool {
+		pss, err := LintFiles([]lint.Checker{stylecheck.NewChecker()}, fset, []*ast.File{f}, nil, nil, opt)
+		if err != nil {
+			t.Fatal(err)
+		}
+		out := map[string]bool{}
+		for _, p := range pss[0] {
+			out[p.Check] = true
+		}
+		return out
+	}
+	if got := checks(&Options{}); !got["ST1003"] || !got["ST1019"] {
+		t.Errorf("got problems of %v, want ST1003 and ST1019", got)
+	}
+	// ST1003 predates 2019.1, ST1019 was added in 2019.2
+	if got := checks(&Options{NewSince: "2019.1"}); got["ST1003"] || !got["ST1019"] {
+		t.Errorf("got problems of %v with -new-since 2019.1, want ST1019 but not ST1003", got)
+	}
+	if got := checks(&Options{NewSince: "2019.2"}); len(got) != 0 {
+		t.Errorf("got problems of %v with -new-since 2019.2, want none", got)
+	}
+}
+
+func TestAPIOnly(t *testing.T) {
+	const src = `package api
+
+func Exported() { helper() }
+
+func helper() {}
+
+func unexportedOnly() {}
+
+type T struct{}
+
+func (T) Method()  {}
+func (T) private() {}
+`
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "api.go", src, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	funcs := func(opt *Options) []string {
+		pss, err := LintFiles([]lint.Checker{funcChecker{}}, fset, []*ast.File{f}, nil, nil, opt)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var out []string
+		for _, p := range pss[0] {
+			out = append(out, p.Text)
+		}
+		sort.Strings(out)
+		return out
+	}
+	want := []string{"function Exported", "function Method", "function helper", "function private", "function unexportedOnly"}
+	if got := funcs(&Options{}); !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+	want = []string{"function Exported", "function Method", "function helper"}
+	if got := funcs(&Options{APIOnly: true}); !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v with APIOnly, want %v", got, want)
+	}
+}
+
+// blockingChecker has a check that stops linting by calling cancel,
+// standing in for reaching the deadline, and that then keeps running
+// until release is closed.
+type blockingChecker struct {
+	cancel  context.CancelFunc
+	release chan struct{}
+}
+
+func (blockingChecker) Name() string            { return "blockingchecker" }
+func (blockingChecker) Prefix() string          { return "TEST" }
+func (blockingChecker) Init(prog *lint.Program) {}
+
+func (c blockingChecker) Funcs() map[string]lint.Func {
+	return map[string]lint.Func{
+		"TEST2000": func(j *lint.Job) {
+			c.cancel()
+			<-c.release
+		},
+	}
+}
+
+func TestDeadline(t *testing.T) {
+	fset := token.NewFileSet()
+	f := generatedFile(fset)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	blocking := blockingChecker{cancel: cancel, release: make(chan struct{})}
+	defer close(blocking.release)
+	cs := []lint.Checker{funcChecker{}, blocking, funcChecker{}}
+	opt := &Options{ctx: ctx}
+	pss, err := LintFiles(cs, fset, []*ast.File{f}, nil, nil, opt)
+	incomplete, ok := err.(*IncompleteError)
+	if !ok {
+		t.Fatalf("got error %v, want an *IncompleteError", err)
+	}
+	if want := []string{"blockingchecker", "funcchecker"}; !reflect.DeepEqual(incomplete.Checkers, want) {
+		t.Errorf("got incomplete checkers %v, want %v", incomplete.Checkers, want)
+	}
+	if want := []string{"gen"}; !reflect.DeepEqual(incomplete.Packages, want) {
+		t.Errorf("got incomplete packages %v, want %v", incomplete.Packages, want)
+	}
+	if len(pss) != len(cs) {
+		t.Fatalf("got problems of %d checkers, want %d", len(pss), len(cs))
+	}
+	if len(pss[0]) != 1 || pss[0][0].Text != "function Gen" {
+		t.Errorf("got problems %v from the first checker, want its problem", pss[0])
+	}
+	if len(pss[1]) != 1 || pss[1][0].Text != "check TEST2000 was skipped because linting didn't finish before the deadline" {
+		t.Errorf("got problems %v from the blocking checker, want a note about the skipped check", pss[1])
+	}
+	if len(pss[2]) != 0 {
+		t.Errorf("got problems %v from a checker that should not have run", pss[2])
+	}
+}
+
+func TestDeadlineDuringLoading(t *testing.T) {
+	cs := []lint.Checker{funcChecker{}}
+	opt := &Options{Deadline: time.Now().Add(-time.Second)}
+	pss, err := Lint(cs, []string{"fmt"}, opt)
+	incomplete, ok := err.(*IncompleteError)
+	if !ok {
+		t.Fatalf("got error %v, want an *IncompleteError", err)
+	}
+	if want := []string{"funcchecker"}; !reflect.DeepEqual(incomplete.Checkers, want) {
+		t.Errorf("got incomplete checkers %v, want %v", incomplete.Checkers, want)
+	}
+	if want := []string{"fmt"}; !reflect.DeepEqual(incomplete.Packages, want) {
+		t.Errorf("got incomplete packages %v, want %v", incomplete.Packages, want)
+	}
+	if len(pss) != len(cs) || len(pss[0]) != 0 {
+		t.Errorf("got problems %v, want no problems for each checker", pss)
+	}
+}
+
+// benchmarkCorpus is a fixed set of packages that exercises the
+// parser and type checker on a representative amount of code.
+var benchmarkCorpus = []string{"encoding/json", "net/http"}
+
+func benchmarkLoad(b *testing.B, opt *Options) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		conf := newLoaderConfig(context.Background(), benchmarkCorpus, false, opt, nil)
+		if _, err := conf.Load(); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkLoad(b *testing.B) {
+	benchmarkLoad(b, &Options{})
+}
+
+func BenchmarkLoadSkipDependencyBodies(b *testing.B) {
+	benchmarkLoad(b, &Options{SkipDependencyBodies: true})
+}
@@ -0,0 +1,86 @@
+package lint
+
+import (
+	"path/filepath"
+	"sort"
+)
+
+// A Report is a collection of problems, as produced by one or more
+// runs of linters. The methods for filtering and grouping a report
+// return new reports and leave the original unmodified.
+type Report struct {
+	Problems []Problem
+}
+
+func (r Report) filter(fn func(p Problem) bool) Report {
+	var out []Problem
+	for _, p := range r.Problems {
+		if fn(p) {
+			out = append(out, p)
+		}
+	}
+	return Report{Problems: out}
+}
+
+// FilterByCheck returns the problems found by any of the checks.
+// Check names support globbing, e.g. SA1*.
+func (r Report) FilterByCheck(checks ...string) Report {
+	return r.filter(func(p Problem) bool {
+		for _, c := range checks {
+			if m, _ := filepath.Match(c, p.Check); m {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+// FilterBySeverity returns the problems that are at least as severe
+// as sev.
+func (r Report) FilterBySeverity(sev Severity) Report {
+	return r.filter(func(p Problem) bool {
+		return p.Severity <= sev
+	})
+}
+
+// SortByPosition sorts the problems in place by file name, line,
+// column, check and finally text. Problems found concurrently, or by
+// several linters, are thus always output in the same order.
+func (r Report) SortByPosition() {
+	sort.Stable(byPosition{nil, r.Problems})
+}
+
+// GroupByFile returns the problems grouped by the names of the files
+// they are in.
+func (r Report) GroupByFile() map[string]Report {
+	out := map[string]Report{}
+	for _, p := range r.Problems {
+		g := out[p.Position.Filename]
+		g.Problems = append(g.Problems, p)
+		out[p.Position.Filename] = g
+	}
+	return out
+}
+
+// GroupByCheck returns the problems grouped by the names of the checks
+// that found them.
+func (r Report) GroupByCheck() map[string]Report {
+	out := map[string]Report{}
+	for _, p := range r.Problems {
+		g := out[p.Check]
+		g.Problems = append(g.Problems, p)
+		out[p.Check] = g
+	}
+	return out
+}
+
+// GroupBySeverity returns the problems grouped by their severities.
+func (r Report) GroupBySeverity() map[Severity]Report {
+	out := map[Severity]Report{}
+	for _, p := range r.Problems {
+		g := out[p.Severity]
+		g.Problems = append(g.Problems, p)
+		out[p.Severity] = g
+	}
+	return out
+}
@@ -0,0 +1,138 @@
+package lint_test
+
+import (
+	"go/token"
+	"reflect"
+	"testing"
+
+	. "honnef.co/go/tools/lint"
+)
+
+func testReport() Report {
+	pos := func(file string, line int) token.Position {
+		return token.Position{Filename: file, Line: line, Column: 1}
+	}
+	return Report{Problems: []Problem{
+		{Position: pos("b.go", 3), Check: "SA1000", Severity: SeverityError},
+		{Position: pos("a.go", 7), Check: "S1000", Severity: SeverityWarning},
+		{Position: pos("b.go", 1), Check: "SA1001", Severity: SeverityInfo},
+		{Position: pos("a.go", 2), Check: "SA4006", Severity: SeverityWarning},
+	}}
+}
+
+func checks(r Report) []string {
+	var out []string
+	for _, p := range r.Problems {
+		out = append(out, p.Check)
+	}
+	return out
+}
+
+func TestReportFilter(t *testing.T) {
+	tests := []struct {
+		name string
+		r    Report
+		want []string
+	}{
+		{"check", testReport().FilterByCheck("SA1000", "S1000"), []string{"SA1000", "S1000"}},
+		{"check glob", testReport().FilterByCheck("SA*"), []string{"SA1000", "SA1001", "SA4006"}},
+		{"check none", testReport().FilterByCheck("ST*"), nil},
+		{"error", testReport().FilterBySeverity(SeverityError), []string{"SA1000"}},
+		{"warning", testReport().FilterBySeverity(SeverityWarning), []string{"SA1000", "S1000", "SA4006"}},
+		{"info", testReport().FilterBySeverity(SeverityInfo), []string{"SA1000", "S1000", "SA1001", "SA4006"}},
+		{"check and severity", testReport().FilterByCheck("SA*").FilterBySeverity(SeverityWarning), []string{"SA1000", "SA4006"}},
+	}
+	for _, tt := range tests {
+		if got := checks(tt.r); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: got %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestReportSortByPosition(t *testing.T) {
+	r := testReport()
+	r.SortByPosition()
+	want := []string{"SA4006", "S1000", "SA1001", "SA1000"}
+	if got := checks(r); !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+
+	r = testReport().FilterBySeverity(SeverityWarning)
+	r.SortByPosition()
+	want = []string{"SA4006", "S1000", "SA1000"}
+	if got := checks(r); !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+
+	// Problems at the same position are ordered by check, then by
+	// text, regardless of the order they were found in.
+	at := token.Position{Filename: "a.go", Line: 1, Column: 1}
+	r = Report{Problems: []Problem{
+		{Position: at, Check: "SA4006", Text: "b"},
+		{Position: at, Check: "SA1000", Text: "b"},
+		{Position: at, Check: "SA4006", Text: "a"},
+	}}
+	r.SortByPosition()
+	var got []string
+	for _, p := range r.Problems {
+		got = append(got, p.Check+" "+p.Text)
+	}
+	if want := []string{"SA1000 b", "SA4006 a", "SA4006 b"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestReportGroupByFile(t *testing.T) {
+	r := testReport()
+	r.SortByPosition()
+	groups := r.GroupByFile()
+	want := map[string][]string{
+		"a.go": {"SA4006", "S1000"},
+		"b.go": {"SA1001", "SA1000"},
+	}
+	if len(groups) != len(want) {
+		t.Fatalf("got %d groups, want %d", len(groups), len(want))
+	}
+	for file, w := range want {
+		if got := checks(groups[file]); !reflect.DeepEqual(got, w) {
+			t.Errorf("%s: got %v, want %v", file, got, w)
+		}
+	}
+
+	groups = testReport().FilterByCheck("SA1*").GroupByFile()
+	if len(groups) != 1 || !reflect.DeepEqual(checks(groups["b.go"]), []string{"SA1000", "SA1001"}) {
+		t.Errorf("got %v, want only b.go with SA1000 and SA1001", groups)
+	}
+}
+
+func TestReportGroupByCheck(t *testing.T) {
+	r := testReport()
+	r.Problems = append(r.Problems, Problem{Check: "SA1000", Severity: SeverityError})
+	groups := r.GroupByCheck()
+	want := map[string]int{"SA1000": 2, "S1000": 1, "SA1001": 1, "SA4006": 1}
+	if len(groups) != len(want) {
+		t.Fatalf("got %d groups, want %d", len(groups), len(want))
+	}
+	for check, n := range want {
+		if got := len(groups[check].Problems); got != n {
+			t.Errorf("%s: got %d problems, want %d", check, got, n)
+		}
+	}
+}
+
+func TestReportGroupBySeverity(t *testing.T) {
+	groups := testReport().GroupBySeverity()
+	want := map[Severity][]string{
+		SeverityError:   {"SA1000"},
+		SeverityWarning: {"S1000", "SA4006"},
+		SeverityInfo:    {"SA1001"},
+	}
+	if len(groups) != len(want) {
+		t.Fatalf("got %d groups, want %d", len(groups), len(want))
+	}
+	for sev, w := range want {
+		if got := checks(groups[sev]); !reflect.DeepEqual(got, w) {
+			t.Errorf("%s: got %v, want %v", sev, got, w)
+		}
+	}
+}
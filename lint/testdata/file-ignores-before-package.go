@@ -0,0 +1,5 @@
+// Package pkg is a package.
+//lint:file-ignore TEST1000 File-wide ignore before the package clause
+package pkg
+
+func fn1() {}
@@ -0,0 +1,10 @@
+package pkg
+
+// A comment that isn't a file-ignore.
+
+func fn1() {} // MATCH "test problem"
+
+//lint:file-ignore TEST1000 Too late to be a file-wide ignore
+func fn2() {} // MATCH "test problem"
+
+// MATCH:7 "file-ignore directive has to appear before the package clause"
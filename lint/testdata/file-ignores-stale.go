@@ -0,0 +1,7 @@
+package pkg
+
+//lint:file-ignore TEST1001 This doesn't match anything
+
+func fn1() {} // MATCH "test problem"
+
+// MATCH:3 "this linter directive didn't match anything"
@@ -0,0 +1,27 @@
+package pkg
+
+func fn1() {}
+
+//lint:enable TEST5000
+func fn2() {} // MATCH "test problem"
+
+//lint:enable TEST5000 trialing the check in this function
+func fn3() { // MATCH "test problem"
+	_ = func() {} // MATCH "test problem"
+}
+
+func fn4() {
+	_ = func() {}
+
+	//lint:enable TEST5*
+	_ = func() {} // MATCH "test problem"
+}
+
+//lint:enable TEST5000
+var _ int
+
+//lint:enable
+func fn5() {}
+
+// MATCH:20 "this linter directive didn't match anything"
+// MATCH:23 "malformed linter directive"
@@ -7,10 +7,12 @@
 package testutil // import "honnef.co/go/tools/lint/testutil"
 
 import (
+	"bytes"
 	"flag"
 	"fmt"
 	"go/parser"
 	"go/token"
+	"go/types"
 	"io/ioutil"
 	"os"
 	"path"
@@ -28,6 +30,15 @@ import (
 var lintMatch = flag.String("lint.match", "", "restrict testdata matches to this pattern")
 
 func TestAll(t *testing.T, c lint.Checker, dir string) {
+	testAll(t, c, dir, []string{"*"})
+}
+
+// TestDefaults is like TestAll, but doesn't enable opt-in checks.
+func TestDefaults(t *testing.T, c lint.Checker, dir string) {
+	testAll(t, c, dir, nil)
+}
+
+func testAll(t *testing.T, c lint.Checker, dir string, enabled []string) {
 	baseDir := filepath.Join("testdata", dir)
 	fis, err := ioutil.ReadDir(baseDir)
 	if err != nil {
@@ -65,9 +76,20 @@ func TestAll(t *testing.T, c lint.Checker, dir string) {
 
 	conf := &loader.Config{
 		ParserMode: parser.ParseComments,
+		TypeChecker: types.Config{
+			// Use a fixed, 64-bit architecture so that tests
+			// involving type sizes are independent of the host.
+			Sizes: types.SizesFor("gc", "amd64"),
+		},
 	}
 	sources := map[string][]byte{}
 	for _, fi := range fis {
+		if fi.IsDir() || !strings.HasSuffix(fi.Name(), ".go") {
+			// subdirectories hold fixtures for tests with
+			// non-default checker configurations, and .golden
+			// files hold the expected results of fixes
+			continue
+		}
 		filename := path.Join(baseDir, fi.Name())
 		src, err := ioutil.ReadFile(filename)
 		if err != nil {
@@ -89,9 +111,12 @@ func TestAll(t *testing.T, c lint.Checker, dir string) {
 	}
 
 	for version, fis := range files {
-		l := &lint.Linter{Checker: c, GoVersion: version}
+		l := &lint.Linter{Checker: c, GoVersion: version, Enabled: enabled}
 
 		res := l.Lint(lprog, conf)
+		for _, fi := range fis {
+			testFixes(t, baseDir, fi.Name(), sources[fi.Name()], res)
+		}
 		for _, fi := range fis {
 			name := fi.Name()
 			src := sources[name]
@@ -99,6 +124,17 @@ func TestAll(t *testing.T, c lint.Checker, dir string) {
 			ins := parseInstructions(t, name, src)
 
 			for _, in := range ins {
+				if !in.Related {
+					continue
+				}
+				if !hasRelated(res, name, in) {
+					t.Errorf("Lint failed at %s:%d; no problem has related information matching /%v/", name, in.Line, in.Match)
+				}
+			}
+			for _, in := range ins {
+				if in.Related {
+					continue
+				}
 				ok := false
 				for i, p := range res {
 					if p.Position.Line != in.Line || filepath.Base(p.Position.Filename) != name {
@@ -131,10 +167,51 @@ func TestAll(t *testing.T, c lint.Checker, dir string) {
 	}
 }
 
+// hasRelated reports whether any of the problems in ps has related
+// information that in matches.
+func hasRelated(ps []lint.Problem, name string, in instruction) bool {
+	for _, p := range ps {
+		for _, rel := range p.Related {
+			if rel.Position.Line == in.Line && filepath.Base(rel.Position.Filename) == name && in.Match.MatchString(rel.Text) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// testFixes compares the result of applying all suggested fixes in a
+// file with the file's golden version, if it has one.
+func testFixes(t *testing.T, dir, name string, src []byte, ps []lint.Problem) {
+	golden, err := ioutil.ReadFile(filepath.Join(dir, name+".golden"))
+	if os.IsNotExist(err) {
+		return
+	}
+	if err != nil {
+		t.Errorf("Failed reading golden file for %s: %v", name, err)
+		return
+	}
+	var fixes []lint.SuggestedFix
+	for _, p := range ps {
+		if filepath.Base(p.Position.Filename) == name {
+			fixes = append(fixes, p.Fixes...)
+		}
+	}
+	out, _, err := lint.ApplyFixes(src, fixes)
+	if err != nil {
+		t.Errorf("Failed applying fixes to %s: %v", name, err)
+		return
+	}
+	if !bytes.Equal(out, golden) {
+		t.Errorf("Applying fixes to %s produced\n%s\nwant\n%s", name, out, golden)
+	}
+}
+
 type instruction struct {
 	Line        int            // the line number this applies to
 	Match       *regexp.Regexp // what pattern to match
 	Replacement string         // what the suggested replacement line should be
+	Related     bool           // whether to match related information instead of problems
 }
 
 // parseInstructions parses instructions from the comments in a Go source file.
@@ -158,7 +235,12 @@ func parseInstructions(t *testing.T, filename string, src []byte) []instruction
 				ins = make([]instruction, 0)
 				continue
 			}
-			if !strings.Contains(line, "MATCH") {
+			// RELATED instructions match the related information
+			// of problems, such as RELATED "x is overwritten here"
+			verb := "MATCH"
+			if strings.Contains(line, "RELATED") {
+				verb = "RELATED"
+			} else if !strings.Contains(line, "MATCH") {
 				continue
 			}
 			rx, err := extractPattern(line)
@@ -166,9 +248,9 @@ func parseInstructions(t *testing.T, filename string, src []byte) []instruction
 				t.Fatalf("At %v:%d: %v", filename, ln, err)
 			}
 			matchLine := ln
-			if i := strings.Index(line, "MATCH:"); i >= 0 {
+			if i := strings.Index(line, verb+":"); i >= 0 {
 				// This is a match for a different line.
-				lns := strings.TrimPrefix(line[i:], "MATCH:")
+				lns := strings.TrimPrefix(line[i:], verb+":")
 				lns = lns[:strings.Index(lns, " ")]
 				matchLine, err = strconv.Atoi(lns)
 				if err != nil {
@@ -183,6 +265,7 @@ func parseInstructions(t *testing.T, filename string, src []byte) []instruction
 				Line:        matchLine,
 				Match:       rx,
 				Replacement: repl,
+				Related:     verb == "RELATED",
 			})
 		}
 	}
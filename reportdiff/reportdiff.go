@@ -0,0 +1,148 @@
+// Package reportdiff compares two sets of problems, as written by the
+// json output format of the linters, to find out which problems have
+// been added and which have been fixed between two runs.
+//
+// Problems are matched by their fingerprints, which don't include
+// line and column numbers, so that problems are still matched after
+// unrelated changes moved them around in their files.
+package reportdiff // import "honnef.co/go/tools/reportdiff"
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"sort"
+)
+
+// Location is the position of a problem.
+type Location struct {
+	File   string `json:"file"`
+	Line   int    `json:"line"`
+	Column int    `json:"column"`
+}
+
+// Problem is a problem as written by the json output format. Only the
+// fields needed for matching are decoded; Raw holds the problem's
+// original encoding, including all other fields.
+type Problem struct {
+	Checker  string   `json:"checker"`
+	Code     string   `json:"code"`
+	Location Location `json:"location"`
+	Message  string   `json:"message"`
+	Ignored  bool     `json:"ignored"`
+
+	Raw json.RawMessage `json:"-"`
+}
+
+// Fingerprint identifies the problem independently of its line and
+// column. It consists of the file, the checker, the check and the
+// message. Identical problems in the same file have the same
+// fingerprint; they are told apart by their order.
+func (p Problem) Fingerprint() string {
+	h := sha256.New()
+	for _, s := range []string{p.Location.File, p.Checker, p.Code, p.Message} {
+		io.WriteString(h, s)
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil)[:8])
+}
+
+// Read reads a stream of problems in the json output format. Ignored
+// problems, which are only present if -show-ignored was used, are
+// skipped.
+func Read(r io.Reader) ([]Problem, error) {
+	var out []Problem
+	dec := json.NewDecoder(r)
+	for {
+		var raw json.RawMessage
+		if err := dec.Decode(&raw); err == io.EOF {
+			return out, nil
+		} else if err != nil {
+			return nil, err
+		}
+		var p Problem
+		if err := json.Unmarshal(raw, &p); err != nil {
+			return nil, err
+		}
+		if p.Ignored {
+			continue
+		}
+		p.Raw = raw
+		out = append(out, p)
+	}
+}
+
+// Diff returns the problems in newer that aren't in older, and the
+// problems in older that aren't in newer anymore. If a fingerprint
+// occurs more often in one of the sets, its problems are paired in
+// the order of their positions, and the remaining ones are reported.
+func Diff(older, newer []Problem) (added, fixed []Problem) {
+	olderByFP := groupByFingerprint(older)
+	newerByFP := groupByFingerprint(newer)
+	for fp, ps := range newerByFP {
+		if n := len(olderByFP[fp]); n < len(ps) {
+			added = append(added, ps[n:]...)
+		}
+	}
+	for fp, ps := range olderByFP {
+		if n := len(newerByFP[fp]); n < len(ps) {
+			fixed = append(fixed, ps[n:]...)
+		}
+	}
+	sortByPosition(added)
+	sortByPosition(fixed)
+	return added, fixed
+}
+
+// Write writes problems in the json output format.
+func Write(w io.Writer, ps []Problem) error {
+	for _, p := range ps {
+		raw := p.Raw
+		if raw == nil {
+			var err error
+			raw, err = json.Marshal(p)
+			if err != nil {
+				return err
+			}
+		}
+		var buf bytes.Buffer
+		if err := json.Compact(&buf, raw); err != nil {
+			return err
+		}
+		buf.WriteByte('\n')
+		if _, err := w.Write(buf.Bytes()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func groupByFingerprint(ps []Problem) map[string][]Problem {
+	out := map[string][]Problem{}
+	for _, p := range ps {
+		fp := p.Fingerprint()
+		out[fp] = append(out[fp], p)
+	}
+	for _, g := range out {
+		sortByPosition(g)
+	}
+	return out
+}
+
+func sortByPosition(ps []Problem) {
+	sort.SliceStable(ps, func(i, j int) bool {
+		li, lj := ps[i].Location, ps[j].Location
+		if li.File != lj.File {
+			return li.File < lj.File
+		}
+		if li.Line != lj.Line {
+			return li.Line < lj.Line
+		}
+		if li.Column != lj.Column {
+			return li.Column < lj.Column
+		}
+		return ps[i].Code < ps[j].Code
+	})
+}
@@ -0,0 +1,87 @@
+package reportdiff
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const older = `{"checker":"staticcheck","code":"SA4006","location":{"file":"/src/a.go","line":10,"column":2},"message":"x is never used","confidence":1,"ignored":false}
+{"checker":"staticcheck","code":"SA1019","location":{"file":"/src/a.go","line":20,"column":2},"message":"foo is deprecated","confidence":1,"ignored":false}
+{"checker":"staticcheck","code":"SA1019","location":{"file":"/src/a.go","line":30,"column":2},"message":"foo is deprecated","confidence":1,"ignored":false}
+{"checker":"staticcheck","code":"SA4017","location":{"file":"/src/b.go","line":5,"column":1},"message":"result is unused","confidence":1,"ignored":true}
+`
+
+// newer moves all problems down by two lines, fixes one of the
+// deprecation warnings and adds two problems.
+const newer = `{"checker":"staticcheck","code":"SA4006","location":{"file":"/src/a.go","line":12,"column":2},"message":"x is never used","confidence":1,"ignored":false}
+{"checker":"staticcheck","code":"SA1019","location":{"file":"/src/a.go","line":22,"column":2},"message":"foo is deprecated","confidence":1,"ignored":false}
+{"checker":"staticcheck","code":"SA4006","location":{"file":"/src/a.go","line":40,"column":2},"message":"y is never used","confidence":1,"ignored":false}
+{"checker":"staticcheck","code":"SA4006","location":{"file":"/src/b.go","line":3,"column":2},"message":"x is never used","confidence":1,"ignored":false}
+`
+
+func read(t *testing.T, s string) []Problem {
+	ps, err := Read(strings.NewReader(s))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return ps
+}
+
+func messages(ps []Problem) []string {
+	var out []string
+	for _, p := range ps {
+		out = append(out, p.Location.File+": "+p.Message)
+	}
+	return out
+}
+
+func TestDiff(t *testing.T) {
+	olderPs, newerPs := read(t, older), read(t, newer)
+	if len(olderPs) != 3 {
+		t.Fatalf("got %d problems, want 3 without the ignored one", len(olderPs))
+	}
+	added, fixed := Diff(olderPs, newerPs)
+	if want := []string{"/src/a.go: y is never used", "/src/b.go: x is never used"}; !reflect.DeepEqual(messages(added), want) {
+		t.Errorf("got added problems %q, want %q", messages(added), want)
+	}
+	// the second of the identical problems is the one that got fixed
+	if len(fixed) != 1 || fixed[0].Location.Line != 30 {
+		t.Errorf("got fixed problems %v, want the one on line 30", fixed)
+	}
+
+	added, fixed = Diff(newerPs, newerPs)
+	if len(added) != 0 || len(fixed) != 0 {
+		t.Errorf("got added problems %q and fixed problems %q for unchanged problems", messages(added), messages(fixed))
+	}
+}
+
+func TestWrite(t *testing.T) {
+	ps := read(t, newer)
+	var buf bytes.Buffer
+	if err := Write(&buf, ps); err != nil {
+		t.Fatal(err)
+	}
+	if got := buf.String(); got != newer {
+		t.Errorf("got\n%s\nwant\n%s", got, newer)
+	}
+}
+
+func TestFingerprint(t *testing.T) {
+	p := Problem{Checker: "staticcheck", Code: "SA4006", Location: Location{"/src/a.go", 1, 1}, Message: "x is never used"}
+	moved := p
+	moved.Location.Line = 10
+	if p.Fingerprint() != moved.Fingerprint() {
+		t.Errorf("fingerprint changed when moving the problem to another line")
+	}
+	for _, other := range []Problem{
+		{Checker: p.Checker, Code: "SA4007", Location: p.Location, Message: p.Message},
+		{Checker: p.Checker, Code: p.Code, Location: Location{"/src/b.go", 1, 1}, Message: p.Message},
+		{Checker: p.Checker, Code: p.Code, Location: p.Location, Message: "y is never used"},
+	} {
+		if p.Fingerprint() == other.Fingerprint() {
+			t.Errorf("%v and %v have the same fingerprint", p, other)
+		}
+	}
+}
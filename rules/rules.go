@@ -0,0 +1,433 @@
+// Package rules implements custom checks that are defined by rules
+// in a small pattern language, for checks that are too specific to
+// a code base to be worth writing in Go.
+//
+// A rule reports all syntax nodes of a kind that satisfy a list of
+// conditions, for example
+//
+//	report CallExpr where Fun.Sel == "Println" and Args[0] is StringLit and Args[0] matches `\n$`: "Println already adds a newline"
+//
+// The kind is the name of a type in go/ast, such as CallExpr or
+// AssignStmt, or StringLit, which matches string literals. Conditions
+// refer to the fields of the node by paths, which select fields by
+// name and elements of lists by index, like Fun.Sel or Args[0]. The
+// value of a path is the name of an identifier, the value of a
+// literal, in the case of string literals without quotes, or the
+// source code of other expressions, so that
+//
+//	Fun == "fmt.Println"
+//
+// compares the called function. The supported conditions are
+//
+//	path == "value"
+//	path != "value"
+//	path matches "regexp"
+//	path is Kind
+//	not condition
+//
+// A condition whose path doesn't exist in a node, such as Args[1] of
+// a call with a single argument, doesn't hold. The message after the
+// colon is optional.
+//
+// Rules don't have access to type information, so they can only
+// match code by its syntax.
+package rules // import "honnef.co/go/tools/rules"
+
+import (
+	"fmt"
+	"go/ast"
+	"go/scanner"
+	"go/token"
+	"go/types"
+	"reflect"
+	"regexp"
+	"strconv"
+
+	"honnef.co/go/tools/lint"
+)
+
+// kinds maps the kinds of nodes that rules can match to their types.
+var kinds = map[string]reflect.Type{}
+
+func init() {
+	for _, node := range []ast.Node{
+		(*ast.ArrayType)(nil), (*ast.AssignStmt)(nil), (*ast.BasicLit)(nil),
+		(*ast.BinaryExpr)(nil), (*ast.BlockStmt)(nil), (*ast.BranchStmt)(nil),
+		(*ast.CallExpr)(nil), (*ast.CaseClause)(nil), (*ast.ChanType)(nil),
+		(*ast.CommClause)(nil), (*ast.CompositeLit)(nil), (*ast.DeclStmt)(nil),
+		(*ast.DeferStmt)(nil), (*ast.Ellipsis)(nil), (*ast.ExprStmt)(nil),
+		(*ast.Field)(nil), (*ast.ForStmt)(nil), (*ast.FuncDecl)(nil),
+		(*ast.FuncLit)(nil), (*ast.FuncType)(nil), (*ast.GenDecl)(nil),
+		(*ast.GoStmt)(nil), (*ast.Ident)(nil), (*ast.IfStmt)(nil),
+		(*ast.ImportSpec)(nil), (*ast.IncDecStmt)(nil), (*ast.IndexExpr)(nil),
+		(*ast.InterfaceType)(nil), (*ast.KeyValueExpr)(nil), (*ast.LabeledStmt)(nil),
+		(*ast.MapType)(nil), (*ast.ParenExpr)(nil), (*ast.RangeStmt)(nil),
+		(*ast.ReturnStmt)(nil), (*ast.SelectStmt)(nil), (*ast.SelectorExpr)(nil),
+		(*ast.SendStmt)(nil), (*ast.SliceExpr)(nil), (*ast.StarExpr)(nil),
+		(*ast.StructType)(nil), (*ast.SwitchStmt)(nil), (*ast.TypeAssertExpr)(nil),
+		(*ast.TypeSpec)(nil), (*ast.TypeSwitchStmt)(nil), (*ast.UnaryExpr)(nil),
+		(*ast.ValueSpec)(nil),
+	} {
+		T := reflect.TypeOf(node).Elem()
+		kinds[T.Name()] = T
+	}
+	kinds["StringLit"] = kinds["BasicLit"]
+}
+
+// isKind reports whether node is of the named kind.
+func isKind(node ast.Node, kind string) bool {
+	if kind == "StringLit" {
+		lit, ok := node.(*ast.BasicLit)
+		return ok && lit.Kind == token.STRING
+	}
+	T := reflect.TypeOf(node)
+	return T.Kind() == reflect.Ptr && T.Elem() == kinds[kind]
+}
+
+// A step selects a field by name or, if the name is empty, the
+// element of a list at an index.
+type step struct {
+	field string
+	index int
+}
+
+type cond struct {
+	not  bool
+	path []step
+	// op is one of ==, !=, matches and is
+	op    string
+	value string
+	rx    *regexp.Regexp
+}
+
+// A Rule is a parsed rule.
+type Rule struct {
+	Kind    string
+	Message string
+	conds   []cond
+}
+
+// resolve returns the value of path in node.
+func resolve(node ast.Node, path []step) (reflect.Value, bool) {
+	v := reflect.ValueOf(node)
+	for _, s := range path {
+		for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+			if v.IsNil() {
+				return reflect.Value{}, false
+			}
+			v = v.Elem()
+		}
+		if s.field == "" {
+			if v.Kind() != reflect.Slice || s.index >= v.Len() {
+				return reflect.Value{}, false
+			}
+			v = v.Index(s.index)
+			continue
+		}
+		if v.Kind() != reflect.Struct {
+			return reflect.Value{}, false
+		}
+		f, ok := v.Type().FieldByName(s.field)
+		if !ok || f.PkgPath != "" {
+			return reflect.Value{}, false
+		}
+		v = v.FieldByIndex(f.Index)
+	}
+	if (v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface) && v.IsNil() {
+		return reflect.Value{}, false
+	}
+	return v, true
+}
+
+// str returns the string that a value compares as.
+func str(v reflect.Value) (string, bool) {
+	switch x := v.Interface().(type) {
+	case *ast.Ident:
+		return x.Name, true
+	case *ast.BasicLit:
+		if x.Kind == token.STRING {
+			if s, err := strconv.Unquote(x.Value); err == nil {
+				return s, true
+			}
+		}
+		return x.Value, true
+	case ast.Expr:
+		return types.ExprString(x), true
+	case fmt.Stringer:
+		return x.String(), true
+	}
+	switch v.Kind() {
+	case reflect.String:
+		return v.String(), true
+	case reflect.Bool, reflect.Int, reflect.Int64:
+		return fmt.Sprint(v.Interface()), true
+	}
+	return "", false
+}
+
+func (c cond) holds(node ast.Node) bool {
+	ok := false
+	if v, found := resolve(node, c.path); found {
+		switch c.op {
+		case "is":
+			n, isNode := v.Interface().(ast.Node)
+			ok = isNode && isKind(n, c.value)
+		default:
+			s, isStr := str(v)
+			switch {
+			case !isStr:
+			case c.op == "==":
+				ok = s == c.value
+			case c.op == "!=":
+				ok = s != c.value
+			case c.op == "matches":
+				ok = c.rx.MatchString(s)
+			}
+		}
+	}
+	return ok != c.not
+}
+
+// Match reports whether node satisfies the rule.
+func (r *Rule) Match(node ast.Node) bool {
+	if !isKind(node, r.Kind) {
+		return false
+	}
+	for _, c := range r.conds {
+		if !c.holds(node) {
+			return false
+		}
+	}
+	return true
+}
+
+// parser parses a rule, which consists of Go tokens.
+type parser struct {
+	s   scanner.Scanner
+	pos token.Pos
+	tok token.Token
+	lit string
+	err error
+}
+
+func (p *parser) next() {
+	p.pos, p.tok, p.lit = p.s.Scan()
+	if p.tok == token.SEMICOLON && p.lit == "\n" {
+		// automatically inserted at the end of the rule
+		p.pos, p.tok, p.lit = p.s.Scan()
+	}
+}
+
+func (p *parser) errorf(format string, args ...interface{}) {
+	p.errorAt(p.pos, format, args...)
+}
+
+func (p *parser) errorAt(pos token.Pos, format string, args ...interface{}) {
+	if p.err == nil {
+		p.err = fmt.Errorf("column %d: %s", int(pos), fmt.Sprintf(format, args...))
+	}
+	// stop parsing
+	p.tok = token.EOF
+}
+
+func (p *parser) found() string {
+	switch {
+	case p.tok == token.EOF:
+		return "end of rule"
+	case p.lit != "":
+		return strconv.Quote(p.lit)
+	default:
+		return strconv.Quote(p.tok.String())
+	}
+}
+
+// keyword consumes the identifier kw, if it is next.
+func (p *parser) keyword(kw string) bool {
+	if p.tok == token.IDENT && p.lit == kw {
+		p.next()
+		return true
+	}
+	return false
+}
+
+func (p *parser) expect(tok token.Token) string {
+	lit := p.lit
+	if p.tok != tok {
+		p.errorf("expected %s, found %s", tok, p.found())
+		return ""
+	}
+	p.next()
+	return lit
+}
+
+func (p *parser) kind() string {
+	pos := p.pos
+	kind := p.expect(token.IDENT)
+	if _, ok := kinds[kind]; !ok && p.err == nil {
+		p.errorAt(pos, "unknown kind of node %q", kind)
+	}
+	return kind
+}
+
+func (p *parser) string() string {
+	lit := p.expect(token.STRING)
+	if p.err != nil {
+		return ""
+	}
+	s, err := strconv.Unquote(lit)
+	if err != nil {
+		p.errorf("malformed string %s", lit)
+	}
+	return s
+}
+
+func (p *parser) path() []step {
+	path := []step{{field: p.expect(token.IDENT)}}
+	for p.err == nil {
+		switch p.tok {
+		case token.PERIOD:
+			p.next()
+			path = append(path, step{field: p.expect(token.IDENT)})
+		case token.LBRACK:
+			p.next()
+			pos := p.pos
+			lit := p.expect(token.INT)
+			n, err := strconv.Atoi(lit)
+			if err != nil && p.err == nil {
+				p.errorAt(pos, "invalid index %s", lit)
+			}
+			p.expect(token.RBRACK)
+			path = append(path, step{index: n})
+		default:
+			return path
+		}
+	}
+	return path
+}
+
+func (p *parser) cond() cond {
+	var c cond
+	if p.keyword("not") {
+		c = p.cond()
+		c.not = !c.not
+		return c
+	}
+	c.path = p.path()
+	switch {
+	case p.tok == token.EQL || p.tok == token.NEQ:
+		c.op = p.tok.String()
+		p.next()
+		c.value = p.string()
+	case p.keyword("matches"):
+		c.op = "matches"
+		pos := p.pos
+		c.value = p.string()
+		if p.err == nil {
+			var err error
+			if c.rx, err = regexp.Compile(c.value); err != nil {
+				p.errorAt(pos, "%s", err)
+			}
+		}
+	case p.keyword("is"):
+		c.op = "is"
+		c.value = p.kind()
+	default:
+		p.errorf("expected ==, !=, matches or is, found %s", p.found())
+	}
+	return c
+}
+
+// Parse parses a rule.
+func Parse(src string) (*Rule, error) {
+	p := &parser{}
+	fset := token.NewFileSet()
+	// The file starts at the base of 1, so that positions are
+	// columns.
+	file := fset.AddFile("", fset.Base(), len(src))
+	p.s.Init(file, []byte(src), func(pos token.Position, msg string) {
+		if p.err == nil {
+			p.err = fmt.Errorf("column %d: %s", pos.Column, msg)
+		}
+	}, 0)
+	p.next()
+
+	r := &Rule{}
+	if !p.keyword("report") {
+		p.errorf("expected report, found %s", p.found())
+	}
+	r.Kind = p.kind()
+	if p.keyword("where") {
+		r.conds = append(r.conds, p.cond())
+		for p.keyword("and") {
+			r.conds = append(r.conds, p.cond())
+		}
+	}
+	if p.tok == token.COLON {
+		p.next()
+		r.Message = p.string()
+	}
+	if p.tok != token.EOF {
+		p.errorf("unexpected %s", p.found())
+	}
+	if p.err != nil {
+		return nil, p.err
+	}
+	return r, nil
+}
+
+var nameRx = regexp.MustCompile(`^R[0-9]+$`)
+
+// ValidName reports whether name can be the name of a rule. Like
+// the names of other checks, names consist of the prefix of the
+// checker, R, and a number, such as R1000.
+func ValidName(name string) bool {
+	return nameRx.MatchString(name)
+}
+
+// Checker runs rules as checks, named after the rules.
+type Checker struct {
+	rules map[string]*Rule
+}
+
+// NewChecker returns a checker for rules, which maps the names of
+// rules to their sources.
+func NewChecker(rules map[string]string) (*Checker, error) {
+	c := &Checker{rules: map[string]*Rule{}}
+	for name, src := range rules {
+		if !ValidName(name) {
+			return nil, fmt.Errorf("invalid rule name %q, rule names look like R1000", name)
+		}
+		r, err := Parse(src)
+		if err != nil {
+			return nil, fmt.Errorf("rule %s: %s", name, err)
+		}
+		c.rules[name] = r
+	}
+	return c, nil
+}
+
+func (*Checker) Name() string            { return "rules" }
+func (*Checker) Prefix() string          { return "R" }
+func (*Checker) Init(prog *lint.Program) {}
+
+func (c *Checker) Funcs() map[string]lint.Func {
+	funcs := map[string]lint.Func{}
+	for name, r := range c.rules {
+		name, r := name, r
+		funcs[name] = func(j *lint.Job) {
+			msg := r.Message
+			if msg == "" {
+				msg = fmt.Sprintf("%s matches rule %s", r.Kind, name)
+			}
+			for _, f := range j.Program.Files {
+				ast.Inspect(f, func(node ast.Node) bool {
+					if node != nil && r.Match(node) {
+						j.Errorf(node, "%s", msg)
+					}
+					return true
+				})
+			}
+		}
+	}
+	return funcs
+}
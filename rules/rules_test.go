@@ -0,0 +1,90 @@
+package rules
+
+import (
+	"go/ast"
+	goparser "go/parser"
+	"testing"
+
+	"honnef.co/go/tools/lint/testutil"
+)
+
+func TestAll(t *testing.T) {
+	c, err := NewChecker(map[string]string{
+		"R1000": `report CallExpr where Fun.Sel == "Println" and Args[0] is StringLit and Args[0] matches "\\n$": "Println already adds a newline"`,
+		"R1001": `report CallExpr where Fun == "errors.New" and not Args[0] matches "^[a-z]"`,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	testutil.TestAll(t, c, "")
+}
+
+func TestMatch(t *testing.T) {
+	tests := []struct {
+		rule string
+		expr string
+		want bool
+	}{
+		{`report CallExpr`, `f()`, true},
+		{`report CallExpr`, `x`, false},
+		{`report CallExpr where Fun == "f"`, `f()`, true},
+		{`report CallExpr where Fun != "f"`, `f()`, false},
+		{`report CallExpr where Fun == "a.b"`, `a.b(1)`, true},
+		{`report CallExpr where Args[1] == "2"`, `f(1, 2)`, true},
+		{`report CallExpr where Args[1] == "2"`, `f(1)`, false},
+		{`report CallExpr where not Args[1] == "2"`, `f(1)`, true},
+		{`report CallExpr where Args[0] is StringLit`, `f("")`, true},
+		{`report CallExpr where Args[0] is StringLit`, "f('x')", false},
+		{`report BinaryExpr where Op == "==" and Y == "nil"`, `x == nil`, true},
+		{`report BinaryExpr where Op == "=="`, `x != nil`, false},
+		{`report StringLit where Value matches "^\"a"`, `"abc"`, true},
+		{`report SelectorExpr where X.Nope == "x"`, `x.y`, false},
+	}
+	for _, tt := range tests {
+		r, err := Parse(tt.rule)
+		if err != nil {
+			t.Errorf("Parse(%q): %s", tt.rule, err)
+			continue
+		}
+		expr, err := goparser.ParseExpr(tt.expr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := r.Match(expr); got != tt.want {
+			t.Errorf("rule %q matched %s: got %t, want %t", tt.rule, tt.expr, got, tt.want)
+		}
+	}
+
+	// StringLit and BasicLit are distinct from the node that has
+	// a string literal as a child
+	r, _ := Parse(`report StringLit`)
+	if r.Match(&ast.Ident{Name: "x"}) {
+		t.Error("StringLit matched an identifier")
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		rule string
+		err  string
+	}{
+		{`CallExpr`, `column 1: expected report, found "CallExpr"`},
+		{`report Call`, `column 8: unknown kind of node "Call"`},
+		{`report CallExpr where`, `column 22: expected IDENT, found end of rule`},
+		{`report CallExpr where Fun = "f"`, `column 27: expected ==, !=, matches or is, found "="`},
+		{`report CallExpr where Fun == f`, `column 30: expected STRING, found "f"`},
+		{`report CallExpr where Args[x] == "f"`, `column 28: expected INT, found "x"`},
+		{`report CallExpr where Fun matches "("`, "column 35: error parsing regexp: missing closing ): `(`"},
+		{`report CallExpr: "msg" extra`, `column 24: unexpected "extra"`},
+		{`report CallExpr: "msg`, `column 18: string literal not terminated`},
+	}
+	for _, tt := range tests {
+		_, err := Parse(tt.rule)
+		if err == nil || err.Error() != tt.err {
+			t.Errorf("Parse(%q) returned error %v, want %q", tt.rule, err, tt.err)
+		}
+	}
+	if _, err := NewChecker(map[string]string{"CUSTOM1": "report CallExpr"}); err == nil {
+		t.Error("NewChecker accepted an invalid rule name")
+	}
+}
@@ -0,0 +1,17 @@
+package pkg
+
+import (
+	"errors"
+	"fmt"
+)
+
+func fn() error {
+	fmt.Println("hello\n") // MATCH "Println already adds a newline"
+	fmt.Println("hello")
+	fmt.Println()
+	fmt.Print("hello\n")
+	if false {
+		return errors.New("Something failed") // MATCH "CallExpr matches rule R1001"
+	}
+	return errors.New("something failed")
+}
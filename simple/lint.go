@@ -7,6 +7,7 @@ import (
 	"go/token"
 	"go/types"
 	"reflect"
+	"strconv"
 	"strings"
 
 	"honnef.co/go/tools/internal/sharedcheck"
@@ -67,6 +68,23 @@ func (c *Checker) Funcs() map[string]lint.Func {
 		"S1030": c.LintBytesBufferConversions,
 		"S1031": c.LintNilCheckAroundRange,
 		"S1032": c.LintSortHelpers,
+		"S1033": c.LintUnnecessaryElse,
+		"S1034": c.LintRedundantConstantConversion,
+		"S1035": c.LintRepeatedMapAccess,
+		"S1036": c.LintRoundTripConversion,
+		"S1037": c.LintRoundTripParse,
+	}
+}
+
+func (c *Checker) Info() map[string]lint.CheckInfo {
+	return map[string]lint.CheckInfo{
+		"S1010": {Fixable: true},
+		"S1023": {Fixable: true},
+		"S1033": {Fixable: true, Since: "2019.2"},
+		"S1034": {Fixable: true, Since: "2019.2"},
+		"S1035": {Since: "2019.2"},
+		"S1036": {Fixable: true, Since: "2019.2"},
+		"S1037": {Since: "2019.2"},
 	}
 }
 
@@ -689,7 +707,19 @@ func (c *Checker) LintSlicing(j *lint.Job) {
 		if !ok || arg.Obj != s.Obj {
 			return true
 		}
-		j.Errorf(n, "should omit second index in slice, s[a:len(s)] is identical to s[a:]")
+		if IsIntLiteral(n.Low, "0") {
+			p := j.Errorf(n, "should omit both indices in slice, s[0:len(s)] is identical to s[:]")
+			p.Fixes = []lint.SuggestedFix{{
+				Message: "omit both indices",
+				Edits:   []lint.TextEdit{j.Edit(n.Low.Pos(), n.High.End(), ":")},
+			}}
+			return true
+		}
+		p := j.Errorf(n, "should omit second index in slice, s[a:len(s)] is identical to s[a:]")
+		p.Fixes = []lint.SuggestedFix{{
+			Message: "omit second index",
+			Edits:   []lint.TextEdit{j.Edit(n.High.Pos(), n.High.End(), "")},
+		}}
 		return true
 	}
 	for _, f := range c.filterGenerated(j.Program.Files) {
@@ -1512,7 +1542,34 @@ func (c *Checker) LintRedundantBreak(j *lint.Job) {
 		}
 		// we don't need to check rst.Results as we already
 		// checked x.Type.Results to be nil.
-		j.Errorf(rst, "redundant return statement")
+		p := j.Errorf(rst, "redundant return statement")
+
+		fset := j.Program.SSA.Fset
+		start, end := rst.Pos(), rst.End()
+		line := fset.PositionFor(rst.Pos(), false).Line
+		prev := body.Lbrace
+		if len(body.List) > 1 {
+			prev = body.List[len(body.List)-2].End()
+		}
+		if fset.PositionFor(prev, false).Line < line {
+			// the return statement is on a line of its own; remove
+			// the whole line, unless it has a comment we'd have to
+			// move elsewhere
+			commented := false
+			for _, cg := range j.File(rst).Comments {
+				if cg.Pos() > rst.End() && fset.PositionFor(cg.Pos(), false).Line == line {
+					commented = true
+					break
+				}
+			}
+			if !commented {
+				start -= token.Pos(fset.PositionFor(rst.Pos(), false).Column)
+			}
+		}
+		p.Fixes = []lint.SuggestedFix{{
+			Message: "remove return statement",
+			Edits:   []lint.TextEdit{j.Edit(start, end, "")},
+		}}
 	}
 	fn := func(node ast.Node) bool {
 		fn1(node)
@@ -1737,3 +1794,776 @@ func (c *Checker) LintSortHelpers(j *lint.Job) {
 		ast.Inspect(f, fnFuncs)
 	}
 }
+
+// isTerminating reports whether stmt unconditionally transfers
+// control elsewhere.
+func isTerminating(j *lint.Job, stmt ast.Stmt) bool {
+	switch stmt := stmt.(type) {
+	case *ast.ReturnStmt:
+		return true
+	case *ast.BranchStmt:
+		return stmt.Tok != token.FALLTHROUGH
+	case *ast.ExprStmt:
+		call, ok := stmt.X.(*ast.CallExpr)
+		if !ok {
+			return false
+		}
+		ident, ok := call.Fun.(*ast.Ident)
+		if !ok {
+			return false
+		}
+		_, ok = ObjectOf(j, ident).(*types.Builtin)
+		return ok && ident.Name == "panic"
+	default:
+		return false
+	}
+}
+
+func terminatingKind(stmt ast.Stmt) string {
+	switch stmt := stmt.(type) {
+	case *ast.ReturnStmt:
+		return "return"
+	case *ast.BranchStmt:
+		return stmt.Tok.String()
+	default:
+		return "panic"
+	}
+}
+
+// declaresNames reports whether any of stmts declares a name in the
+// scope of the block they belong to.
+func declaresNames(stmts []ast.Stmt) bool {
+	for _, stmt := range stmts {
+		switch stmt := stmt.(type) {
+		case *ast.DeclStmt:
+			return true
+		case *ast.AssignStmt:
+			if stmt.Tok == token.DEFINE {
+				return true
+			}
+		case *ast.LabeledStmt:
+			return true
+		}
+	}
+	return false
+}
+
+func (c *Checker) LintUnnecessaryElse(j *lint.Job) {
+	chained := map[*ast.IfStmt]bool{}
+	fn := func(node ast.Node) bool {
+		ifstmt, ok := node.(*ast.IfStmt)
+		if !ok {
+			return true
+		}
+		if elseif, ok := ifstmt.Else.(*ast.IfStmt); ok {
+			chained[elseif] = true
+			return true
+		}
+		els, ok := ifstmt.Else.(*ast.BlockStmt)
+		if !ok || chained[ifstmt] {
+			// we can only dedent the else branch if the if
+			// statement isn't itself part of an else branch
+			return true
+		}
+		if ifstmt.Init != nil {
+			// variables declared in the init statement may be
+			// used in the else branch
+			return true
+		}
+		if len(ifstmt.Body.List) == 0 || !isTerminating(j, ifstmt.Body.List[len(ifstmt.Body.List)-1]) {
+			return true
+		}
+		p := j.Errorf(els, "if block ends with a %s statement, so drop this else and outdent its block",
+			terminatingKind(ifstmt.Body.List[len(ifstmt.Body.List)-1]))
+		if declaresNames(els.List) {
+			// moving the declarations into the outer scope might
+			// conflict with existing names
+			return true
+		}
+		rbrace := els.Rbrace
+		fset := j.Program.SSA.Fset
+		pos := fset.PositionFor(els.Rbrace, false)
+		last := els.Lbrace
+		if len(els.List) > 0 {
+			last = els.List[len(els.List)-1].End()
+		}
+		if pos.Line > fset.PositionFor(last, false).Line {
+			// the closing brace is on a line of its own, remove
+			// the whole line
+			rbrace -= token.Pos(pos.Column)
+		}
+		p.Fixes = []lint.SuggestedFix{{
+			Message: "remove else and outdent its block",
+			Edits: []lint.TextEdit{
+				j.Edit(ifstmt.Body.Rbrace+1, els.Lbrace+1, ""),
+				j.Edit(rbrace, els.Rbrace+1, ""),
+			},
+		}}
+		return true
+	}
+	for _, f := range c.filterGenerated(j.Program.Files) {
+		ast.Inspect(f, fn)
+	}
+}
+
+// untypedConstant returns the type an untyped constant expression
+// would default to. It returns false if expr isn't an untyped
+// constant.
+func untypedConstant(j *lint.Job, expr ast.Expr) (types.Type, bool) {
+	switch expr := expr.(type) {
+	case *ast.BasicLit:
+		switch expr.Kind {
+		case token.INT:
+			return types.Typ[types.Int], true
+		case token.FLOAT:
+			return types.Typ[types.Float64], true
+		case token.IMAG:
+			return types.Typ[types.Complex128], true
+		case token.CHAR:
+			return types.Universe.Lookup("rune").Type(), true
+		case token.STRING:
+			return types.Typ[types.String], true
+		}
+	case *ast.UnaryExpr:
+		if expr.Op == token.SUB || expr.Op == token.ADD {
+			if lit, ok := expr.X.(*ast.BasicLit); ok {
+				return untypedConstant(j, lit)
+			}
+		}
+	case *ast.Ident:
+		obj, ok := ObjectOf(j, expr).(*types.Const)
+		if !ok {
+			return nil, false
+		}
+		if basic, ok := obj.Type().(*types.Basic); ok && basic.Info()&types.IsUntyped != 0 {
+			return types.Default(obj.Type()), true
+		}
+	}
+	return nil, false
+}
+
+// assignableConstant reports whether the untyped constant converted
+// by call, whose default type is def, could be assigned to the type
+// of the conversion without it. That requires the constant to be of
+// a compatible kind, such as not an integer converted to a string,
+// and to not change its value.
+func assignableConstant(j *lint.Job, call *ast.CallExpr, def types.Type) bool {
+	basic, ok := TypeOf(j, call).Underlying().(*types.Basic)
+	if !ok {
+		return false
+	}
+	from := def.(*types.Basic).Info()
+	to := basic.Info()
+	switch {
+	case from&types.IsString != 0:
+		if to&types.IsString == 0 {
+			return false
+		}
+	case from&types.IsBoolean != 0:
+		if to&types.IsBoolean == 0 {
+			return false
+		}
+	case from&types.IsNumeric != 0:
+		if to&types.IsNumeric == 0 {
+			return false
+		}
+	default:
+		return false
+	}
+	orig := j.Program.Info.Types[call.Args[0]].Value
+	conv := j.Program.Info.Types[call].Value
+	if orig == nil || conv == nil {
+		return false
+	}
+	return constant.Compare(orig, token.EQL, conv)
+}
+
+func (c *Checker) LintRedundantConstantConversion(j *lint.Job) {
+	// check flags expr if it converts an untyped constant to T, when
+	// the constant would have been given type T anyway. If expected
+	// is nil, the constant would be given its default type.
+	check := func(expr ast.Expr, expected types.Type) {
+		call, ok := expr.(*ast.CallExpr)
+		if !ok || len(call.Args) != 1 || !j.Program.Info.Types[call.Fun].IsType() {
+			return
+		}
+		def, ok := untypedConstant(j, call.Args[0])
+		if !ok {
+			return
+		}
+		if expected == nil {
+			expected = def
+		} else if !assignableConstant(j, call, def) {
+			return
+		}
+		T := TypeOf(j, call)
+		if !types.Identical(T, expected) {
+			return
+		}
+		if _, ok := T.Underlying().(*types.Interface); ok {
+			return
+		}
+		p := j.Errorf(call, "unnecessary conversion of constant %s to %s", Render(j, call.Args[0]), Render(j, call.Fun))
+		p.Fixes = []lint.SuggestedFix{{
+			Message: "remove conversion",
+			Edits:   []lint.TextEdit{j.Edit(call.Pos(), call.End(), Render(j, call.Args[0]))},
+		}}
+	}
+	fn := func(node ast.Node) bool {
+		switch node := node.(type) {
+		case *ast.ValueSpec:
+			var expected types.Type
+			if node.Type != nil {
+				expected = TypeOf(j, node.Type)
+			}
+			for _, val := range node.Values {
+				check(val, expected)
+			}
+		case *ast.AssignStmt:
+			if len(node.Lhs) != len(node.Rhs) {
+				return true
+			}
+			switch node.Tok {
+			case token.DEFINE:
+				for _, rhs := range node.Rhs {
+					check(rhs, nil)
+				}
+			case token.ASSIGN:
+				for i, rhs := range node.Rhs {
+					if IsBlank(node.Lhs[i]) {
+						// a blank identifier has no type to infer
+						// from
+						continue
+					}
+					check(rhs, TypeOf(j, node.Lhs[i]))
+				}
+			}
+		case *ast.CallExpr:
+			if node.Ellipsis.IsValid() || j.Program.Info.Types[node.Fun].IsType() {
+				return true
+			}
+			if ident, ok := node.Fun.(*ast.Ident); ok {
+				if _, ok := ObjectOf(j, ident).(*types.Builtin); ok {
+					// builtins like print accept arguments of any
+					// type, so the conversion may matter
+					return true
+				}
+			}
+			sig, ok := TypeOf(j, node.Fun).Underlying().(*types.Signature)
+			if !ok {
+				return true
+			}
+			params := sig.Params()
+			for i, arg := range node.Args {
+				var T types.Type
+				if sig.Variadic() && i >= params.Len()-1 {
+					T = params.At(params.Len() - 1).Type().(*types.Slice).Elem()
+				} else {
+					T = params.At(i).Type()
+				}
+				check(arg, T)
+			}
+		}
+		return true
+	}
+	for _, f := range c.filterGenerated(j.Program.Files) {
+		ast.Inspect(f, fn)
+	}
+}
+
+// isBuiltinCall reports whether call calls one of the named
+// builtin functions.
+func isBuiltinCall(j *lint.Job, call *ast.CallExpr, names ...string) bool {
+	ident, ok := call.Fun.(*ast.Ident)
+	if !ok {
+		return false
+	}
+	if _, ok := ObjectOf(j, ident).(*types.Builtin); !ok {
+		return false
+	}
+	for _, name := range names {
+		if ident.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// isUnsharedLocalMap reports whether expr is a local variable that
+// only ever holds maps created in its own function, and whose value
+// and address never escape it, so that calls can't modify the map
+// without being passed it.
+func isUnsharedLocalMap(j *lint.Job, expr ast.Expr) bool {
+	ident, ok := expr.(*ast.Ident)
+	if !ok {
+		return false
+	}
+	obj, ok := ObjectOf(j, ident).(*types.Var)
+	if !ok || obj.Pkg() == nil || obj.Parent() == nil || obj.Parent() == obj.Pkg().Scope() {
+		return false
+	}
+	fresh := func(expr ast.Expr) bool {
+		switch expr := expr.(type) {
+		case *ast.CompositeLit:
+			return true
+		case *ast.CallExpr:
+			return isBuiltinCall(j, expr, "make")
+		default:
+			return false
+		}
+	}
+	// closure returns the innermost function literal in stack
+	closure := func(stack []ast.Node) ast.Node {
+		for i := len(stack) - 1; i >= 0; i-- {
+			if lit, ok := stack[i].(*ast.FuncLit); ok {
+				return lit
+			}
+		}
+		return nil
+	}
+	declared := false
+	var declClosure ast.Node
+	var useClosures []ast.Node
+	escapes := false
+	var stack []ast.Node
+	ast.Inspect(j.File(ident), func(node ast.Node) bool {
+		if node == nil {
+			stack = stack[:len(stack)-1]
+			return true
+		}
+		stack = append(stack, node)
+		id, ok := node.(*ast.Ident)
+		if !ok || ObjectOf(j, id) != obj || len(stack) < 2 {
+			return true
+		}
+		ok = false
+		switch parent := stack[len(stack)-2].(type) {
+		case *ast.IndexExpr:
+			ok = parent.X == id
+		case *ast.CallExpr:
+			ok = isBuiltinCall(j, parent, "len", "delete") && parent.Args[0] == id
+		case *ast.RangeStmt:
+			ok = parent.X == id
+		case *ast.AssignStmt:
+			for i, lhs := range parent.Lhs {
+				if lhs == id && len(parent.Lhs) == len(parent.Rhs) {
+					ok = fresh(parent.Rhs[i])
+				}
+			}
+		case *ast.ValueSpec:
+			for i, name := range parent.Names {
+				if name == id {
+					ok = len(parent.Values) == 0 || (len(parent.Values) == len(parent.Names) && fresh(parent.Values[i]))
+				}
+			}
+		}
+		if !ok {
+			escapes = true
+		}
+		if j.Program.Info.Defs[id] == obj {
+			declared = true
+			declClosure = closure(stack)
+		} else {
+			useClosures = append(useClosures, closure(stack))
+		}
+		return true
+	})
+	if !declared || escapes {
+		return false
+	}
+	for _, c := range useClosures {
+		// the map is used by a closure, which may get called
+		if c != declClosure {
+			return false
+		}
+	}
+	return true
+}
+
+func (c *Checker) LintRepeatedMapAccess(j *lint.Job) {
+	// isPure reports whether evaluating expr can't have side effects
+	// and always yields the same value, as long as none of the
+	// identifiers in it get assigned to.
+	var isPure func(expr ast.Expr) bool
+	isPure = func(expr ast.Expr) bool {
+		switch expr := expr.(type) {
+		case *ast.Ident, *ast.BasicLit:
+			return true
+		case *ast.SelectorExpr:
+			return isPure(expr.X)
+		case *ast.ParenExpr:
+			return isPure(expr.X)
+		default:
+			return false
+		}
+	}
+	identNames := func(node ast.Node) map[string]bool {
+		names := map[string]bool{}
+		ast.Inspect(node, func(node ast.Node) bool {
+			if ident, ok := node.(*ast.Ident); ok {
+				names[ident.Name] = true
+			}
+			return true
+		})
+		return names
+	}
+
+	fn := func(node ast.Node) bool {
+		ifstmt, ok := node.(*ast.IfStmt)
+		if !ok {
+			return true
+		}
+		var candidates []*ast.IndexExpr
+		collect := func(node ast.Node) bool {
+			switch node := node.(type) {
+			case *ast.FuncLit:
+				return false
+			case *ast.IndexExpr:
+				if _, ok := TypeOf(j, node.X).Underlying().(*types.Map); !ok {
+					return true
+				}
+				if isPure(node.X) && isPure(node.Index) {
+					candidates = append(candidates, node)
+				}
+			}
+			return true
+		}
+		if ifstmt.Init != nil {
+			ast.Inspect(ifstmt.Init, collect)
+		}
+		ast.Inspect(ifstmt.Cond, collect)
+
+		for _, index := range candidates {
+			s := Render(j, index)
+			m := Render(j, index.X)
+			names := identNames(index)
+			// calls may modify maps they aren't passed, for example
+			// through a receiver or a package-level variable, unless
+			// nothing else can refer to the map
+			unshared := isUnsharedLocalMap(j, index.X)
+			// callModifies reports whether call may change the map
+			// before it is read again
+			callModifies := func(call *ast.CallExpr) bool {
+				if j.Program.Info.Types[call.Fun].IsType() {
+					return false
+				}
+				for _, arg := range call.Args {
+					// functions that get passed the map may modify it
+					if Render(j, arg) == m {
+						return true
+					}
+				}
+				if unshared || isBuiltinCall(j, call, "len", "cap", "append", "make", "new", "copy", "print", "println", "complex", "real", "imag") {
+					return false
+				}
+				// arguments are evaluated before the call, so calls
+				// that contain the second access don't get between
+				// the two accesses
+				contains := false
+				ast.Inspect(call, func(node ast.Node) bool {
+					if node, ok := node.(*ast.IndexExpr); ok && Render(j, node) == s {
+						contains = true
+					}
+					return !contains
+				})
+				return !contains
+			}
+			modified := false
+			// calls in the condition, after the first access
+			inspectCalls := func(node ast.Node) {
+				ast.Inspect(node, func(node ast.Node) bool {
+					if call, ok := node.(*ast.CallExpr); ok && call.Pos() > index.End() && callModifies(call) {
+						modified = true
+					}
+					return !modified
+				})
+			}
+			if ifstmt.Init != nil {
+				inspectCalls(ifstmt.Init)
+			}
+			inspectCalls(ifstmt.Cond)
+			// modifies reports whether node may change the map, the
+			// key or what the identifiers refer to
+			modifies := func(node ast.Node) bool {
+				for name := range identNames(node) {
+					if names[name] {
+						return true
+					}
+				}
+				return false
+			}
+			found := false
+			ast.Inspect(ifstmt.Body, func(node ast.Node) bool {
+				switch node := node.(type) {
+				case *ast.AssignStmt:
+					for _, lhs := range node.Lhs {
+						modified = modified || modifies(lhs)
+					}
+				case *ast.IncDecStmt:
+					modified = modified || modifies(node.X)
+				case *ast.ValueSpec:
+					for _, name := range node.Names {
+						modified = modified || names[name.Name]
+					}
+				case *ast.RangeStmt:
+					if node.Key != nil {
+						modified = modified || modifies(node.Key)
+					}
+					if node.Value != nil {
+						modified = modified || modifies(node.Value)
+					}
+				case *ast.UnaryExpr:
+					if node.Op == token.AND {
+						modified = modified || modifies(node.X)
+					}
+				case *ast.CallExpr:
+					if !found && callModifies(node) {
+						modified = true
+					}
+				case *ast.IndexExpr:
+					if Render(j, node) == s {
+						found = true
+					}
+				}
+				return !modified
+			})
+			if found && !modified {
+				j.Errorf(index, "should read the map only once, e.g. with v, ok := %s, instead of evaluating %s repeatedly", s, s)
+				break
+			}
+		}
+		return true
+	}
+	for _, f := range c.filterGenerated(j.Program.Files) {
+		ast.Inspect(f, fn)
+	}
+}
+
+// renderOperand renders expr so that it can replace a primary
+// expression, such as a call, parenthesizing it if necessary.
+func renderOperand(j *lint.Job, expr ast.Expr) string {
+	switch expr.(type) {
+	case *ast.BinaryExpr, *ast.UnaryExpr, *ast.StarExpr:
+		return "(" + Render(j, expr) + ")"
+	}
+	return Render(j, expr)
+}
+
+func (c *Checker) LintRoundTripConversion(j *lint.Job) {
+	// conversion returns the operand of expr if expr is a type
+	// conversion.
+	conversion := func(expr ast.Expr) (ast.Expr, bool) {
+		call, ok := expr.(*ast.CallExpr)
+		if !ok || len(call.Args) != 1 || !j.Program.Info.Types[call.Fun].IsType() {
+			return nil, false
+		}
+		return call.Args[0], true
+	}
+	isBasic := func(T types.Type, kind types.BasicKind) bool {
+		basic, ok := T.Underlying().(*types.Basic)
+		return ok && basic.Kind() == kind
+	}
+	isByteSlice := func(T types.Type) bool {
+		slice, ok := T.Underlying().(*types.Slice)
+		return ok && isBasic(slice.Elem(), types.Byte)
+	}
+	// bytesLiteral returns the string consisting of the elements of
+	// lit, if they are all constant bytes.
+	bytesLiteral := func(lit *ast.CompositeLit) (string, bool) {
+		var b []byte
+		for _, elt := range lit.Elts {
+			if _, ok := elt.(*ast.KeyValueExpr); ok {
+				return "", false
+			}
+			n, ok := ExprToInt(j, elt)
+			if !ok {
+				return "", false
+			}
+			b = append(b, byte(n))
+		}
+		return string(b), true
+	}
+
+	fn := func(node ast.Node) bool {
+		call, ok := node.(*ast.CallExpr)
+		if !ok {
+			return true
+		}
+		outer, ok := conversion(call)
+		if !ok {
+			return true
+		}
+		T := TypeOf(j, call)
+		if lit, ok := outer.(*ast.CompositeLit); ok && IsType(T, "string") && isByteSlice(TypeOf(j, lit)) {
+			s, ok := bytesLiteral(lit)
+			if !ok {
+				return true
+			}
+			q := strconv.Quote(s)
+			p := j.Errorf(node, "should use %s instead of %s", q, Render(j, node))
+			p.Fixes = []lint.SuggestedFix{{
+				Message: "use a string literal",
+				Edits:   []lint.TextEdit{j.Edit(node.Pos(), node.End(), q)},
+			}}
+			return true
+		}
+		inner, ok := conversion(outer)
+		if !ok {
+			return true
+		}
+		if !types.Identical(T, TypeOf(j, inner)) {
+			return true
+		}
+		mid := TypeOf(j, outer)
+		switch {
+		case isBasic(T, types.String) && isByteSlice(mid):
+			// strings are immutable, which makes the round-trip a
+			// no-op
+			p := j.Errorf(node, "should use %s instead of %s", Render(j, inner), Render(j, node))
+			p.Fixes = []lint.SuggestedFix{{
+				Message: "remove conversions",
+				Edits:   []lint.TextEdit{j.Edit(node.Pos(), node.End(), renderOperand(j, inner))},
+			}}
+		case isByteSlice(T) && isBasic(mid, types.String):
+			// the round-trip copies the slice, which may be
+			// intentional, so we don't offer a fix
+			j.Errorf(node, "should use %s instead of %s, unless a copy of %s is needed",
+				Render(j, inner), Render(j, node), Render(j, inner))
+		}
+		return true
+	}
+	for _, f := range c.filterGenerated(j.Program.Files) {
+		ast.Inspect(f, fn)
+	}
+}
+
+// roundTripParse describes a parse function, the arguments it has to
+// be called with, in addition to the string, to parse exactly what
+// has been formatted, and the ways of formatting its result.
+type roundTripParse struct {
+	args []string
+	// typ is the type of the parsed value
+	typ string
+	// verbs are the fmt verbs that format values of type typ in a
+	// way that parsing them yields the same value
+	verbs []string
+	// format is the strconv function that is the inverse of the
+	// parse function, when called with formatArgs. Each element of
+	// formatArgs lists the valid arguments at that position.
+	format     string
+	formatArgs [][]string
+}
+
+var roundTripParses = map[string]roundTripParse{
+	"strconv.Atoi": {
+		typ:    "int",
+		verbs:  []string{"%d", "%v"},
+		format: "strconv.Itoa",
+	},
+	"strconv.ParseInt": {
+		args:       []string{"10", "64"},
+		typ:        "int64",
+		verbs:      []string{"%d", "%v"},
+		format:     "strconv.FormatInt",
+		formatArgs: [][]string{{"10"}},
+	},
+	"strconv.ParseUint": {
+		args:       []string{"10", "64"},
+		typ:        "uint64",
+		verbs:      []string{"%d", "%v"},
+		format:     "strconv.FormatUint",
+		formatArgs: [][]string{{"10"}},
+	},
+	"strconv.ParseFloat": {
+		args: []string{"64"},
+		typ:  "float64",
+		// %f and %e use a fixed precision and may round the value
+		verbs:      []string{"%g", "%v"},
+		format:     "strconv.FormatFloat",
+		formatArgs: [][]string{{"'g'", "'e'", "'f'"}, {"-1"}, {"64"}},
+	},
+	"strconv.ParseBool": {
+		typ:    "bool",
+		verbs:  []string{"%t", "%v"},
+		format: "strconv.FormatBool",
+	},
+}
+
+func (c *Checker) LintRoundTripParse(j *lint.Job) {
+	// formatted returns the value that expr formats, if it formats a
+	// single value in a way that rt parses exactly.
+	formatted := func(expr ast.Expr, rt roundTripParse) (ast.Expr, bool) {
+		call, ok := expr.(*ast.CallExpr)
+		if !ok {
+			return nil, false
+		}
+		switch {
+		case IsCallToAST(j, call, "fmt.Sprintf"):
+			if len(call.Args) != 2 {
+				return nil, false
+			}
+			f, ok := ExprToString(j, call.Args[0])
+			if !ok {
+				return nil, false
+			}
+			for _, verb := range rt.verbs {
+				if f == verb {
+					return call.Args[1], true
+				}
+			}
+			return nil, false
+		case IsCallToAST(j, call, "fmt.Sprint"):
+			if len(call.Args) != 1 {
+				return nil, false
+			}
+			return call.Args[0], true
+		case IsCallToAST(j, call, rt.format):
+			if len(call.Args) != len(rt.formatArgs)+1 {
+				return nil, false
+			}
+		args:
+			for i, valid := range rt.formatArgs {
+				arg := Render(j, call.Args[i+1])
+				for _, v := range valid {
+					if arg == v {
+						continue args
+					}
+				}
+				return nil, false
+			}
+			return call.Args[0], true
+		default:
+			return nil, false
+		}
+	}
+
+	fn := func(node ast.Node) bool {
+		call, ok := node.(*ast.CallExpr)
+		if !ok {
+			return true
+		}
+		for name, rt := range roundTripParses {
+			if !IsCallToAST(j, call, name) {
+				continue
+			}
+			if len(call.Args) != len(rt.args)+1 {
+				return true
+			}
+			for i, arg := range rt.args {
+				if Render(j, call.Args[i+1]) != arg {
+					return true
+				}
+			}
+			v, ok := formatted(call.Args[0], rt)
+			if !ok || !IsOfType(j, v, rt.typ) {
+				return true
+			}
+			j.Errorf(call, "should use %s directly instead of formatting and parsing it", Render(j, v))
+			return true
+		}
+		return true
+	}
+	for _, f := range c.filterGenerated(j.Program.Files) {
+		ast.Inspect(f, fn)
+	}
+}
@@ -0,0 +1,46 @@
+package pkg
+
+import "time"
+
+type MyInt int
+
+const untyped = 5
+const typed int64 = 5
+
+func fn1(x float64)            {}
+func fn2(x int, ys ...float64) {}
+func fn3(x interface{})        {}
+
+func fn() {
+	const c1 = int(5)            // MATCH "unnecessary conversion of constant 5 to int"
+	var v1 = float64(3.0)        // MATCH "unnecessary conversion of constant 3.0 to float64"
+	var v2 int = int(-1)         // MATCH "unnecessary conversion of constant -1 to int"
+	v3 := string("foo")          // MATCH /unnecessary conversion of constant "foo" to string/
+	v4 := int(untyped)           // MATCH "unnecessary conversion of constant untyped to int"
+	v5 := rune('a')              // MATCH "unnecessary conversion of constant 'a' to rune"
+	fn1(float64(3))              // MATCH "unnecessary conversion of constant 3 to float64"
+	fn2(int(1), 2.5)             // MATCH "unnecessary conversion of constant 1 to int"
+	time.Sleep(time.Duration(5)) // MATCH "unnecessary conversion of constant 5 to time.Duration"
+	v6 := MyInt(0)
+	v6 = MyInt(1)              // MATCH "unnecessary conversion of constant 1 to MyInt"
+	var v14 uint8 = uint8('a') // MATCH "unnecessary conversion of constant 'a' to uint8"
+
+	// these conversions change the type
+	const c2 = int64(5)
+	v7 := float64(3)
+	v8 := MyInt(1)
+	var v9 = uint8(255)
+	var v10 float64 = float64(1) / 3
+	fn3(int64(1))
+	fn1(float64(typed))
+	println(float64(1))
+	v11 := time.Duration(1)
+	_ = int(5)
+
+	// the constants can't be assigned without the conversion
+	var v12 string = string(65)
+	var v13 string = string('a')
+
+	_, _, _, _, _, _, _, _, _, _, _ = c1, v1, v2, v3, v4, v5, v6, c2, v7, v8, v9
+	_, _, _, _, _ = v10, v11, v12, v13, v14
+}
@@ -38,3 +38,33 @@ func fn8() {
 		return // MATCH /redundant return/
 	}
 }
+
+func fn9() {
+	defer func() {
+		println("foo")
+		return // MATCH /redundant return/
+	}()
+	println("bar")
+	return
+	// MATCH:48 /redundant return/
+}
+
+func fn10(b bool) {
+	if b {
+		goto end
+	}
+	println("foo")
+end:
+	return
+}
+
+func fn11(b bool) {
+	for b {
+		return
+	}
+	println("foo")
+}
+
+func fn12() { return }
+
+// MATCH:68 /redundant return/
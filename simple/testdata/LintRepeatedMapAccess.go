@@ -0,0 +1,118 @@
+package pkg
+
+type T struct {
+	m map[string]*int
+}
+
+func (t T) update() { t.m["foo"] = nil }
+
+var global = map[string]*int{}
+
+func use(*int)              {}
+func clear(map[string]*int) {}
+func key() string           { return "" }
+func mutate()               { global["foo"] = nil }
+func other() bool           { return true }
+
+func fn(m map[string]*int, k string, t T, s []*int) {
+	if m[k] != nil { // MATCH "should read the map only once, e.g. with v, ok := m[k], instead of evaluating m[k] repeatedly"
+		use(m[k])
+	}
+
+	if _, ok := m[k]; ok { // MATCH "should read the map only once"
+		use(m[k])
+	}
+
+	if t.m["foo"] != nil { // MATCH "should read the map only once"
+		use(t.m["foo"])
+	}
+
+	// a single access
+	if v := m[k]; v != nil {
+		use(v)
+	}
+	if m[k] != nil {
+		use(nil)
+	}
+
+	// different keys
+	if m[k] != nil {
+		use(m["other"])
+	}
+
+	// keys that aren't pure expressions
+	if m[key()] != nil {
+		use(m[key()])
+	}
+
+	// slices aren't maps
+	if s[0] != nil {
+		use(s[0])
+	}
+
+	// the key changes
+	if m[k] != nil {
+		k = "other"
+		use(m[k])
+	}
+
+	// the map entry changes
+	if m[k] == nil {
+		m[k] = new(int)
+		use(m[k])
+	}
+	if m[k] != nil {
+		delete(m, k)
+		use(m[k])
+	}
+	if m[k] != nil {
+		clear(m)
+		use(m[k])
+	}
+
+	// the key gets shadowed
+	if m[k] != nil {
+		k := "other"
+		use(m[k])
+	}
+
+	// calls may modify maps they aren't passed
+	if t.m["foo"] != nil {
+		t.update()
+		use(t.m["foo"])
+	}
+	if global["foo"] != nil {
+		mutate()
+		use(global["foo"])
+	}
+	if m[k] != nil && other() {
+		use(m[k])
+	}
+	if m[k] != nil {
+		use(nil)
+		use(m[k])
+	}
+}
+
+func fn2(k string) {
+	// nothing but this function can refer to the map
+	local := map[string]*int{}
+	if local[k] != nil { // MATCH "should read the map only once"
+		mutate()
+		use(local[k])
+	}
+
+	shared := map[string]*int{}
+	f := func() { delete(shared, "foo") }
+	if shared[k] != nil {
+		f()
+		use(shared[k])
+	}
+
+	escaped := map[string]*int{}
+	global = escaped
+	if escaped[k] != nil {
+		mutate()
+		use(escaped[k])
+	}
+}
@@ -0,0 +1,32 @@
+package pkg
+
+type MyString string
+
+type MyBytes []byte
+
+func fn(s string, b []byte, ms MyString, mb MyBytes) {
+	_ = string([]byte(s))        // MATCH "should use s instead of string([]byte(s))"
+	_ = string([]byte("abc"))    // MATCH "should use "abc" instead of string([]byte("abc"))"
+	_ = MyString([]byte(ms))     // MATCH "should use ms instead of MyString([]byte(ms))"
+	_ = string([]byte{'a', 'b'}) // MATCH "should use "ab" instead of string([]byte{'a', 'b'})"
+	_ = string([]byte{0, 0x7f})  // MATCH "should use "\x00\x7f" instead of string([]byte{0, 0x7f})"
+	_ = []byte(string(b))        // MATCH "should use b instead of []byte(string(b)), unless a copy of b is needed"
+	_ = MyBytes(string(mb))      // MATCH "should use mb instead of MyBytes(string(mb)), unless a copy of mb is needed"
+
+	// the fix keeps the precedence of the operand
+	_ = string([]byte(s + s))[0] // MATCH "should use s + s instead of string([]byte(s + s))"
+
+	// conversions that change the type or the value
+	_ = string([]byte(ms))
+	_ = MyString([]byte(s))
+	_ = []byte(string(mb))
+	_ = string([]rune(s))
+	_ = []byte(s)
+	_ = string(b)
+
+	// not constant
+	var x byte
+	_ = string([]byte{x})
+	_ = string([]byte{1: 'a'})
+	_ = MyString([]byte{'a'})
+}
@@ -0,0 +1,29 @@
+package pkg
+
+import (
+	"fmt"
+	"strconv"
+)
+
+func fn(x int, y int64, u uint64, f float64, b bool, z int32, s string) {
+	strconv.Atoi(fmt.Sprintf("%d", x))                          // MATCH "should use x directly instead of formatting and parsing it"
+	strconv.Atoi(fmt.Sprint(x))                                 // MATCH "should use x directly"
+	strconv.Atoi(strconv.Itoa(x))                               // MATCH "should use x directly"
+	strconv.ParseInt(fmt.Sprintf("%v", y), 10, 64)              // MATCH "should use y directly"
+	strconv.ParseInt(strconv.FormatInt(y, 10), 10, 64)          // MATCH "should use y directly"
+	strconv.ParseUint(strconv.FormatUint(u, 10), 10, 64)        // MATCH "should use u directly"
+	strconv.ParseFloat(fmt.Sprintf("%g", f), 64)                // MATCH "should use f directly"
+	strconv.ParseFloat(strconv.FormatFloat(f, 'g', -1, 64), 64) // MATCH "should use f directly"
+	strconv.ParseBool(strconv.FormatBool(b))                    // MATCH "should use b directly"
+	strconv.ParseBool(fmt.Sprintf("%t", b))                     // MATCH "should use b directly"
+
+	// the formatted value differs in type or value
+	strconv.Atoi(fmt.Sprintf("%d", z))
+	strconv.Atoi(fmt.Sprintf("%05d", x))
+	strconv.Atoi(fmt.Sprintf("%d%s", x, s))
+	strconv.ParseInt(strconv.FormatInt(y, 16), 16, 64)
+	strconv.ParseInt(fmt.Sprintf("%d", y), 10, 32)
+	strconv.ParseFloat(fmt.Sprintf("%.2f", f), 64)
+	strconv.ParseFloat(strconv.FormatFloat(f, 'f', 2, 64), 64)
+	strconv.Atoi(s)
+}
@@ -0,0 +1,18 @@
+package pkg
+
+func fn(s []int, str string, arr [4]int) {
+	_ = s[1:len(s)]     // MATCH "should omit second index in slice, s[a:len(s)] is identical to s[a:]"
+	_ = s[0:len(s)]     // MATCH "should omit both indices in slice, s[0:len(s)] is identical to s[:]"
+	_ = str[0:len(str)] // MATCH "should omit both indices in slice"
+	_ = arr[0:len(arr)] // MATCH "should omit both indices in slice"
+	_ = s[:len(s)]      // MATCH "should omit second index in slice"
+
+	_ = s[0:len(s):len(s)]
+	_ = s[0:]
+	_ = s[:]
+	_ = s[0:len(str)]
+	_ = get()[0:len(get())]
+	_ = s[0 : len(s)-1]
+}
+
+func get() []int { return nil }
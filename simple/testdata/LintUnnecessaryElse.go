@@ -0,0 +1,79 @@
+package pkg
+
+func fn1(x int) int {
+	if x > 0 {
+		println()
+		return 1
+	} else { // MATCH "if block ends with a return statement, so drop this else and outdent its block"
+		println()
+		return 2
+	}
+}
+
+func fn2(x int) {
+	if x > 0 {
+		panic("positive")
+	} else { // MATCH "if block ends with a panic statement"
+		// a comment
+		println()
+	}
+	for {
+		if x > 0 {
+			continue
+		} else { // MATCH "if block ends with a continue statement"
+			break
+		}
+	}
+}
+
+func fn3(x int) int {
+	if x > 0 {
+		return 1
+	} else { // MATCH "if block ends with a return statement"
+		y := 2
+		return y
+	}
+}
+
+func fn4(x int) int {
+	if x > 0 {
+		if x > 1 {
+			return 1
+		}
+	} else {
+		return 2
+	}
+
+	if x > 0 {
+	} else {
+		return 3
+	}
+
+	if y := x * 2; y > 0 {
+		return y
+	} else {
+		return -y
+	}
+
+	if x > 0 {
+		return 1
+	} else if x < 0 {
+		return -1
+	} else {
+		return 0
+	}
+
+	if x > 0 {
+		return 1
+	} else if x < 0 {
+		return -1
+	}
+
+	panic := func(int) {}
+	if x > 0 {
+		panic(1)
+	} else {
+		return 1
+	}
+	return 0
+}
@@ -8,6 +8,7 @@ import (
 	"go/token"
 	"go/types"
 	htmltemplate "html/template"
+	"math/big"
 	"net/http"
 	"regexp"
 	"regexp/syntax"
@@ -16,6 +17,7 @@ import (
 	"strings"
 	"sync"
 	texttemplate "text/template"
+	"unicode/utf8"
 
 	"honnef.co/go/tools/deprecated"
 	"honnef.co/go/tools/functions"
@@ -205,12 +207,27 @@ var (
 
 type Checker struct {
 	CheckGenerated bool
+	// PaddingThreshold is the number of bytes a struct has to be
+	// able to shrink by before SA6005 reports it.
+	PaddingThreshold int64
+	// ChanCapacityThreshold is the capacity that buffered channels
+	// created with a literal capacity have to exceed for SA9010 to
+	// report them.
+	ChanCapacityThreshold int64
+	// DynamicConversions causes SA5012 to also report narrowing
+	// conversions of values whose range isn't known, with a low
+	// confidence.
+	DynamicConversions bool
+	// PointerSliceThreshold is the size in bytes up to which SA6008
+	// reports slices of pointers to structs.
+	PointerSliceThreshold int64
+
 	funcDescs      *functions.Descriptions
 	deprecatedObjs map[types.Object]string
 }
 
 func NewChecker() *Checker {
-	return &Checker{}
+	return &Checker{ChanCapacityThreshold: 100, PointerSliceThreshold: 16}
 }
 
 func (*Checker) Name() string   { return "staticcheck" }
@@ -243,11 +260,17 @@ func (c *Checker) Funcs() map[string]lint.Func {
 		"SA1022": nil,
 		"SA1023": c.CheckWriterBufferModified,
 		"SA1024": c.callChecker(checkUniqueCutsetRules),
+		"SA1025": c.CheckHeaderAfterWrite,
+		"SA1026": c.CheckRowsScanError,
+		"SA1027": c.CheckPrintlnNewline,
+		"SA1028": c.CheckNonConstantFormat,
+		"SA1029": c.CheckTimeEquality,
 
 		"SA2000": c.CheckWaitgroupAdd,
 		"SA2001": c.CheckEmptyCriticalSection,
 		"SA2002": c.CheckConcurrentTesting,
 		"SA2003": c.CheckDeferLock,
+		"SA2004": c.CheckOnceVaryingCapture,
 
 		"SA3000": c.CheckTestMainExit,
 		"SA3001": c.CheckBenchmarkN,
@@ -257,7 +280,7 @@ func (c *Checker) Funcs() map[string]lint.Func {
 		"SA4002": c.CheckDiffSizeComparison,
 		"SA4003": c.CheckUnsignedComparison,
 		"SA4004": c.CheckIneffectiveLoop,
-		"SA4005": nil,
+		"SA4005": c.CheckIneffectiveFieldAssignment,
 		"SA4006": c.CheckUnreadVariableValues,
 		// "SA4007": c.CheckPredeterminedBooleanExprs,
 		"SA4007": nil,
@@ -273,6 +296,9 @@ func (c *Checker) Funcs() map[string]lint.Func {
 		"SA4017": c.CheckPureFunctions,
 		"SA4018": c.CheckSelfAssignment,
 		"SA4019": c.CheckDuplicateBuildConstraints,
+		"SA4020": c.CheckModifiedMapValueCopy,
+		"SA4021": c.CheckUnreachableTypeCases,
+		"SA4022": c.CheckDroppedError,
 
 		"SA5000": c.CheckNilMaps,
 		"SA5001": c.CheckEarlyDefer,
@@ -282,18 +308,73 @@ func (c *Checker) Funcs() map[string]lint.Func {
 		"SA5005": c.CheckCyclicFinalizer,
 		// "SA5006": c.CheckSliceOutOfBounds,
 		"SA5007": c.CheckInfiniteRecursion,
+		"SA5008": c.CheckStringerNumericVerb,
+		"SA5009": c.CheckMethodValueReceiver,
+		"SA5010": c.CheckLoopVariableAddress,
+		"SA5011": c.CheckPrintedPointers,
+		"SA5012": c.CheckNarrowingConversion,
+		"SA5013": c.CheckNonStrictLess,
 
 		"SA6000": c.callChecker(checkRegexpMatchLoopRules),
 		"SA6001": c.CheckMapBytesKey,
 		"SA6002": c.callChecker(checkSyncPoolValueRules),
 		"SA6003": c.CheckRangeStringRunes,
 		"SA6004": c.CheckSillyRegexp,
+		"SA6005": c.CheckStructPadding,
+		"SA6006": c.CheckPreallocatableAppend,
+		"SA6007": c.CheckComparableDeepEqual,
+		"SA6008": c.CheckSmallStructPointerSlice,
 
 		"SA9000": nil,
 		"SA9001": c.CheckDubiousDeferInChannelRangeLoop,
 		"SA9002": c.CheckNonOctalFileMode,
 		"SA9003": c.CheckEmptyBranch,
 		"SA9004": c.CheckMissingEnumTypesInDeclaration,
+		"SA9005": c.CheckIntegerDivisionBeforeMultiplication,
+		"SA9006": c.CheckDiscardedParseError,
+		"SA9007": c.CheckUnspreadVariadic,
+		"SA9008": c.CheckExhaustiveTypeSwitch,
+		"SA9009": c.CheckUnboundedGoroutines,
+		"SA9010": c.CheckLargeChanCapacity,
+		"SA9011": c.CheckAppendToSubslice,
+		"SA9012": c.CheckSleepInLoop,
+		"SA9013": c.CheckUncheckedCopy,
+		"SA9014": c.CheckUnsynchronizedGoroutineWrite,
+	}
+}
+
+func (c *Checker) Info() map[string]lint.CheckInfo {
+	return map[string]lint.CheckInfo{
+		"SA1025": {Since: "2019.2"},
+		"SA1026": {Since: "2019.2"},
+		"SA1027": {Fixable: true, Since: "2019.2"},
+		"SA1028": {OptIn: true, Since: "2019.2"},
+		"SA1029": {Fixable: true, Since: "2019.2"},
+		"SA2004": {OptIn: true, Since: "2019.2"},
+		"SA4005": {Since: "2019.2"},
+		"SA4020": {Since: "2019.2"},
+		"SA4021": {Since: "2019.2"},
+		"SA4022": {Since: "2019.2"},
+		"SA5008": {OptIn: true, Since: "2019.2"},
+		"SA5009": {Since: "2019.2"},
+		"SA5010": {Since: "2019.2"},
+		"SA5011": {Since: "2019.2"},
+		"SA5012": {Since: "2019.2"},
+		"SA5013": {Since: "2019.2"},
+		"SA6005": {OptIn: true, Since: "2019.2"},
+		"SA6006": {Since: "2019.2"},
+		"SA6007": {Since: "2019.2"},
+		"SA6008": {OptIn: true, Since: "2019.2"},
+		"SA9005": {OptIn: true, Since: "2019.2"},
+		"SA9006": {Since: "2019.2"},
+		"SA9007": {OptIn: true, Since: "2019.2"},
+		"SA9008": {OptIn: true, Since: "2019.2"},
+		"SA9009": {OptIn: true, Since: "2019.2"},
+		"SA9010": {OptIn: true, Since: "2019.2"},
+		"SA9011": {OptIn: true, Since: "2019.2"},
+		"SA9012": {OptIn: true, Since: "2019.2"},
+		"SA9013": {OptIn: true, Since: "2019.2"},
+		"SA9014": {OptIn: true, Since: "2019.2"},
 	}
 }
 
@@ -533,8 +614,7 @@ func (c *Checker) CheckTemplate(j *lint.Job) {
 			return true
 		}
 		sel := call.Fun.(*ast.SelectorExpr)
-		if !IsCallToAST(j, sel.X, "text/template.New") &&
-			!IsCallToAST(j, sel.X, "html/template.New") {
+		if !isNewTemplate(j, sel.X) {
 			// TODO(dh): this is a cheap workaround for templates with
 			// different delims. A better solution with less false
 			// negatives would use data flow analysis to see where the
@@ -553,8 +633,9 @@ func (c *Checker) CheckTemplate(j *lint.Job) {
 			_, err = htmltemplate.New("").Parse(s)
 		}
 		if err != nil {
-			// TODO(dominikh): whitelist other parse errors, if any
-			if strings.Contains(err.Error(), "unexpected") {
+			// Functions may be provided via Funcs, which we cannot
+			// see; every other parse error is a genuine mistake.
+			if !strings.Contains(err.Error(), "not defined") {
 				j.Errorf(call.Args[0], "%s", err)
 			}
 		}
@@ -565,6 +646,25 @@ func (c *Checker) CheckTemplate(j *lint.Job) {
 	}
 }
 
+// isNewTemplate reports whether expr is a freshly created template,
+// possibly configured with calls to Funcs or Option, none of which
+// affect how the template is parsed.
+func isNewTemplate(j *lint.Job, expr ast.Expr) bool {
+	for {
+		if IsCallToAST(j, expr, "text/template.New") ||
+			IsCallToAST(j, expr, "html/template.New") {
+			return true
+		}
+		if !IsCallToAST(j, expr, "(*text/template.Template).Funcs") &&
+			!IsCallToAST(j, expr, "(*html/template.Template).Funcs") &&
+			!IsCallToAST(j, expr, "(*text/template.Template).Option") &&
+			!IsCallToAST(j, expr, "(*html/template.Template).Option") {
+			return false
+		}
+		expr = expr.(*ast.CallExpr).Fun.(*ast.SelectorExpr).X
+	}
+}
+
 func (c *Checker) CheckTimeSleepConstant(j *lint.Job) {
 	fn := func(node ast.Node) bool {
 		call, ok := node.(*ast.CallExpr)
@@ -1267,6 +1367,15 @@ func (c *Checker) CheckUnreadVariableValues(j *lint.Job) {
 			continue
 		}
 
+		report := func(lhs ast.Expr) {
+			// An error that is overwritten before it has been
+			// checked is usually a missed check, not dead code.
+			if IsOfType(j, lhs, "error") {
+				j.Errorf(lhs, "the error assigned to %s here is never checked", lhs)
+				return
+			}
+			j.Errorf(lhs, "this value of %s is never used", lhs)
+		}
 		ast.Inspect(node, func(node ast.Node) bool {
 			assign, ok := node.(*ast.AssignStmt)
 			if !ok {
@@ -1298,7 +1407,7 @@ func (c *Checker) CheckUnreadVariableValues(j *lint.Job) {
 						if ident, ok := lhs.(*ast.Ident); !ok || ok && ident.Name == "_" {
 							continue
 						}
-						j.Errorf(lhs, "this value of %s is never used", lhs)
+						report(lhs)
 					}
 				}
 				return true
@@ -1319,7 +1428,11 @@ func (c *Checker) CheckUnreadVariableValues(j *lint.Job) {
 					return true
 				}
 				if len(FilterDebug(*refs)) == 0 {
-					j.Errorf(lhs, "this value of %s is never used", lhs)
+					if IsCallToAnyAST(j, rhs, newErrorFuncs...) {
+						// flagged by CheckDroppedError
+						continue
+					}
+					report(lhs)
 				}
 			}
 			return true
@@ -1569,8 +1682,11 @@ func (c *Checker) CheckArgOverwritten(j *lint.Job) {
 						continue
 					}
 
-					assigned := false
+					var assigned *ast.AssignStmt
 					ast.Inspect(body, func(node ast.Node) bool {
+						if assigned != nil {
+							return false
+						}
 						assign, ok := node.(*ast.AssignStmt)
 						if !ok {
 							return true
@@ -1581,14 +1697,15 @@ func (c *Checker) CheckArgOverwritten(j *lint.Job) {
 								continue
 							}
 							if ObjectOf(j, ident) == obj {
-								assigned = true
+								assigned = assign
 								return false
 							}
 						}
 						return true
 					})
-					if assigned {
-						j.Errorf(arg, "argument %s is overwritten before first use", arg)
+					if assigned != nil {
+						p := j.Errorf(arg, "argument %s is overwritten before first use", arg)
+						p.Related = append(p.Related, j.Related(assigned, "%s is overwritten here", arg))
 					}
 				}
 			}
@@ -1598,6 +1715,157 @@ func (c *Checker) CheckArgOverwritten(j *lint.Job) {
 	}
 }
 
+// valueReceiverField returns the receiver and the name of the field
+// if expr modifies a field of the receiver recv, such as r.f or
+// r.f.arr[0], without going through a pointer, slice or map, which
+// would make the modification visible outside the method. Promoted
+// fields are named after the embedded field that contains them.
+func valueReceiverField(j *lint.Job, expr ast.Expr, recv types.Object) (*ast.Ident, string, bool) {
+	field := ""
+	for {
+		switch e := expr.(type) {
+		case *ast.ParenExpr:
+			expr = e.X
+		case *ast.SelectorExpr:
+			sel, ok := j.Program.Info.Selections[e]
+			if !ok || sel.Kind() != types.FieldVal || sel.Indirect() {
+				return nil, "", false
+			}
+			if _, ok := TypeOf(j, e.X).Underlying().(*types.Pointer); ok {
+				return nil, "", false
+			}
+			field = receiverField(sel)
+			expr = e.X
+		case *ast.IndexExpr:
+			if _, ok := TypeOf(j, e.X).Underlying().(*types.Array); !ok {
+				return nil, "", false
+			}
+			expr = e.X
+		case *ast.Ident:
+			if field == "" || ObjectOf(j, e) != recv {
+				return nil, "", false
+			}
+			return e, field, true
+		default:
+			return nil, "", false
+		}
+	}
+}
+
+// receiverField returns the name of the field of the selection's
+// receiver that the selection goes through, which is the embedded
+// field for promoted fields.
+func receiverField(sel *types.Selection) string {
+	T := sel.Recv()
+	if ptr, ok := T.(*types.Pointer); ok {
+		T = ptr.Elem()
+	}
+	s, ok := T.Underlying().(*types.Struct)
+	if !ok {
+		return sel.Obj().Name()
+	}
+	return s.Field(sel.Index()[0]).Name()
+}
+
+func (c *Checker) CheckIneffectiveFieldAssignment(j *lint.Job) {
+	fn := func(decl *ast.FuncDecl) {
+		if decl.Recv == nil || decl.Body == nil || len(decl.Recv.List[0].Names) == 0 {
+			return
+		}
+		recv := ObjectOf(j, decl.Recv.List[0].Names[0])
+		if recv == nil {
+			return
+		}
+		if _, ok := recv.Type().(*types.Pointer); ok {
+			return
+		}
+
+		type assignment struct {
+			node  ast.Node
+			stmt  ast.Node
+			field string
+		}
+		var assignments []assignment
+		targets := map[*ast.Ident]bool{}
+		ast.Inspect(decl.Body, func(node ast.Node) bool {
+			var lhs []ast.Expr
+			switch node := node.(type) {
+			case *ast.AssignStmt:
+				if node.Tok == token.DEFINE {
+					return true
+				}
+				lhs = node.Lhs
+			case *ast.IncDecStmt:
+				lhs = []ast.Expr{node.X}
+			default:
+				return true
+			}
+			for _, expr := range lhs {
+				if ident, field, ok := valueReceiverField(j, expr, recv); ok {
+					assignments = append(assignments, assignment{expr, node, field})
+					targets[ident] = true
+				}
+			}
+			return true
+		})
+		if len(assignments) == 0 {
+			return
+		}
+
+		// Any other use of the receiver may observe the assignments,
+		// either by reading the modified fields or by using the
+		// receiver as a whole, for example by returning it. Reads in
+		// the assignment itself, as in r.n = r.n + 1, don't count.
+		reads := map[string][]token.Pos{}
+		whole := false
+		var parent ast.Node
+		var stack []ast.Node
+		ast.Inspect(decl.Body, func(node ast.Node) bool {
+			if node == nil {
+				stack = stack[:len(stack)-1]
+				return true
+			}
+			if len(stack) > 0 {
+				parent = stack[len(stack)-1]
+			}
+			stack = append(stack, node)
+			ident, ok := node.(*ast.Ident)
+			if !ok || targets[ident] || j.Program.Info.Uses[ident] != recv {
+				return true
+			}
+			if sel, ok := parent.(*ast.SelectorExpr); ok && sel.X == ident {
+				if s, ok := j.Program.Info.Selections[sel]; ok && s.Kind() == types.FieldVal {
+					field := receiverField(s)
+					reads[field] = append(reads[field], ident.Pos())
+					return true
+				}
+			}
+			whole = true
+			return true
+		})
+		if whole {
+			return
+		}
+	assignLoop:
+		for _, a := range assignments {
+			for _, pos := range reads[a.field] {
+				if pos < a.stmt.Pos() || pos >= a.stmt.End() {
+					continue assignLoop
+				}
+			}
+			j.Errorf(a.node, "ineffective assignment to field %s; %s has a value receiver, so the change is discarded when the method returns. Did you mean to use a pointer receiver?",
+				a.field, decl.Name.Name)
+		}
+	}
+	for _, f := range c.filterGenerated(j.Program.Files) {
+		for _, decl := range f.Decls {
+			if decl, ok := decl.(*ast.FuncDecl); ok {
+				fn(decl)
+			}
+		}
+	}
+}
+
 func (c *Checker) CheckIneffectiveLoop(j *lint.Job) {
 	// This check detects some, but not all unconditional loop exits.
 	// We give up in the following cases:
@@ -1998,7 +2266,8 @@ func (c *Checker) CheckDeferLock(j *lint.Job) {
 				case "RLock":
 					alt = "RUnlock"
 				}
-				j.Errorf(nins, "deferring %s right after having locked already; did you mean to defer %s?", name, alt)
+				p := j.Errorf(nins, "deferring %s right after having locked already; did you mean to defer %s?", name, alt)
+				p.Related = append(p.Related, j.Related(call, "locked here"))
 			}
 		}
 	}
@@ -2089,9 +2358,23 @@ func isName(j *lint.Job, expr ast.Expr, name string) bool {
 	return objectName(obj) == name
 }
 
+// isInInit reports whether fn is part of package initialization,
+// that is the initializers of package-level variables, init
+// functions, or closures defined in either.
+func isInInit(fn *ssa.Function) bool {
+	for fn.Parent() != nil {
+		fn = fn.Parent()
+	}
+	if fn.Synthetic == "package initializer" {
+		return true
+	}
+	decl, ok := fn.Syntax().(*ast.FuncDecl)
+	return ok && decl.Recv == nil && decl.Name.Name == "init"
+}
+
 func (c *Checker) CheckLeakyTimeTick(j *lint.Job) {
 	for _, ssafn := range j.Program.InitialFunctions {
-		if IsInMain(j, ssafn) || IsInTest(j, ssafn) {
+		if IsInMain(j, ssafn) || IsInTest(j, ssafn) || isInInit(ssafn) {
 			continue
 		}
 		for _, block := range ssafn.Blocks {
@@ -2103,7 +2386,7 @@ func (c *Checker) CheckLeakyTimeTick(j *lint.Job) {
 				if c.funcDescs.Get(call.Parent()).Infinite {
 					continue
 				}
-				j.Errorf(call, "using time.Tick leaks the underlying ticker, consider using it only in endless functions, tests and the main package, and use time.NewTicker here")
+				j.Errorf(call, "using time.Tick leaks the underlying ticker, consider using it only in endless functions, tests, package initialization and the main package, and use time.NewTicker with a deferred call to Stop here")
 			}
 		}
 	}
@@ -2148,6 +2431,26 @@ func hasSideEffects(node ast.Node) bool {
 	return dynamic
 }
 
+// conditionKey returns a string that is identical for conditions that
+// are identical. Comparisons against constants are keyed by the value
+// of the constant, so that x == 1, 1 == x and x == one match each
+// other.
+func conditionKey(j *lint.Job, cond ast.Expr) string {
+	bin, ok := cond.(*ast.BinaryExpr)
+	if !ok || (bin.Op != token.EQL && bin.Op != token.NEQ) {
+		return Render(j, cond)
+	}
+	x, y := bin.X, bin.Y
+	if j.Program.Info.Types[x].Value != nil {
+		x, y = y, x
+	}
+	val := j.Program.Info.Types[y].Value
+	if val == nil || j.Program.Info.Types[x].Value != nil {
+		return Render(j, cond)
+	}
+	return fmt.Sprintf("%s %s %s", Render(j, x), bin.Op, val.ExactString())
+}
+
 func (c *Checker) CheckRepeatedIfElse(j *lint.Job) {
 	seen := map[ast.Node]bool{}
 
@@ -2182,7 +2485,7 @@ func (c *Checker) CheckRepeatedIfElse(j *lint.Job) {
 		}
 		counts := map[string]int{}
 		for _, cond := range conds {
-			s := Render(j, cond)
+			s := conditionKey(j, cond)
 			counts[s]++
 			if counts[s] == 2 {
 				j.Errorf(cond, "this condition occurs multiple times in this if/else if chain")
@@ -2788,3 +3091,2546 @@ func (c *Checker) CheckMissingEnumTypesInDeclaration(j *lint.Job) {
 		ast.Inspect(f, fn)
 	}
 }
+
+func (c *Checker) CheckIntegerDivisionBeforeMultiplication(j *lint.Job) {
+	fn := func(node ast.Node) bool {
+		mul, ok := node.(*ast.BinaryExpr)
+		if !ok || mul.Op != token.MUL {
+			return true
+		}
+		// (a / b) * c parses as a ParenExpr and documents that the
+		// order is intentional, so we only look at bare a / b * c.
+		div, ok := mul.X.(*ast.BinaryExpr)
+		if !ok || div.Op != token.QUO {
+			return true
+		}
+		basic, ok := TypeOf(j, mul).Underlying().(*types.Basic)
+		if !ok || basic.Info()&types.IsInteger == 0 {
+			return true
+		}
+		if Render(j, div.Y) == Render(j, mul.Y) {
+			// x / n * n is the idiomatic way of rounding down to a
+			// multiple of n
+			return true
+		}
+		p := j.Errorf(mul, "integer division happens before the multiplication and may lose precision; did you mean %s * %s / %s?",
+			Render(j, div.X), Render(j, mul.Y), Render(j, div.Y))
+		p.Confidence = 0.5
+		return true
+	}
+	for _, f := range j.Program.Files {
+		ast.Inspect(f, fn)
+	}
+}
+
+// optimalFieldOrder returns the fields of a struct, sorted in a way
+// that minimizes the amount of padding.
+func optimalFieldOrder(T *types.Struct, sizes types.Sizes) []*types.Var {
+	fields := make([]*types.Var, T.NumFields())
+	for i := range fields {
+		fields[i] = T.Field(i)
+	}
+	sort.SliceStable(fields, func(i, j int) bool {
+		si, sj := sizes.Sizeof(fields[i].Type()), sizes.Sizeof(fields[j].Type())
+		// Place zero sized objects before non-zero sized objects,
+		// as a trailing zero sized field causes padding.
+		if (si == 0) != (sj == 0) {
+			return si == 0
+		}
+		// Next, place more tightly aligned objects before less
+		// tightly aligned objects.
+		ai, aj := sizes.Alignof(fields[i].Type()), sizes.Alignof(fields[j].Type())
+		if ai != aj {
+			return ai > aj
+		}
+		// Lastly, order by size.
+		return si > sj
+	})
+	return fields
+}
+
+func (c *Checker) CheckStructPadding(j *lint.Job) {
+	sizes := j.Program.Sizes
+	fn := func(node ast.Node) bool {
+		spec, ok := node.(*ast.TypeSpec)
+		if !ok {
+			return true
+		}
+		if _, ok := spec.Type.(*ast.StructType); !ok {
+			return true
+		}
+		T, ok := ObjectOf(j, spec.Name).Type().Underlying().(*types.Struct)
+		if !ok || T.NumFields() < 2 {
+			return true
+		}
+		for i := 0; i < T.NumFields(); i++ {
+			if T.Field(i).Name() == "_" {
+				// blank fields are commonly used for deliberate
+				// padding, e.g. to avoid false sharing
+				return true
+			}
+		}
+		fields := optimalFieldOrder(T, sizes)
+		before := sizes.Sizeof(T)
+		after := sizes.Sizeof(types.NewStruct(fields, nil))
+		if before-after <= c.PaddingThreshold {
+			return true
+		}
+		names := make([]string, len(fields))
+		for i, field := range fields {
+			names[i] = field.Name()
+		}
+		j.Errorf(spec.Name, "struct %s occupies %d bytes but could occupy %d bytes with its fields in the following order: %s",
+			spec.Name.Name, before, after, strings.Join(names, ", "))
+		return true
+	}
+	for _, f := range c.filterGenerated(j.Program.Files) {
+		ast.Inspect(f, fn)
+	}
+}
+
+// emptySliceDecl returns the identifier and the type expression of
+// stmt if it declares a single, empty slice, either as var s []T or
+// as s := []T{}, and whether the slice is nil.
+func emptySliceDecl(j *lint.Job, stmt ast.Stmt) (*ast.Ident, ast.Expr, bool, bool) {
+	var ident *ast.Ident
+	var typ ast.Expr
+	isNil := false
+	switch stmt := stmt.(type) {
+	case *ast.DeclStmt:
+		gen, ok := stmt.Decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.VAR || len(gen.Specs) != 1 {
+			return nil, nil, false, false
+		}
+		spec := gen.Specs[0].(*ast.ValueSpec)
+		if len(spec.Names) != 1 || len(spec.Values) != 0 || spec.Type == nil {
+			return nil, nil, false, false
+		}
+		ident, typ, isNil = spec.Names[0], spec.Type, true
+	case *ast.AssignStmt:
+		if stmt.Tok != token.DEFINE || len(stmt.Lhs) != 1 || len(stmt.Rhs) != 1 {
+			return nil, nil, false, false
+		}
+		lit, ok := stmt.Rhs[0].(*ast.CompositeLit)
+		if !ok || len(lit.Elts) != 0 || lit.Type == nil {
+			return nil, nil, false, false
+		}
+		ident, ok = stmt.Lhs[0].(*ast.Ident)
+		if !ok {
+			return nil, nil, false, false
+		}
+		typ = lit.Type
+	default:
+		return nil, nil, false, false
+	}
+	if IsBlank(ident) {
+		return nil, nil, false, false
+	}
+	if _, ok := TypeOf(j, ident).Underlying().(*types.Slice); !ok {
+		return nil, nil, false, false
+	}
+	return ident, typ, isNil, true
+}
+
+// isSelfAppend reports whether stmt has the form s = append(s, x),
+// with obj being the object of s.
+func isSelfAppend(j *lint.Job, stmt ast.Stmt, obj types.Object) bool {
+	assign, ok := stmt.(*ast.AssignStmt)
+	if !ok || assign.Tok != token.ASSIGN || len(assign.Lhs) != 1 || len(assign.Rhs) != 1 {
+		return false
+	}
+	lhs, ok := assign.Lhs[0].(*ast.Ident)
+	if !ok || ObjectOf(j, lhs) != obj {
+		return false
+	}
+	call, ok := assign.Rhs[0].(*ast.CallExpr)
+	if !ok || len(call.Args) != 2 || call.Ellipsis.IsValid() {
+		return false
+	}
+	fn, ok := call.Fun.(*ast.Ident)
+	if !ok {
+		return false
+	}
+	if _, ok := ObjectOf(j, fn).(*types.Builtin); !ok || fn.Name != "append" {
+		return false
+	}
+	arg, ok := call.Args[0].(*ast.Ident)
+	return ok && ObjectOf(j, arg) == obj
+}
+
+// nonEmptyArray reports whether T is an array, or a pointer to an
+// array, with at least one element.
+func nonEmptyArray(T types.Type) bool {
+	if ptr, ok := T.Underlying().(*types.Pointer); ok {
+		T = ptr.Elem()
+	}
+	arr, ok := T.Underlying().(*types.Array)
+	return ok && arr.Len() > 0
+}
+
+// mayObserveNil reports whether any of stmts uses the slice obj in a
+// way that could tell a nil slice from an empty one. Taking its
+// length or capacity, indexing it, ranging over it, appending
+// elements to it and assigning to it can't.
+func mayObserveNil(j *lint.Job, stmts []ast.Stmt, obj types.Object) bool {
+	isBuiltin := func(expr ast.Expr, names ...string) bool {
+		ident, ok := expr.(*ast.Ident)
+		if !ok {
+			return false
+		}
+		if _, ok := ObjectOf(j, ident).(*types.Builtin); !ok {
+			return false
+		}
+		for _, name := range names {
+			if ident.Name == name {
+				return true
+			}
+		}
+		return false
+	}
+	observes := false
+	var stack []ast.Node
+	fn := func(node ast.Node) bool {
+		if node == nil {
+			stack = stack[:len(stack)-1]
+			return true
+		}
+		stack = append(stack, node)
+		ident, ok := node.(*ast.Ident)
+		if !ok || ObjectOf(j, ident) != obj {
+			return true
+		}
+		if len(stack) < 2 {
+			observes = true
+			return true
+		}
+		switch parent := stack[len(stack)-2].(type) {
+		case *ast.CallExpr:
+			if isBuiltin(parent.Fun, "len", "cap") {
+				return true
+			}
+			if isBuiltin(parent.Fun, "append") && parent.Args[0] == ident &&
+				len(parent.Args) > 1 && parent.Ellipsis == token.NoPos {
+				return true
+			}
+		case *ast.IndexExpr:
+			if parent.X == ident {
+				return true
+			}
+		case *ast.RangeStmt:
+			if parent.X == ident {
+				return true
+			}
+		case *ast.AssignStmt:
+			for _, lhs := range parent.Lhs {
+				if lhs == ident {
+					return true
+				}
+			}
+		}
+		observes = true
+		return true
+	}
+	for _, stmt := range stmts {
+		ast.Inspect(stmt, fn)
+	}
+	return observes
+}
+
+func (c *Checker) CheckPreallocatableAppend(j *lint.Job) {
+	// We only flag the simplest form of the pattern, where the
+	// number of appends is guaranteed to be the length of the ranged
+	// collection: a range loop directly following the declaration,
+	// whose body unconditionally appends a single element and which
+	// neither exits early nor otherwise refers to the slice.
+	//
+	// Preallocating turns a nil slice into an empty one when there
+	// is nothing to append, so slices declared with var are only
+	// flagged if the rest of the block can't tell the difference.
+	fn := func(node ast.Node) bool {
+		block, ok := node.(*ast.BlockStmt)
+		if !ok {
+			return true
+		}
+		for i := 0; i+1 < len(block.List); i++ {
+			stmt := block.List[i]
+			ident, typ, nilSlice, ok := emptySliceDecl(j, stmt)
+			if !ok {
+				continue
+			}
+			rng, ok := block.List[i+1].(*ast.RangeStmt)
+			if !ok {
+				continue
+			}
+			switch rng.X.(type) {
+			case *ast.Ident, *ast.SelectorExpr:
+			default:
+				// avoid suggesting len() of an expression with
+				// possible side effects
+				continue
+			}
+			switch T := TypeOf(j, rng.X).Underlying().(type) {
+			case *types.Slice, *types.Map, *types.Array:
+			case *types.Pointer:
+				if _, ok := T.Elem().Underlying().(*types.Array); !ok {
+					continue
+				}
+			default:
+				// strings yield runes, not bytes, and channels
+				// have no known length
+				continue
+			}
+			obj := ObjectOf(j, ident)
+			appends := 0
+			for _, stmt := range rng.Body.List {
+				if isSelfAppend(j, stmt, obj) {
+					appends++
+				}
+			}
+			if appends != 1 {
+				continue
+			}
+			uses := 0
+			exits := false
+			ast.Inspect(rng.Body, func(node ast.Node) bool {
+				switch node := node.(type) {
+				case *ast.Ident:
+					if ObjectOf(j, node) == obj {
+						uses++
+					}
+				case *ast.BranchStmt, *ast.ReturnStmt:
+					exits = true
+				case *ast.CallExpr:
+					if IsIdent(node.Fun, "panic") {
+						exits = true
+					}
+				}
+				return true
+			})
+			// the assignment and the first argument to append
+			if uses != 2 || exits {
+				continue
+			}
+			if nilSlice && !nonEmptyArray(TypeOf(j, rng.X)) && mayObserveNil(j, block.List[i+2:], obj) {
+				continue
+			}
+			j.Errorf(stmt, "should preallocate %s with make(%s, 0, len(%s)) before appending to it in a loop",
+				ident.Name, Render(j, typ), Render(j, rng.X))
+		}
+		return true
+	}
+	for _, f := range c.filterGenerated(j.Program.Files) {
+		ast.Inspect(f, fn)
+	}
+}
+
+type printfVerb struct {
+	verb rune
+	// arg is the index of the argument the verb formats, relative
+	// to the first argument after the format string.
+	arg int
+	// plain is true if the verb has no flags, width or precision.
+	plain bool
+}
+
+// parsePrintfVerbs returns the verbs in a printf format string. It
+// returns false if the format uses explicit argument indexes, which
+// we don't support.
+func parsePrintfVerbs(format string) ([]printfVerb, bool) {
+	var verbs []printfVerb
+	arg := 0
+	for i := 0; i < len(format); i++ {
+		if format[i] != '%' {
+			continue
+		}
+		i++
+		if i < len(format) && format[i] == '%' {
+			continue
+		}
+		plain := true
+		for i < len(format) && strings.IndexByte("+-# 0", format[i]) != -1 {
+			plain = false
+			i++
+		}
+		for i < len(format) && (format[i] == '*' || format[i] == '.' || (format[i] >= '0' && format[i] <= '9')) {
+			if format[i] == '*' {
+				arg++
+			}
+			plain = false
+			i++
+		}
+		if i == len(format) {
+			break
+		}
+		if format[i] == '[' {
+			return nil, false
+		}
+		verb, size := utf8.DecodeRuneInString(format[i:])
+		verbs = append(verbs, printfVerb{verb: verb, arg: arg, plain: plain})
+		arg++
+		i += size - 1
+	}
+	return verbs, true
+}
+
+// stringMethod returns the name of the method that fmt would use to
+// format values of type T with %s or %v, if any.
+func stringMethod(T types.Type) (string, bool) {
+	ms := types.NewMethodSet(T)
+	for _, name := range []string{"Error", "String"} {
+		sel := ms.Lookup(nil, name)
+		if sel == nil {
+			continue
+		}
+		sig, ok := sel.Type().(*types.Signature)
+		if !ok || sig.Params().Len() != 0 || sig.Results().Len() != 1 {
+			continue
+		}
+		if IsType(sig.Results().At(0).Type(), "string") {
+			return name, true
+		}
+	}
+	return "", false
+}
+
+func (c *Checker) CheckStringerNumericVerb(j *lint.Job) {
+	// maps functions to the index of their format argument
+	fns := map[string]int{
+		"fmt.Errorf":               0,
+		"fmt.Fprintf":              1,
+		"fmt.Printf":               0,
+		"fmt.Sprintf":              0,
+		"log.Fatalf":               0,
+		"log.Panicf":               0,
+		"log.Printf":               0,
+		"(*log.Logger).Fatalf":     0,
+		"(*log.Logger).Panicf":     0,
+		"(*log.Logger).Printf":     0,
+		"(*testing.common).Errorf": 0,
+		"(*testing.common).Fatalf": 0,
+		"(*testing.common).Logf":   0,
+		"(*testing.common).Skipf":  0,
+	}
+	numeric := "bcdeEfFgGoOU"
+	fn := func(node ast.Node) bool {
+		call, ok := node.(*ast.CallExpr)
+		if !ok || call.Ellipsis.IsValid() {
+			return true
+		}
+		sel, ok := call.Fun.(*ast.SelectorExpr)
+		if !ok {
+			return true
+		}
+		obj, ok := ObjectOf(j, sel.Sel).(*types.Func)
+		if !ok {
+			return true
+		}
+		idx, ok := fns[obj.FullName()]
+		if !ok || len(call.Args) <= idx {
+			return true
+		}
+		format, ok := ExprToString(j, call.Args[idx])
+		if !ok {
+			return true
+		}
+		verbs, ok := parsePrintfVerbs(format)
+		if !ok {
+			return true
+		}
+		args := call.Args[idx+1:]
+		for _, verb := range verbs {
+			if !verb.plain || strings.IndexRune(numeric, verb.verb) == -1 || verb.arg >= len(args) {
+				// flags, width and precision suggest that the
+				// numeric formatting is intentional
+				continue
+			}
+			arg := args[verb.arg]
+			T := TypeOf(j, arg)
+			if types.NewMethodSet(T).Lookup(nil, "Format") != nil {
+				// the type probably implements fmt.Formatter, which
+				// takes precedence over all other methods
+				continue
+			}
+			name, ok := stringMethod(T)
+			if !ok {
+				continue
+			}
+			p := j.Errorf(arg, "%s is formatted with %%%c, which doesn't use its %s method; did you mean %%s?",
+				Render(j, arg), verb.verb, name)
+			p.Confidence = 0.5
+		}
+		return true
+	}
+	for _, f := range c.filterGenerated(j.Program.Files) {
+		ast.Inspect(f, fn)
+	}
+}
+
+// printsAddresses reports whether formatting a value of type T with
+// %v prints the addresses of struct pointers. fmt prints pointers to
+// structs as &{...} at the top level, but prints the address of
+// pointers nested in slices, arrays and maps.
+func printsAddresses(T types.Type) bool {
+	if _, ok := stringMethod(T); ok {
+		return false
+	}
+	if types.NewMethodSet(T).Lookup(nil, "Format") != nil {
+		return false
+	}
+	var elems []types.Type
+	switch T := T.Underlying().(type) {
+	case *types.Slice:
+		elems = []types.Type{T.Elem()}
+	case *types.Array:
+		elems = []types.Type{T.Elem()}
+	case *types.Map:
+		elems = []types.Type{T.Key(), T.Elem()}
+	}
+	for _, elem := range elems {
+		ptr, ok := elem.Underlying().(*types.Pointer)
+		if !ok {
+			continue
+		}
+		if _, ok := ptr.Elem().Underlying().(*types.Struct); !ok {
+			continue
+		}
+		if _, ok := stringMethod(elem); ok {
+			continue
+		}
+		if types.NewMethodSet(elem).Lookup(nil, "Format") != nil {
+			continue
+		}
+		return true
+	}
+	return false
+}
+
+func (c *Checker) CheckPrintlnNewline(j *lint.Job) {
+	fns := map[string]bool{
+		"fmt.Fprintln":          true,
+		"fmt.Println":           true,
+		"fmt.Sprintln":          true,
+		"log.Fatalln":           true,
+		"log.Panicln":           true,
+		"log.Println":           true,
+		"(*log.Logger).Fatalln": true,
+		"(*log.Logger).Panicln": true,
+		"(*log.Logger).Println": true,
+	}
+	fn := func(node ast.Node) bool {
+		call, ok := node.(*ast.CallExpr)
+		if !ok || len(call.Args) == 0 || call.Ellipsis.IsValid() {
+			return true
+		}
+		sel, ok := call.Fun.(*ast.SelectorExpr)
+		if !ok {
+			return true
+		}
+		obj, ok := ObjectOf(j, sel.Sel).(*types.Func)
+		if !ok || !fns[obj.FullName()] {
+			return true
+		}
+		// Only the newline of the last argument is followed by the
+		// one that the function adds.
+		lit, ok := call.Args[len(call.Args)-1].(*ast.BasicLit)
+		if !ok || lit.Kind != token.STRING {
+			return true
+		}
+		s, err := strconv.Unquote(lit.Value)
+		if err != nil || !strings.HasSuffix(s, "\n") {
+			return true
+		}
+		p := j.Errorf(lit, "%s already adds a newline, the one at the end of the string results in an empty line", obj.Name())
+		if s == "\n" {
+			return true
+		}
+		// the newline is either an escape sequence in an
+		// interpreted string literal or a literal newline in a raw
+		// string literal
+		end := lit.End() - 1
+		var newline string
+		switch {
+		case strings.HasSuffix(lit.Value, `\n"`):
+			newline = `\n`
+		case strings.HasSuffix(lit.Value, "\n`"):
+			newline = "\n"
+		default:
+			return true
+		}
+		p.Fixes = []lint.SuggestedFix{{
+			Message: "remove trailing newline",
+			Edits:   []lint.TextEdit{j.Edit(end-token.Pos(len(newline)), end, "")},
+		}}
+		return true
+	}
+	for _, f := range c.filterGenerated(j.Program.Files) {
+		ast.Inspect(f, fn)
+	}
+}
+
+func (c *Checker) CheckNonConstantFormat(j *lint.Job) {
+	// maps functions to the index of their format string
+	fns := map[string]int{
+		"fmt.Errorf":               0,
+		"fmt.Fprintf":              1,
+		"fmt.Printf":               0,
+		"fmt.Sprintf":              0,
+		"log.Fatalf":               0,
+		"log.Panicf":               0,
+		"log.Printf":               0,
+		"(*log.Logger).Fatalf":     0,
+		"(*log.Logger).Panicf":     0,
+		"(*log.Logger).Printf":     0,
+		"(*testing.common).Errorf": 0,
+		"(*testing.common).Fatalf": 0,
+		"(*testing.common).Logf":   0,
+		"(*testing.common).Skipf":  0,
+	}
+	fn := func(node ast.Node) bool {
+		call, ok := node.(*ast.CallExpr)
+		if !ok || call.Ellipsis.IsValid() {
+			return true
+		}
+		sel, ok := call.Fun.(*ast.SelectorExpr)
+		if !ok {
+			return true
+		}
+		obj, ok := ObjectOf(j, sel.Sel).(*types.Func)
+		if !ok {
+			return true
+		}
+		idx, ok := fns[obj.FullName()]
+		if !ok || len(call.Args) <= idx {
+			return true
+		}
+		format := call.Args[idx]
+		// Calls without arguments are the domain of SA1006.
+		if len(call.Args) == idx+1 {
+			return true
+		}
+		if _, ok := ExprToString(j, format); ok {
+			return true
+		}
+		j.Errorf(format, "non-constant format string in call to %s; its verbs can't be checked against the arguments, consider using a constant format", obj.Name())
+		return true
+	}
+	for _, f := range c.filterGenerated(j.Program.Files) {
+		ast.Inspect(f, fn)
+	}
+}
+
+func (c *Checker) CheckTimeEquality(j *lint.Job) {
+	// operand renders expr as the receiver of a method call
+	operand := func(expr ast.Expr) string {
+		switch expr.(type) {
+		case *ast.StarExpr, *ast.UnaryExpr, *ast.BinaryExpr:
+			return "(" + Render(j, expr) + ")"
+		default:
+			return Render(j, expr)
+		}
+	}
+	fn := func(node ast.Node) bool {
+		expr, ok := node.(*ast.BinaryExpr)
+		if !ok || (expr.Op != token.EQL && expr.Op != token.NEQ) {
+			return true
+		}
+		if !IsOfType(j, expr.X, "time.Time") || !IsOfType(j, expr.Y, "time.Time") {
+			return true
+		}
+		// Comparisons with the zero value are better written with
+		// IsZero, but aren't affected by the monotonic clock.
+		if IsZeroValue(j.Program.Info, expr.X) || IsZeroValue(j.Program.Info, expr.Y) {
+			return true
+		}
+		p := j.Errorf(expr, "comparing time.Time values with %s also compares their locations and monotonic clock readings; use the Equal method instead", expr.Op)
+		fix := operand(expr.X) + ".Equal(" + Render(j, expr.Y) + ")"
+		if expr.Op == token.NEQ {
+			fix = "!" + fix
+		}
+		p.Fixes = []lint.SuggestedFix{{
+			Message: "use Equal",
+			Edits:   []lint.TextEdit{j.Edit(expr.Pos(), expr.End(), fix)},
+		}}
+		return true
+	}
+	for _, f := range c.filterGenerated(j.Program.Files) {
+		ast.Inspect(f, fn)
+	}
+}
+
+func (c *Checker) CheckPrintedPointers(j *lint.Job) {
+	// maps functions to the index of their first formatted argument
+	// and whether they take a format string
+	type printer struct {
+		idx    int
+		format bool
+	}
+	fns := map[string]printer{
+		"fmt.Errorf":               {0, true},
+		"fmt.Fprint":               {1, false},
+		"fmt.Fprintf":              {1, true},
+		"fmt.Fprintln":             {1, false},
+		"fmt.Print":                {0, false},
+		"fmt.Printf":               {0, true},
+		"fmt.Println":              {0, false},
+		"fmt.Sprint":               {0, false},
+		"fmt.Sprintf":              {0, true},
+		"fmt.Sprintln":             {0, false},
+		"log.Fatal":                {0, false},
+		"log.Fatalf":               {0, true},
+		"log.Fatalln":              {0, false},
+		"log.Panic":                {0, false},
+		"log.Panicf":               {0, true},
+		"log.Panicln":              {0, false},
+		"log.Print":                {0, false},
+		"log.Printf":               {0, true},
+		"log.Println":              {0, false},
+		"(*log.Logger).Fatal":      {0, false},
+		"(*log.Logger).Fatalf":     {0, true},
+		"(*log.Logger).Fatalln":    {0, false},
+		"(*log.Logger).Panic":      {0, false},
+		"(*log.Logger).Panicf":     {0, true},
+		"(*log.Logger).Panicln":    {0, false},
+		"(*log.Logger).Print":      {0, false},
+		"(*log.Logger).Printf":     {0, true},
+		"(*log.Logger).Println":    {0, false},
+		"(*testing.common).Error":  {0, false},
+		"(*testing.common).Errorf": {0, true},
+		"(*testing.common).Fatal":  {0, false},
+		"(*testing.common).Fatalf": {0, true},
+		"(*testing.common).Log":    {0, false},
+		"(*testing.common).Logf":   {0, true},
+		"(*testing.common).Skip":   {0, false},
+		"(*testing.common).Skipf":  {0, true},
+	}
+	report := func(arg ast.Expr) {
+		if !printsAddresses(TypeOf(j, arg)) {
+			return
+		}
+		p := j.Errorf(arg, "printing %s prints the addresses of its elements, not the values they point to",
+			Render(j, arg))
+		p.Confidence = 0.5
+	}
+	fn := func(node ast.Node) bool {
+		call, ok := node.(*ast.CallExpr)
+		if !ok || call.Ellipsis.IsValid() {
+			return true
+		}
+		sel, ok := call.Fun.(*ast.SelectorExpr)
+		if !ok {
+			return true
+		}
+		obj, ok := ObjectOf(j, sel.Sel).(*types.Func)
+		if !ok {
+			return true
+		}
+		pr, ok := fns[obj.FullName()]
+		if !ok || len(call.Args) <= pr.idx {
+			return true
+		}
+		if !pr.format {
+			for _, arg := range call.Args[pr.idx:] {
+				report(arg)
+			}
+			return true
+		}
+		format, ok := ExprToString(j, call.Args[pr.idx])
+		if !ok {
+			return true
+		}
+		verbs, ok := parsePrintfVerbs(format)
+		if !ok {
+			return true
+		}
+		args := call.Args[pr.idx+1:]
+		for _, verb := range verbs {
+			if verb.verb != 'v' || verb.arg >= len(args) {
+				continue
+			}
+			report(args[verb.arg])
+		}
+		return true
+	}
+	for _, f := range c.filterGenerated(j.Program.Files) {
+		ast.Inspect(f, fn)
+	}
+}
+
+// methodValue returns the variable that expr, a method value of the
+// form v.M, binds as its receiver, and whether the method has a
+// pointer receiver.
+func methodValue(j *lint.Job, expr ast.Expr) (recv *types.Var, ptr bool, ok bool) {
+	sel, ok := expr.(*ast.SelectorExpr)
+	if !ok {
+		return nil, false, false
+	}
+	s, ok := j.Program.Info.Selections[sel]
+	if !ok || s.Kind() != types.MethodVal {
+		return nil, false, false
+	}
+	ident, ok := sel.X.(*ast.Ident)
+	if !ok {
+		return nil, false, false
+	}
+	v, ok := ObjectOf(j, ident).(*types.Var)
+	if !ok {
+		return nil, false, false
+	}
+	switch v.Type().Underlying().(type) {
+	case *types.Pointer, *types.Interface:
+		// the receiver is already a reference, nothing gets copied
+		return nil, false, false
+	}
+	_, ptr = s.Obj().Type().(*types.Signature).Recv().Type().(*types.Pointer)
+	return v, ptr, true
+}
+
+// rootIdent returns the variable being assigned to by an lvalue such
+// as v, v.f or v.arr[i], or nil if the lvalue doesn't refer to a
+// variable's own storage.
+func rootIdent(expr ast.Expr) *ast.Ident {
+	for {
+		switch e := expr.(type) {
+		case *ast.Ident:
+			return e
+		case *ast.SelectorExpr:
+			expr = e.X
+		case *ast.IndexExpr:
+			expr = e.X
+		case *ast.ParenExpr:
+			expr = e.X
+		default:
+			return nil
+		}
+	}
+}
+
+// loopVariables returns the variables declared by node, if it is a
+// for or range loop, as well as the loop's body.
+func loopVariables(j *lint.Job, node ast.Node) (map[types.Object]bool, *ast.BlockStmt) {
+	var idents []ast.Expr
+	var body *ast.BlockStmt
+	switch loop := node.(type) {
+	case *ast.RangeStmt:
+		if loop.Tok != token.DEFINE {
+			return nil, nil
+		}
+		idents = []ast.Expr{loop.Key, loop.Value}
+		body = loop.Body
+	case *ast.ForStmt:
+		init, ok := loop.Init.(*ast.AssignStmt)
+		if !ok || init.Tok != token.DEFINE {
+			return nil, nil
+		}
+		idents = init.Lhs
+		body = loop.Body
+	default:
+		return nil, nil
+	}
+	vars := map[types.Object]bool{}
+	for _, expr := range idents {
+		if ident, ok := expr.(*ast.Ident); ok {
+			if obj := ObjectOf(j, ident); obj != nil {
+				vars[obj] = true
+			}
+		}
+	}
+	return vars, body
+}
+
+// inspectStoredValues calls fn for all expressions in the loop body
+// whose values outlive the current iteration, because they are
+// assigned to variables declared outside the body or to other
+// locations, appended to slices or sent on channels.
+func inspectStoredValues(j *lint.Job, body *ast.BlockStmt, fn func(ast.Expr)) {
+	// declaredInBody reports whether ident is a variable local to
+	// a single iteration of the loop.
+	declaredInBody := func(ident *ast.Ident) bool {
+		obj := ObjectOf(j, ident)
+		return obj != nil && obj.Pos() >= body.Pos() && obj.Pos() < body.End()
+	}
+	ast.Inspect(body, func(node ast.Node) bool {
+		switch node := node.(type) {
+		case *ast.AssignStmt:
+			if len(node.Lhs) != len(node.Rhs) {
+				return true
+			}
+			for i, rhs := range node.Rhs {
+				if ident, ok := node.Lhs[i].(*ast.Ident); ok && declaredInBody(ident) {
+					continue
+				}
+				fn(rhs)
+			}
+		case *ast.CallExpr:
+			if !IsIdent(node.Fun, "append") {
+				return true
+			}
+			if _, ok := ObjectOf(j, node.Fun.(*ast.Ident)).(*types.Builtin); !ok {
+				return true
+			}
+			for _, arg := range node.Args[1:] {
+				fn(arg)
+			}
+		case *ast.SendStmt:
+			fn(node.Value)
+		}
+		return true
+	})
+}
+
+func (c *Checker) CheckMethodValueReceiver(j *lint.Job) {
+	// checkLoop flags method values with pointer receivers that bind
+	// the address of a loop variable and outlive the iteration.
+	checkLoop := func(node ast.Node) bool {
+		vars, body := loopVariables(j, node)
+		if len(vars) == 0 {
+			return true
+		}
+		inspectStoredValues(j, body, func(expr ast.Expr) {
+			v, ptr, ok := methodValue(j, expr)
+			if !ok || !ptr || !vars[v] {
+				return
+			}
+			sel := expr.(*ast.SelectorExpr)
+			j.Errorf(sel, "the method value %s binds the address of the loop variable %s, which is shared by all iterations; copy %s into a new variable first",
+				Render(j, sel), v.Name(), v.Name())
+		})
+		return true
+	}
+
+	// checkBlock flags method values with value receivers whose
+	// receiver is modified before the method value gets called.
+	checkBlock := func(node ast.Node) bool {
+		block, ok := node.(*ast.BlockStmt)
+		if !ok {
+			return true
+		}
+		for i, stmt := range block.List {
+			assign, ok := stmt.(*ast.AssignStmt)
+			if !ok || len(assign.Lhs) != 1 || len(assign.Rhs) != 1 {
+				continue
+			}
+			lhs, ok := assign.Lhs[0].(*ast.Ident)
+			if !ok {
+				continue
+			}
+			f := ObjectOf(j, lhs)
+			v, ptr, ok := methodValue(j, assign.Rhs[0])
+			if !ok || ptr || f == nil {
+				continue
+			}
+
+			var modified ast.Node
+		stmtLoop:
+			for _, stmt := range block.List[i+1:] {
+				reassigned := false
+				called := false
+				ast.Inspect(stmt, func(node ast.Node) bool {
+					var lvals []ast.Expr
+					switch node := node.(type) {
+					case *ast.AssignStmt:
+						lvals = node.Lhs
+					case *ast.IncDecStmt:
+						lvals = []ast.Expr{node.X}
+					case *ast.CallExpr:
+						if ident, ok := node.Fun.(*ast.Ident); ok && ObjectOf(j, ident) == f {
+							called = true
+						}
+						return true
+					default:
+						return true
+					}
+					for _, lval := range lvals {
+						ident := rootIdent(lval)
+						if ident == nil {
+							continue
+						}
+						switch ObjectOf(j, ident) {
+						case f:
+							reassigned = true
+						case v:
+							if modified == nil {
+								modified = lval
+							}
+						}
+					}
+					return true
+				})
+				switch {
+				case called && modified != nil && modified.Pos() < stmt.Pos():
+					j.Errorf(assign.Rhs[0], "the method value %s copies %s when it is evaluated; the later modification of %s won't be visible when calling %s",
+						Render(j, assign.Rhs[0]), v.Name(), Render(j, modified), f.Name())
+					break stmtLoop
+				case reassigned, called:
+					break stmtLoop
+				}
+			}
+		}
+		return true
+	}
+
+	for _, f := range c.filterGenerated(j.Program.Files) {
+		if !IsGoVersion(j, 22) {
+			// loop variables are per iteration since Go 1.22
+			ast.Inspect(f, checkLoop)
+		}
+		ast.Inspect(f, checkBlock)
+	}
+}
+
+// instrDominates reports whether ins1 dominates ins2, i.e. whether
+// every path to ins2 goes through ins1.
+func instrDominates(ins1, ins2 ssa.Instruction) bool {
+	if ins1.Block() != ins2.Block() {
+		return ins1.Block().Dominates(ins2.Block())
+	}
+	for _, ins := range ins1.Block().Instrs {
+		switch ins {
+		case ins1:
+			return true
+		case ins2:
+			return false
+		}
+	}
+	return false
+}
+
+func (c *Checker) CheckHeaderAfterWrite(j *lint.Job) {
+	unwrap := func(v ssa.Value) ssa.Value {
+		for {
+			switch vv := v.(type) {
+			case *ssa.ChangeInterface:
+				v = vv.X
+			case *ssa.MakeInterface:
+				v = vv.X
+			default:
+				return v
+			}
+		}
+	}
+	isResponseWriter := func(v ssa.Value) bool {
+		return IsType(v.Type(), "net/http.ResponseWriter")
+	}
+	type mutation struct {
+		call *ssa.Call
+		w    ssa.Value
+	}
+	for _, fn := range j.Program.InitialFunctions {
+		// maps response writers to the calls that write to them
+		writes := map[ssa.Value][]*ssa.Call{}
+		var mutations []mutation
+		for _, b := range fn.Blocks {
+			for _, ins := range b.Instrs {
+				// deferred writes happen at the end of the function,
+				// so we only look at ordinary calls
+				call, ok := ins.(*ssa.Call)
+				if !ok {
+					continue
+				}
+				common := call.Common()
+				if common.IsInvoke() {
+					if !isResponseWriter(common.Value) {
+						continue
+					}
+					switch common.Method.Name() {
+					case "Write", "WriteHeader":
+						w := unwrap(common.Value)
+						writes[w] = append(writes[w], call)
+					}
+					continue
+				}
+				switch CallName(common) {
+				case "fmt.Fprint", "fmt.Fprintf", "fmt.Fprintln", "io.WriteString":
+					w := unwrap(common.Args[0])
+					if isResponseWriter(w) {
+						writes[w] = append(writes[w], call)
+					}
+				case "(net/http.Header).Add", "(net/http.Header).Del", "(net/http.Header).Set":
+					hdr, ok := common.Args[0].(*ssa.Call)
+					if !ok || !hdr.Common().IsInvoke() || hdr.Common().Method.Name() != "Header" {
+						continue
+					}
+					if !isResponseWriter(hdr.Common().Value) {
+						continue
+					}
+					mutations = append(mutations, mutation{call, unwrap(hdr.Common().Value)})
+				}
+			}
+		}
+		for _, m := range mutations {
+			for _, w := range writes[m.w] {
+				if instrDominates(w, m.call) {
+					j.Errorf(m.call, "modifying headers after the response has been written has no effect")
+					break
+				}
+			}
+		}
+	}
+}
+
+func (c *Checker) CheckDiscardedParseError(j *lint.Job) {
+	fns := []string{
+		"strconv.Atoi",
+		"strconv.ParseBool",
+		"strconv.ParseFloat",
+		"strconv.ParseInt",
+		"strconv.ParseUint",
+	}
+	fn := func(node ast.Node) bool {
+		assign, ok := node.(*ast.AssignStmt)
+		if !ok || len(assign.Lhs) != 2 || len(assign.Rhs) != 1 {
+			return true
+		}
+		if !IsCallToAnyAST(j, assign.Rhs[0], fns...) {
+			return true
+		}
+		if IsBlank(assign.Lhs[0]) || !IsBlank(assign.Lhs[1]) {
+			return true
+		}
+		sel := assign.Rhs[0].(*ast.CallExpr).Fun.(*ast.SelectorExpr)
+		j.Errorf(assign, "discarding the error of %s means that invalid input goes unnoticed and %s silently gets a zero value; handle the error explicitly",
+			SelectorName(j, sel), Render(j, assign.Lhs[0]))
+		return true
+	}
+	for _, f := range c.filterGenerated(j.Program.Files) {
+		ast.Inspect(f, fn)
+	}
+}
+
+func (c *Checker) CheckRowsScanError(j *lint.Job) {
+	fn := func(node ast.Node) bool {
+		loop, ok := node.(*ast.ForStmt)
+		if !ok || !IsCallToAST(j, loop.Cond, "(*database/sql.Rows).Next") {
+			return true
+		}
+		check := func(expr ast.Expr) {
+			if !IsCallToAST(j, expr, "(*database/sql.Rows).Scan") {
+				return
+			}
+			sel := expr.(*ast.CallExpr).Fun.(*ast.SelectorExpr)
+			j.Errorf(expr, "the error returned by %s.Scan is discarded, which silently skips rows that couldn't be scanned",
+				Render(j, sel.X))
+		}
+		ast.Inspect(loop.Body, func(node ast.Node) bool {
+			switch node := node.(type) {
+			case *ast.ExprStmt:
+				check(node.X)
+			case *ast.AssignStmt:
+				if len(node.Lhs) == 1 && len(node.Rhs) == 1 && IsBlank(node.Lhs[0]) {
+					check(node.Rhs[0])
+				}
+			case *ast.FuncLit:
+				// Don't look into function bodies
+				return false
+			}
+			return true
+		})
+		return true
+	}
+	for _, f := range c.filterGenerated(j.Program.Files) {
+		ast.Inspect(f, fn)
+	}
+}
+
+// fieldStoreRoot returns the variable whose own storage is modified
+// by assigning to lval, a chain of field selectors such as v.a.b, or
+// nil if lval is of any other form. Selectors through pointers
+// modify memory that isn't part of the variable and aren't matched.
+func fieldStoreRoot(j *lint.Job, lval ast.Expr) *ast.Ident {
+	sel, ok := lval.(*ast.SelectorExpr)
+	if !ok {
+		return nil
+	}
+	for {
+		if _, ok := TypeOf(j, sel.X).Underlying().(*types.Struct); !ok {
+			return nil
+		}
+		switch x := sel.X.(type) {
+		case *ast.Ident:
+			return x
+		case *ast.SelectorExpr:
+			sel = x
+		default:
+			return nil
+		}
+	}
+}
+
+func (c *Checker) CheckModifiedMapValueCopy(j *lint.Job) {
+	fn := func(node ast.Node) bool {
+		decl, ok := node.(*ast.FuncDecl)
+		if !ok || decl.Body == nil {
+			return true
+		}
+
+		// copies maps variables holding copies of struct values
+		// taken from maps to the index expressions they came from
+		copies := map[types.Object]*ast.IndexExpr{}
+		ast.Inspect(decl.Body, func(node ast.Node) bool {
+			assign, ok := node.(*ast.AssignStmt)
+			if !ok || assign.Tok != token.DEFINE || len(assign.Rhs) != 1 {
+				return true
+			}
+			index, ok := assign.Rhs[0].(*ast.IndexExpr)
+			if !ok {
+				return true
+			}
+			m, ok := TypeOf(j, index.X).Underlying().(*types.Map)
+			if !ok {
+				return true
+			}
+			if _, ok := m.Elem().Underlying().(*types.Struct); !ok {
+				return true
+			}
+			ident, ok := assign.Lhs[0].(*ast.Ident)
+			if !ok || IsBlank(ident) {
+				return true
+			}
+			if obj := j.Program.Info.Defs[ident]; obj != nil {
+				copies[obj] = index
+			}
+			return true
+		})
+		if len(copies) == 0 {
+			return true
+		}
+
+		// stores records the identifiers that are the roots of field
+		// assignments, modified the first assignment to each copy.
+		stores := map[*ast.Ident]bool{}
+		modified := map[types.Object]ast.Node{}
+		ast.Inspect(decl.Body, func(node ast.Node) bool {
+			var lvals []ast.Expr
+			switch node := node.(type) {
+			case *ast.AssignStmt:
+				lvals = node.Lhs
+			case *ast.IncDecStmt:
+				lvals = []ast.Expr{node.X}
+			default:
+				return true
+			}
+			for _, lval := range lvals {
+				ident := fieldStoreRoot(j, lval)
+				if ident == nil {
+					continue
+				}
+				obj := ObjectOf(j, ident)
+				if _, ok := copies[obj]; !ok {
+					continue
+				}
+				stores[ident] = true
+				if _, ok := modified[obj]; !ok {
+					modified[obj] = lval
+				}
+			}
+			return true
+		})
+
+		read := map[types.Object]bool{}
+		ast.Inspect(decl.Body, func(node ast.Node) bool {
+			ident, ok := node.(*ast.Ident)
+			if !ok || stores[ident] {
+				return true
+			}
+			if obj, ok := j.Program.Info.Uses[ident]; ok {
+				read[obj] = true
+			}
+			return true
+		})
+
+		for obj, lval := range modified {
+			if read[obj] {
+				continue
+			}
+			index := copies[obj]
+			j.Errorf(lval, "%s is a copy of the map value %s, modifying it doesn't change the map; assign it back with %s = %s",
+				obj.Name(), Render(j, index), Render(j, index), obj.Name())
+		}
+		return true
+	}
+	for _, f := range c.filterGenerated(j.Program.Files) {
+		ast.Inspect(f, fn)
+	}
+}
+
+func (c *Checker) CheckLoopVariableAddress(j *lint.Job) {
+	if IsGoVersion(j, 22) {
+		// loop variables are per iteration since Go 1.22
+		return
+	}
+	fn := func(node ast.Node) bool {
+		vars, body := loopVariables(j, node)
+		if len(vars) == 0 {
+			return true
+		}
+		seen := map[ast.Expr]bool{}
+		var check func(expr ast.Expr)
+		check = func(expr ast.Expr) {
+			switch expr := expr.(type) {
+			case *ast.UnaryExpr:
+				if expr.Op != token.AND || seen[expr] {
+					return
+				}
+				ident, ok := expr.X.(*ast.Ident)
+				if !ok || !vars[ObjectOf(j, ident)] {
+					return
+				}
+				seen[expr] = true
+				j.Errorf(expr, "storing the address of the loop variable %s, which is shared by all iterations of the loop; copy it with %s := %s first",
+					ident.Name, ident.Name, ident.Name)
+			case *ast.ParenExpr:
+				check(expr.X)
+			case *ast.CompositeLit:
+				for _, elt := range expr.Elts {
+					check(elt)
+				}
+			case *ast.KeyValueExpr:
+				check(expr.Value)
+			}
+		}
+		inspectStoredValues(j, body, check)
+		return true
+	}
+	for _, f := range c.filterGenerated(j.Program.Files) {
+		ast.Inspect(f, fn)
+	}
+}
+
+// isEmptyInterfaceSlice reports whether T is a slice of empty
+// interfaces, such as []interface{}.
+func isEmptyInterfaceSlice(T types.Type) bool {
+	s, ok := T.Underlying().(*types.Slice)
+	if !ok {
+		return false
+	}
+	iface, ok := s.Elem().Underlying().(*types.Interface)
+	return ok && iface.NumMethods() == 0
+}
+
+func (c *Checker) CheckUnspreadVariadic(j *lint.Job) {
+	fn := func(node ast.Node) bool {
+		call, ok := node.(*ast.CallExpr)
+		if !ok || call.Ellipsis.IsValid() {
+			return true
+		}
+		if tv, ok := j.Program.Info.Types[call.Fun]; !ok || !tv.IsValue() {
+			// conversions and builtins
+			return true
+		}
+		sig, ok := TypeOf(j, call.Fun).Underlying().(*types.Signature)
+		if !ok || !sig.Variadic() {
+			return true
+		}
+		params := sig.Params()
+		if len(call.Args) != params.Len() || !isEmptyInterfaceSlice(params.At(params.Len()-1).Type()) {
+			// only the case of a single variadic argument is
+			// ambiguous
+			return true
+		}
+		arg := call.Args[len(call.Args)-1]
+		if _, ok := arg.(*ast.CompositeLit); ok {
+			// a slice literal is deliberately passed as one value
+			return true
+		}
+		if !isEmptyInterfaceSlice(TypeOf(j, arg)) {
+			return true
+		}
+		j.Errorf(arg, "%s is passed as a single argument to the variadic %s; did you mean %s...?",
+			Render(j, arg), Render(j, call.Fun), Render(j, arg))
+		return true
+	}
+	for _, f := range c.filterGenerated(j.Program.Files) {
+		ast.Inspect(f, fn)
+	}
+}
+
+func (c *Checker) CheckUnreachableTypeCases(j *lint.Job) {
+	fn := func(node ast.Node) bool {
+		tsStmt, ok := node.(*ast.TypeSwitchStmt)
+		if !ok {
+			return true
+		}
+		type seenType struct {
+			expr  ast.Expr
+			iface *types.Interface
+		}
+		var ifaces []seenType
+		for _, stmt := range tsStmt.Body.List {
+			clause := stmt.(*ast.CaseClause)
+			for _, expr := range clause.List {
+				T := TypeOf(j, expr)
+				if T == nil || IsType(T, "untyped nil") {
+					continue
+				}
+				for _, prev := range ifaces {
+					if types.Implements(T, prev.iface) {
+						j.Errorf(expr, "case %s is unreachable because it is matched by the earlier case %s",
+							Render(j, expr), Render(j, prev.expr))
+						break
+					}
+				}
+			}
+			// Only add the clause's types after checking all of them,
+			// as types in the same clause don't shadow each other.
+			for _, expr := range clause.List {
+				T := TypeOf(j, expr)
+				if T == nil {
+					continue
+				}
+				if iface, ok := T.Underlying().(*types.Interface); ok {
+					ifaces = append(ifaces, seenType{expr, iface})
+				}
+			}
+		}
+		return true
+	}
+	for _, f := range c.filterGenerated(j.Program.Files) {
+		ast.Inspect(f, fn)
+	}
+}
+
+// isValueUsed reports whether v is used by any instruction other
+// than debug references, looking through phi nodes, which merge
+// values without using them.
+func isValueUsed(v ssa.Value, seen map[ssa.Value]bool) bool {
+	if seen[v] {
+		return false
+	}
+	seen[v] = true
+	refs := v.Referrers()
+	if refs == nil {
+		return false
+	}
+	for _, ref := range FilterDebug(*refs) {
+		phi, ok := ref.(*ssa.Phi)
+		if !ok || isValueUsed(phi, seen) {
+			return true
+		}
+	}
+	return false
+}
+
+// newErrorFuncs are the functions that construct new errors.
+var newErrorFuncs = []string{"errors.New", "fmt.Errorf"}
+
+func (c *Checker) CheckDroppedError(j *lint.Job) {
+	// errors that are explicitly discarded by assigning them to the
+	// blank identifier, keyed by the positions of their calls
+	discarded := map[token.Pos]bool{}
+	fn := func(node ast.Node) bool {
+		assign, ok := node.(*ast.AssignStmt)
+		if !ok || len(assign.Lhs) != len(assign.Rhs) {
+			return true
+		}
+		for i, lhs := range assign.Lhs {
+			if call, ok := assign.Rhs[i].(*ast.CallExpr); ok && IsBlank(lhs) {
+				discarded[call.Lparen] = true
+			}
+		}
+		return true
+	}
+	for _, f := range j.Program.Files {
+		ast.Inspect(f, fn)
+	}
+
+	for _, ssafn := range j.Program.InitialFunctions {
+		for _, block := range ssafn.Blocks {
+			for _, ins := range block.Instrs {
+				call, ok := ins.(*ssa.Call)
+				if !ok || !IsCallToAny(call.Common(), newErrorFuncs...) {
+					continue
+				}
+				if discarded[call.Pos()] || isValueUsed(call, map[ssa.Value]bool{}) {
+					continue
+				}
+				j.Errorf(call, "the error created by %s is never returned or used; did you forget to return it?",
+					CallName(call.Common()))
+			}
+		}
+	}
+}
+
+// isShallowComparable reports whether values of type T can be
+// compared with == with the same result as reflect.DeepEqual. This
+// excludes pointers and interfaces, which DeepEqual follows, as well
+// as floating point numbers, to err on the side of caution.
+func isShallowComparable(T types.Type) bool {
+	if !types.Comparable(T) {
+		return false
+	}
+	switch T := T.Underlying().(type) {
+	case *types.Basic:
+		return T.Info()&(types.IsFloat|types.IsComplex|types.IsUntyped) == 0 && T.Kind() != types.UnsafePointer
+	case *types.Array:
+		return isShallowComparable(T.Elem())
+	case *types.Struct:
+		for i := 0; i < T.NumFields(); i++ {
+			if !isShallowComparable(T.Field(i).Type()) {
+				return false
+			}
+		}
+		return true
+	default:
+		return false
+	}
+}
+
+func (c *Checker) CheckComparableDeepEqual(j *lint.Job) {
+	fn := func(node ast.Node) bool {
+		if !IsCallToAST(j, node, "reflect.DeepEqual") {
+			return true
+		}
+		call := node.(*ast.CallExpr)
+		if len(call.Args) != 2 {
+			return true
+		}
+		x, y := TypeOf(j, call.Args[0]), TypeOf(j, call.Args[1])
+		if !types.Identical(x, y) || !isShallowComparable(x) {
+			return true
+		}
+		j.Errorf(call, "%s and %s can be compared with ==, which is faster than reflect.DeepEqual",
+			Render(j, call.Args[0]), Render(j, call.Args[1]))
+		return true
+	}
+	for _, f := range c.filterGenerated(j.Program.Files) {
+		ast.Inspect(f, fn)
+	}
+}
+
+// smallStructPointerSlice returns the struct type T and its size if
+// typ is []*T, T is at most threshold bytes large and its values can
+// be copied freely: *T has no additional methods, which could mutate
+// shared values, and T has no fields from sync or sync/atomic.
+func smallStructPointerSlice(typ types.Type, sizes types.Sizes, threshold int64) (*types.Named, int64, bool) {
+	slice, ok := typ.(*types.Slice)
+	if !ok {
+		return nil, 0, false
+	}
+	ptr, ok := slice.Elem().(*types.Pointer)
+	if !ok {
+		return nil, 0, false
+	}
+	T, ok := ptr.Elem().(*types.Named)
+	if !ok {
+		return nil, 0, false
+	}
+	st, ok := T.Underlying().(*types.Struct)
+	if !ok {
+		return nil, 0, false
+	}
+	size := sizes.Sizeof(T)
+	if size > threshold {
+		return nil, 0, false
+	}
+	if types.NewMethodSet(ptr).Len() != types.NewMethodSet(T).Len() {
+		return nil, 0, false
+	}
+	for i := 0; i < st.NumFields(); i++ {
+		named, ok := st.Field(i).Type().(*types.Named)
+		if !ok || named.Obj().Pkg() == nil {
+			continue
+		}
+		if path := named.Obj().Pkg().Path(); path == "sync" || path == "sync/atomic" {
+			return nil, 0, false
+		}
+	}
+	return T, size, true
+}
+
+// onlyReadsElements reports whether body uses the slice obj only to
+// get its length and capacity and to read fields of, or call methods
+// on, its elements, either by indexing it or by ranging over it.
+func onlyReadsElements(j *lint.Job, body *ast.BlockStmt, obj types.Object) bool {
+	elems := map[types.Object]bool{}
+	ok := true
+	var stack []ast.Node
+	// readsElement reports whether the element at stack[i] is only
+	// used to read a field or call a method.
+	readsElement := func(i int) bool {
+		if i < 1 {
+			return false
+		}
+		sel, ok := stack[i-1].(*ast.SelectorExpr)
+		if !ok || sel.X != stack[i] {
+			return false
+		}
+		// find the outermost expression the element is part of, as
+		// in s[i].a.b, and make sure it doesn't get modified
+		top := i - 1
+		for top > 0 {
+			switch parent := stack[top-1].(type) {
+			case *ast.SelectorExpr:
+				if parent.X != stack[top] {
+					break
+				}
+				top--
+				continue
+			case *ast.IndexExpr:
+				if parent.X != stack[top] {
+					break
+				}
+				top--
+				continue
+			case *ast.ParenExpr:
+				top--
+				continue
+			}
+			break
+		}
+		if top == 0 {
+			return true
+		}
+		switch parent := stack[top-1].(type) {
+		case *ast.AssignStmt:
+			for _, lhs := range parent.Lhs {
+				if lhs == stack[top] {
+					return false
+				}
+			}
+		case *ast.IncDecStmt:
+			return false
+		case *ast.UnaryExpr:
+			return parent.Op != token.AND
+		}
+		return true
+	}
+	fn := func(node ast.Node) bool {
+		if !ok {
+			return false
+		}
+		if node == nil {
+			stack = stack[:len(stack)-1]
+			return true
+		}
+		stack = append(stack, node)
+		ident, isIdent := node.(*ast.Ident)
+		if !isIdent {
+			return true
+		}
+		used := j.Program.Info.Uses[ident]
+		if used == nil {
+			return true
+		}
+		n := len(stack) - 1
+		if elems[used] {
+			ok = readsElement(n)
+			return true
+		}
+		if used != obj {
+			return true
+		}
+		if n < 1 {
+			ok = false
+			return true
+		}
+		switch parent := stack[n-1].(type) {
+		case *ast.CallExpr:
+			ok = IsCallToAST(j, parent, "len") || IsCallToAST(j, parent, "cap")
+		case *ast.IndexExpr:
+			ok = parent.X == ident && readsElement(n-1)
+		case *ast.RangeStmt:
+			if parent.X != ident || parent.Value == nil {
+				return true
+			}
+			value, isIdent := parent.Value.(*ast.Ident)
+			if !isIdent || parent.Tok != token.DEFINE {
+				ok = false
+				return true
+			}
+			if value.Name != "_" {
+				elems[ObjectOf(j, value)] = true
+			}
+		default:
+			ok = false
+		}
+		return true
+	}
+	ast.Inspect(body, fn)
+	return ok
+}
+
+func (c *Checker) CheckSmallStructPointerSlice(j *lint.Job) {
+	sizes := j.Program.Sizes
+	report := func(node ast.Node, T *types.Named, size int64) *lint.Problem {
+		return j.Errorf(node, "slice of pointers to %s, which is only %d bytes large; a slice of values would avoid the indirection and the separate allocations",
+			T.Obj().Name(), size)
+	}
+	fn := func(node ast.Node) bool {
+		switch node := node.(type) {
+		case *ast.StructType:
+			for _, field := range node.Fields.List {
+				T, size, ok := smallStructPointerSlice(TypeOf(j, field.Type), sizes, c.PointerSliceThreshold)
+				if !ok {
+					continue
+				}
+				// we can't tell whether the elements get modified
+				// through the pointers elsewhere
+				p := report(field.Type, T, size)
+				p.Confidence = 0.5
+			}
+		case *ast.FuncDecl:
+			if node.Body == nil {
+				return true
+			}
+			for _, field := range node.Type.Params.List {
+				T, size, ok := smallStructPointerSlice(TypeOf(j, field.Type), sizes, c.PointerSliceThreshold)
+				if !ok {
+					continue
+				}
+				for _, name := range field.Names {
+					if name.Name == "_" || !onlyReadsElements(j, node.Body, ObjectOf(j, name)) {
+						continue
+					}
+					report(name, T, size)
+				}
+			}
+		}
+		return true
+	}
+	for _, f := range c.filterGenerated(j.Program.Files) {
+		ast.Inspect(f, fn)
+	}
+}
+
+// sealedImplementations returns the types implementing iface, the
+// underlying interface of a named type, if the interface is sealed,
+// that is if it has unexported methods and can only be implemented
+// by types in its own package. Types whose pointers implement the
+// interface are returned as pointers.
+func sealedImplementations(T *types.Named) ([]types.Type, bool) {
+	iface, ok := T.Underlying().(*types.Interface)
+	if !ok || T.Obj().Pkg() == nil {
+		return nil, false
+	}
+	sealed := false
+	for i := 0; i < iface.NumMethods(); i++ {
+		if !iface.Method(i).Exported() {
+			sealed = true
+			break
+		}
+	}
+	if !sealed {
+		return nil, false
+	}
+	var impls []types.Type
+	scope := T.Obj().Pkg().Scope()
+	for _, name := range scope.Names() {
+		obj, ok := scope.Lookup(name).(*types.TypeName)
+		if !ok || obj.IsAlias() {
+			continue
+		}
+		typ := obj.Type()
+		if types.IsInterface(typ) {
+			continue
+		}
+		if types.Implements(typ, iface) {
+			impls = append(impls, typ)
+		} else if ptr := types.NewPointer(typ); types.Implements(ptr, iface) {
+			impls = append(impls, ptr)
+		}
+	}
+	return impls, true
+}
+
+func (c *Checker) CheckExhaustiveTypeSwitch(j *lint.Job) {
+	fn := func(node ast.Node) bool {
+		tsStmt, ok := node.(*ast.TypeSwitchStmt)
+		if !ok {
+			return true
+		}
+		var ta *ast.TypeAssertExpr
+		switch assign := tsStmt.Assign.(type) {
+		case *ast.ExprStmt:
+			ta, _ = assign.X.(*ast.TypeAssertExpr)
+		case *ast.AssignStmt:
+			ta, _ = assign.Rhs[0].(*ast.TypeAssertExpr)
+		}
+		if ta == nil {
+			return true
+		}
+		named, ok := TypeOf(j, ta.X).(*types.Named)
+		if !ok {
+			return true
+		}
+		impls, ok := sealedImplementations(named)
+		if !ok || len(impls) == 0 {
+			return true
+		}
+		var cases []types.Type
+		for _, stmt := range tsStmt.Body.List {
+			clause := stmt.(*ast.CaseClause)
+			if clause.List == nil {
+				// default case
+				return true
+			}
+			for _, expr := range clause.List {
+				if T := TypeOf(j, expr); T != nil {
+					cases = append(cases, T)
+				}
+			}
+		}
+		covered := func(impl types.Type) bool {
+			// a value type that implements the interface may be
+			// stored as a value or as a pointer; handling either of
+			// them counts
+			elem := impl
+			if ptr, ok := impl.(*types.Pointer); ok {
+				elem = ptr.Elem()
+			}
+			for _, T := range cases {
+				if types.Identical(T, elem) || types.Identical(T, types.NewPointer(elem)) {
+					return true
+				}
+				if iface, ok := T.Underlying().(*types.Interface); ok && types.Implements(impl, iface) {
+					return true
+				}
+			}
+			return false
+		}
+		qf := types.RelativeTo(j.NodePackage(tsStmt).Pkg)
+		var missing []string
+		for _, impl := range impls {
+			if !covered(impl) {
+				missing = append(missing, types.TypeString(impl, qf))
+			}
+		}
+		if len(missing) == 0 {
+			return true
+		}
+		j.Errorf(tsStmt, "type switch on %s doesn't handle %s and has no default case",
+			Render(j, ta.X), strings.Join(missing, ", "))
+		return true
+	}
+	for _, f := range c.filterGenerated(j.Program.Files) {
+		ast.Inspect(f, fn)
+	}
+}
+
+func (c *Checker) CheckUnboundedGoroutines(j *lint.Job) {
+	// bounded reports whether the loop body contains something that
+	// may limit the number of goroutines running at once: a channel
+	// operation, as used by semaphores and worker pools, or waiting
+	// for previously started goroutines.
+	bounded := func(body *ast.BlockStmt) bool {
+		found := false
+		ast.Inspect(body, func(node ast.Node) bool {
+			switch node := node.(type) {
+			case *ast.GoStmt:
+				// channel operations in the goroutines themselves,
+				// such as releasing a semaphore, don't count
+				for _, arg := range node.Call.Args {
+					ast.Inspect(arg, func(node ast.Node) bool {
+						found = found || isChannelOp(node)
+						return !found
+					})
+				}
+				return false
+			case *ast.CallExpr:
+				if sel, ok := node.Fun.(*ast.SelectorExpr); ok {
+					switch sel.Sel.Name {
+					case "Wait", "Acquire":
+						found = true
+					}
+				}
+			default:
+				found = found || isChannelOp(node)
+			}
+			return !found
+		})
+		return found
+	}
+	fn := func(node ast.Node) bool {
+		loop, ok := node.(*ast.RangeStmt)
+		if !ok {
+			return true
+		}
+		switch TypeOf(j, loop.X).Underlying().(type) {
+		case *types.Slice, *types.Map, *types.Chan:
+		default:
+			return true
+		}
+		for _, stmt := range loop.Body.List {
+			gostmt, ok := stmt.(*ast.GoStmt)
+			if !ok {
+				continue
+			}
+			if bounded(loop.Body) {
+				return true
+			}
+			j.Errorf(gostmt, "starting a goroutine for every element of %s doesn't bound the number of concurrent goroutines", Render(j, loop.X))
+			return true
+		}
+		return true
+	}
+	for _, f := range c.filterGenerated(j.Program.Files) {
+		ast.Inspect(f, fn)
+	}
+}
+
+// isChannelOp reports whether node sends to or receives from a
+// channel.
+func isChannelOp(node ast.Node) bool {
+	switch node := node.(type) {
+	case *ast.SendStmt:
+		return true
+	case *ast.UnaryExpr:
+		return node.Op == token.ARROW
+	}
+	return false
+}
+
+func (c *Checker) CheckOnceVaryingCapture(j *lint.Job) {
+	// rootIdent returns the identifier that a chain of selectors
+	// starts with, such as t in t.once.
+	var rootIdent func(expr ast.Expr) *ast.Ident
+	rootIdent = func(expr ast.Expr) *ast.Ident {
+		switch expr := expr.(type) {
+		case *ast.Ident:
+			return expr
+		case *ast.SelectorExpr:
+			return rootIdent(expr.X)
+		case *ast.ParenExpr:
+			return rootIdent(expr.X)
+		case *ast.StarExpr:
+			return rootIdent(expr.X)
+		case *ast.UnaryExpr:
+			if expr.Op == token.AND {
+				return rootIdent(expr.X)
+			}
+		}
+		return nil
+	}
+	checkFunc := func(decl *ast.FuncDecl) {
+		params := map[types.Object]bool{}
+		var fields []*ast.Field
+		if decl.Recv != nil {
+			fields = append(fields, decl.Recv.List...)
+		}
+		fields = append(fields, decl.Type.Params.List...)
+		for _, field := range fields {
+			for _, name := range field.Names {
+				params[ObjectOf(j, name)] = true
+			}
+		}
+		if len(params) == 0 {
+			return
+		}
+		ast.Inspect(decl.Body, func(node ast.Node) bool {
+			call, ok := node.(*ast.CallExpr)
+			if !ok || len(call.Args) != 1 {
+				return true
+			}
+			sel, ok := call.Fun.(*ast.SelectorExpr)
+			if !ok {
+				return true
+			}
+			fn, ok := ObjectOf(j, sel.Sel).(*types.Func)
+			if !ok || fn.FullName() != "(*sync.Once).Do" {
+				return true
+			}
+			lit, ok := call.Args[0].(*ast.FuncLit)
+			if !ok {
+				return true
+			}
+			root := rootIdent(sel.X)
+			if root == nil {
+				return true
+			}
+			obj, ok := ObjectOf(j, root).(*types.Var)
+			if !ok {
+				return true
+			}
+			// Only a Once that outlives the call is shared between
+			// calls: a package-level variable, or one reached through
+			// a parameter such as the receiver.
+			if obj.Parent() != obj.Pkg().Scope() && !params[obj] {
+				return true
+			}
+			seen := map[types.Object]bool{}
+			ast.Inspect(lit.Body, func(node ast.Node) bool {
+				ident, ok := node.(*ast.Ident)
+				if !ok {
+					return true
+				}
+				used := ObjectOf(j, ident)
+				if !params[used] || used == obj || seen[used] {
+					return true
+				}
+				seen[used] = true
+				j.Errorf(ident, "the function passed to %s.Do uses %s, which may differ between calls of %s, but only the first call runs the function",
+					Render(j, sel.X), ident.Name, decl.Name.Name)
+				return true
+			})
+			return true
+		})
+	}
+	for _, f := range j.Program.Files {
+		for _, decl := range f.Decls {
+			if decl, ok := decl.(*ast.FuncDecl); ok && decl.Body != nil {
+				checkFunc(decl)
+			}
+		}
+	}
+}
+
+func (c *Checker) CheckSleepInLoop(j *lint.Job) {
+	// Sleeping in a loop usually means waiting for a condition by
+	// polling it. Tests and main packages get a pass, as they
+	// commonly wait for external processes that can't be
+	// synchronized with.
+	var inspectBody func(node ast.Node) bool
+	inspectBody = func(node ast.Node) bool {
+		switch node := node.(type) {
+		case *ast.FuncLit:
+			return false
+		case *ast.ForStmt:
+			// nested loops are inspected on their own
+			return false
+		case *ast.CallExpr:
+			if IsCallToAST(j, node, "time.Sleep") {
+				j.Errorf(node, "time.Sleep in a loop is usually polling for a condition, which is slow and prone to races; consider waiting on a channel, sync.WaitGroup or sync.Cond instead")
+			}
+		}
+		return true
+	}
+	fn := func(node ast.Node) bool {
+		loop, ok := node.(*ast.ForStmt)
+		if !ok {
+			return true
+		}
+		ast.Inspect(loop.Body, inspectBody)
+		return true
+	}
+	for _, f := range c.filterGenerated(j.Program.Files) {
+		if IsInTest(j, f) || IsInMain(j, f) {
+			continue
+		}
+		ast.Inspect(f, fn)
+	}
+}
+
+func (c *Checker) CheckLargeChanCapacity(j *lint.Job) {
+	// isLiteral reports whether expr consists only of literals, as
+	// opposed to referring to named constants, whose names explain
+	// their values.
+	isLiteral := func(expr ast.Expr) bool {
+		literal := true
+		ast.Inspect(expr, func(node ast.Node) bool {
+			if _, ok := node.(*ast.Ident); ok {
+				literal = false
+			}
+			return literal
+		})
+		return literal
+	}
+	fn := func(node ast.Node) bool {
+		call, ok := node.(*ast.CallExpr)
+		if !ok || len(call.Args) != 2 {
+			return true
+		}
+		ident, ok := call.Fun.(*ast.Ident)
+		if !ok {
+			return true
+		}
+		if _, ok := ObjectOf(j, ident).(*types.Builtin); !ok || ident.Name != "make" {
+			return true
+		}
+		if _, ok := TypeOf(j, call.Args[0]).Underlying().(*types.Chan); !ok {
+			return true
+		}
+		n, ok := ExprToInt(j, call.Args[1])
+		if !ok || n <= c.ChanCapacityThreshold || !isLiteral(call.Args[1]) {
+			return true
+		}
+		j.Errorf(call.Args[1], "channel created with a capacity of %d; a large buffer may hide missing backpressure, consider using a named constant that explains the capacity", n)
+		return true
+	}
+	for _, f := range c.filterGenerated(j.Program.Files) {
+		ast.Inspect(f, fn)
+	}
+}
+
+func (c *Checker) CheckAppendToSubslice(j *lint.Job) {
+	// uses reports whether any of stmts refers to obj.
+	uses := func(stmts []ast.Stmt, obj types.Object) bool {
+		found := false
+		for _, stmt := range stmts {
+			ast.Inspect(stmt, func(node ast.Node) bool {
+				if ident, ok := node.(*ast.Ident); ok && ObjectOf(j, ident) == obj {
+					found = true
+				}
+				return !found
+			})
+		}
+		return found
+	}
+	// isAppendTo reports whether expr is a call of append that
+	// appends to obj.
+	isAppendTo := func(expr ast.Expr, obj types.Object) bool {
+		call, ok := expr.(*ast.CallExpr)
+		if !ok || len(call.Args) == 0 {
+			return false
+		}
+		fn, ok := call.Fun.(*ast.Ident)
+		if !ok || fn.Name != "append" {
+			return false
+		}
+		if _, ok := ObjectOf(j, fn).(*types.Builtin); !ok {
+			return false
+		}
+		ident, ok := call.Args[0].(*ast.Ident)
+		return ok && ObjectOf(j, ident) == obj
+	}
+	// appendTo returns the first call of append in stmt that appends
+	// to obj.
+	appendTo := func(stmt ast.Stmt, obj types.Object) *ast.CallExpr {
+		var call *ast.CallExpr
+		ast.Inspect(stmt, func(node ast.Node) bool {
+			if expr, ok := node.(ast.Expr); ok && isAppendTo(expr, obj) {
+				call = expr.(*ast.CallExpr)
+			}
+			return call == nil
+		})
+		return call
+	}
+	// assigns reports whether stmt assigns to obj, other than with
+	// the result of appending to obj.
+	assigns := func(stmt ast.Stmt, obj types.Object) bool {
+		assign, ok := stmt.(*ast.AssignStmt)
+		if !ok {
+			return false
+		}
+		for i, lhs := range assign.Lhs {
+			ident, ok := lhs.(*ast.Ident)
+			if !ok || ObjectOf(j, ident) != obj {
+				continue
+			}
+			if len(assign.Lhs) != len(assign.Rhs) || !isAppendTo(assign.Rhs[i], obj) {
+				return true
+			}
+		}
+		return false
+	}
+	checkStmts := func(stmts []ast.Stmt) {
+		for i, stmt := range stmts {
+			assign, ok := stmt.(*ast.AssignStmt)
+			if !ok || len(assign.Lhs) != 1 || len(assign.Rhs) != 1 {
+				continue
+			}
+			lhs, ok := assign.Lhs[0].(*ast.Ident)
+			if !ok {
+				continue
+			}
+			slice, ok := assign.Rhs[0].(*ast.SliceExpr)
+			// full slice expressions limit the capacity, and a
+			// sub-slice that extends to the end of the original
+			// slice only appends to elements the original can't see
+			if !ok || slice.Slice3 || slice.High == nil {
+				continue
+			}
+			x, ok := slice.X.(*ast.Ident)
+			if !ok {
+				continue
+			}
+			if _, ok := TypeOf(j, x).Underlying().(*types.Slice); !ok {
+				continue
+			}
+			sub := ObjectOf(j, lhs)
+			orig := ObjectOf(j, x)
+			if sub == nil || orig == nil || sub == orig {
+				continue
+			}
+			for k, next := range stmts[i+1:] {
+				if call := appendTo(next, sub); call != nil {
+					if uses(stmts[i+k+2:], orig) {
+						j.Errorf(call, "appending to %s may overwrite %s[%s:], which %s shares its backing array with and which is used later",
+							lhs.Name, x.Name, Render(j, slice.High), lhs.Name)
+					}
+					break
+				}
+				if assigns(next, sub) || assigns(next, orig) {
+					break
+				}
+			}
+		}
+	}
+	fn := func(node ast.Node) bool {
+		switch node := node.(type) {
+		case *ast.BlockStmt:
+			checkStmts(node.List)
+		case *ast.CaseClause:
+			checkStmts(node.Body)
+		case *ast.CommClause:
+			checkStmts(node.Body)
+		}
+		return true
+	}
+	for _, f := range c.filterGenerated(j.Program.Files) {
+		ast.Inspect(f, fn)
+	}
+}
+
+func (c *Checker) CheckNarrowingConversion(j *lint.Job) {
+	// bounds returns the smallest and largest value of the integer
+	// type T.
+	bounds := func(T *types.Basic) (vrp.Z, vrp.Z) {
+		bits := uint(8 * j.Program.Sizes.Sizeof(T))
+		if T.Info()&types.IsUnsigned != 0 {
+			max := new(big.Int).Lsh(big.NewInt(1), bits)
+			return vrp.NewZ(0), vrp.NewBigZ(max.Sub(max, big.NewInt(1)))
+		}
+		max := new(big.Int).Lsh(big.NewInt(1), bits-1)
+		min := new(big.Int).Neg(max)
+		return vrp.NewBigZ(min), vrp.NewBigZ(max.Sub(max, big.NewInt(1)))
+	}
+	isInteger := func(T types.Type) (*types.Basic, bool) {
+		basic, ok := T.Underlying().(*types.Basic)
+		return basic, ok && basic.Info()&types.IsInteger != 0
+	}
+	for _, ssafn := range j.Program.InitialFunctions {
+		for _, block := range ssafn.Blocks {
+			for _, ins := range block.Instrs {
+				conv, ok := ins.(*ssa.Convert)
+				if !ok || conv.Pos() == token.NoPos {
+					continue
+				}
+				src, ok1 := isInteger(conv.X.Type())
+				dst, ok2 := isInteger(conv.Type())
+				if !ok1 || !ok2 {
+					continue
+				}
+				smin, smax := bounds(src)
+				dmin, dmax := bounds(dst)
+				if smin.Cmp(dmin) >= 0 && smax.Cmp(dmax) <= 0 {
+					// not a narrowing conversion
+					continue
+				}
+
+				if k, ok := conv.X.(*ssa.Const); ok {
+					n, _ := new(big.Int).SetString(k.Value.ExactString(), 10)
+					if z := vrp.NewBigZ(n); z.Cmp(dmin) < 0 || z.Cmp(dmax) > 0 {
+						j.Errorf(conv, "conversion of %s to %s overflows", k.Value, conv.Type())
+					}
+					continue
+				}
+
+				r, ok := c.funcDescs.Get(ssafn).Ranges[conv.X].(vrp.IntInterval)
+				if !ok || !r.IsKnown() || r.Empty() {
+					r = vrp.NewIntInterval(smin, smax)
+				}
+				if r.Lower.Cmp(dmin) >= 0 && r.Upper.Cmp(dmax) <= 0 {
+					continue
+				}
+				// bounds that VRP couldn't determine are infinite or
+				// those of the type
+				lowerKnown := !r.Lower.Infinite() && r.Lower.Cmp(smin) > 0
+				upperKnown := !r.Upper.Infinite() && r.Upper.Cmp(smax) < 0
+				switch {
+				case r.Lower.Cmp(dmax) > 0 || r.Upper.Cmp(dmin) < 0:
+					j.Errorf(conv, "conversion from %s to %s overflows, as the value is in the range %s", conv.X.Type(), conv.Type(), r)
+				case (lowerKnown && r.Lower.Cmp(dmin) < 0) || (upperKnown && r.Upper.Cmp(dmax) > 0):
+					j.Errorf(conv, "conversion from %s to %s may overflow, as the value can be in the range %s", conv.X.Type(), conv.Type(), r)
+				case c.DynamicConversions:
+					p := j.Errorf(conv, "conversion from %s to %s may overflow", conv.X.Type(), conv.Type())
+					p.Confidence = 0.2
+				}
+			}
+		}
+	}
+}
+
+// isSortInterface reports whether T, or a pointer to T, has the Len
+// and Swap methods of sort.Interface.
+func isSortInterface(T types.Type) bool {
+	intT := types.Typ[types.Int]
+	lenSig := types.NewSignature(nil, nil,
+		types.NewTuple(types.NewVar(token.NoPos, nil, "", intT)), false)
+	swapSig := types.NewSignature(nil, types.NewTuple(
+		types.NewVar(token.NoPos, nil, "", intT),
+		types.NewVar(token.NoPos, nil, "", intT)), nil, false)
+	ms := types.NewMethodSet(types.NewPointer(T))
+	for name, sig := range map[string]*types.Signature{"Len": lenSig, "Swap": swapSig} {
+		sel := ms.Lookup(nil, name)
+		if sel == nil {
+			return false
+		}
+		fn, ok := sel.Obj().(*types.Func)
+		if !ok {
+			return false
+		}
+		msig := fn.Type().(*types.Signature)
+		if !types.Identical(msig.Params(), sig.Params()) || !types.Identical(msig.Results(), sig.Results()) {
+			return false
+		}
+	}
+	return true
+}
+
+func (c *Checker) CheckNonStrictLess(j *lint.Job) {
+	fn := func(node ast.Node) bool {
+		decl, ok := node.(*ast.FuncDecl)
+		if !ok || decl.Recv == nil || decl.Body == nil || decl.Name.Name != "Less" {
+			return true
+		}
+		obj, ok := ObjectOf(j, decl.Name).(*types.Func)
+		if !ok {
+			return true
+		}
+		sig := obj.Type().(*types.Signature)
+		if sig.Params().Len() != 2 || sig.Results().Len() != 1 ||
+			!IsType(sig.Params().At(0).Type(), "int") ||
+			!IsType(sig.Params().At(1).Type(), "int") ||
+			!IsType(sig.Results().At(0).Type(), "bool") {
+			return true
+		}
+		recv := sig.Recv().Type()
+		if ptr, ok := recv.(*types.Pointer); ok {
+			recv = ptr.Elem()
+		}
+		if !isSortInterface(recv) {
+			return true
+		}
+		ast.Inspect(decl.Body, func(node ast.Node) bool {
+			if _, ok := node.(*ast.FuncLit); ok {
+				return false
+			}
+			ret, ok := node.(*ast.ReturnStmt)
+			if !ok || len(ret.Results) != 1 {
+				return true
+			}
+			bin, ok := Unparen(ret.Results[0]).(*ast.BinaryExpr)
+			if !ok {
+				return true
+			}
+			var strict token.Token
+			switch bin.Op {
+			case token.LEQ:
+				strict = token.LSS
+			case token.GEQ:
+				strict = token.GTR
+			default:
+				return true
+			}
+			j.Errorf(bin, "Less uses %s, which reports true for equal elements and violates the contract of sort.Interface; use %s instead", bin.Op, strict)
+			return true
+		})
+		return true
+	}
+	for _, f := range c.filterGenerated(j.Program.Files) {
+		ast.Inspect(f, fn)
+	}
+}
+
+func (c *Checker) CheckUncheckedCopy(j *lint.Job) {
+	constInt := func(v ssa.Value) (int64, bool) {
+		k, ok := v.(*ssa.Const)
+		if !ok || k.Value == nil || k.Value.Kind() != constant.Int {
+			return 0, false
+		}
+		return k.Int64(), true
+	}
+	// length returns the length of the slice or string v, if it is
+	// constant.
+	var length func(v ssa.Value) (int64, bool)
+	length = func(v ssa.Value) (int64, bool) {
+		switch v := v.(type) {
+		case *ssa.Const:
+			if v.Value != nil && v.Value.Kind() == constant.String {
+				return int64(len(constant.StringVal(v.Value))), true
+			}
+		case *ssa.MakeSlice:
+			return constInt(v.Len)
+		case *ssa.Slice:
+			var lo, hi int64
+			var ok bool
+			if v.Low != nil {
+				if lo, ok = constInt(v.Low); !ok {
+					return 0, false
+				}
+			}
+			if v.High != nil {
+				hi, ok = constInt(v.High)
+			} else if ptr, isPtr := v.X.Type().Underlying().(*types.Pointer); isPtr {
+				arr, isArr := ptr.Elem().Underlying().(*types.Array)
+				hi, ok = arr.Len(), isArr
+			} else {
+				hi, ok = length(v.X)
+			}
+			return hi - lo, ok
+		}
+		return 0, false
+	}
+	// safe reports whether dst is provably at least as long as src.
+	safe := func(dst, src ssa.Value) bool {
+		if mk, ok := dst.(*ssa.MakeSlice); ok {
+			// make([]T, len(src))
+			if call, ok := mk.Len.(*ssa.Call); ok && IsCallTo(call.Common(), "len") && call.Call.Args[0] == src {
+				return true
+			}
+		}
+		dl, ok1 := length(dst)
+		sl, ok2 := length(src)
+		return ok1 && ok2 && dl >= sl
+	}
+	for _, ssafn := range j.Program.InitialFunctions {
+		for _, block := range ssafn.Blocks {
+			for _, ins := range block.Instrs {
+				call, ok := ins.(*ssa.Call)
+				if !ok || !IsCallTo(call.Common(), "copy") {
+					continue
+				}
+				if refs := call.Referrers(); refs == nil || len(*refs) != 0 {
+					continue
+				}
+				args := call.Common().Args
+				if safe(args[0], args[1]) {
+					continue
+				}
+				p := j.Errorf(call, "the number of elements copied is ignored, but the destination may be shorter than the source, which silently truncates the copy")
+				p.Confidence = 0.5
+			}
+		}
+	}
+}
+
+func (c *Checker) CheckUnsynchronizedGoroutineWrite(j *lint.Job) {
+	// isSync reports whether node may synchronize with a goroutine:
+	// a channel operation, a select statement, ranging over a
+	// channel, or waiting or locking with the sync package.
+	isSync := func(node ast.Node) bool {
+		switch node := node.(type) {
+		case *ast.SelectStmt:
+			return true
+		case *ast.RangeStmt:
+			_, ok := TypeOf(j, node.X).Underlying().(*types.Chan)
+			return ok
+		case *ast.CallExpr:
+			return IsCallToAnyAST(j, node,
+				"(*sync.WaitGroup).Wait", "(*sync.Mutex).Lock",
+				"(*sync.RWMutex).Lock", "(*sync.RWMutex).RLock",
+				"(*sync.Cond).Wait", "(*sync.Once).Do")
+		}
+		return isChannelOp(node)
+	}
+	// writes returns the first write in lit of each variable that is
+	// declared outside of lit but inside of outer, the function that
+	// starts the goroutine.
+	writes := func(lit *ast.FuncLit, outer ast.Node) map[*types.Var]*ast.Ident {
+		out := map[*types.Var]*ast.Ident{}
+		record := func(expr ast.Expr) {
+			ident, ok := Unparen(expr).(*ast.Ident)
+			if !ok {
+				return
+			}
+			v, ok := ObjectOf(j, ident).(*types.Var)
+			if !ok || v.Pos() < outer.Pos() || v.Pos() >= outer.End() ||
+				(v.Pos() >= lit.Pos() && v.Pos() < lit.End()) {
+				return
+			}
+			if _, ok := out[v]; !ok {
+				out[v] = ident
+			}
+		}
+		ast.Inspect(lit.Body, func(node ast.Node) bool {
+			switch node := node.(type) {
+			case *ast.AssignStmt:
+				if node.Tok != token.DEFINE {
+					for _, lhs := range node.Lhs {
+						record(lhs)
+					}
+				}
+			case *ast.IncDecStmt:
+				record(node.X)
+			}
+			return true
+		})
+		return out
+	}
+	check := func(outer ast.Node, body *ast.BlockStmt) {
+		var gos []*ast.GoStmt
+		ast.Inspect(body, func(node ast.Node) bool {
+			switch node := node.(type) {
+			case *ast.FuncLit:
+				// checked on its own
+				return false
+			case *ast.GoStmt:
+				if _, ok := node.Call.Fun.(*ast.FuncLit); ok {
+					gos = append(gos, node)
+				}
+			}
+			return true
+		})
+		for _, g := range gos {
+			written := writes(g.Call.Fun.(*ast.FuncLit), outer)
+			if len(written) == 0 {
+				continue
+			}
+			// Look for reads in the code after the go statement, up
+			// to the first point that may synchronize with the
+			// goroutine.
+			reported := map[*types.Var]bool{}
+			synced := false
+			var visit func(node ast.Node) bool
+			visit = func(node ast.Node) bool {
+				if synced || node == nil || node == g || node.End() <= g.Pos() {
+					return false
+				}
+				if node.Pos() <= g.Pos() {
+					// encloses the go statement
+					return true
+				}
+				if _, ok := node.(*ast.FuncLit); ok {
+					return false
+				}
+				if isSync(node) {
+					synced = true
+					return false
+				}
+				if assign, ok := node.(*ast.AssignStmt); ok && assign.Tok == token.ASSIGN {
+					// assigning to a variable doesn't read it
+					for _, lhs := range assign.Lhs {
+						if _, ok := Unparen(lhs).(*ast.Ident); !ok {
+							ast.Inspect(lhs, visit)
+						}
+					}
+					for _, rhs := range assign.Rhs {
+						ast.Inspect(rhs, visit)
+					}
+					return false
+				}
+				ident, ok := node.(*ast.Ident)
+				if !ok {
+					return true
+				}
+				v, ok := ObjectOf(j, ident).(*types.Var)
+				if !ok || reported[v] {
+					return true
+				}
+				write, ok := written[v]
+				if !ok {
+					return true
+				}
+				reported[v] = true
+				p := j.Errorf(ident, "%s is read while the goroutine started on line %d may still be writing it; synchronize with a channel or sync.WaitGroup first",
+					ident.Name, j.Program.DisplayPosition(g.Pos()).Line)
+				p.Related = append(p.Related, j.Related(write, "%s is written here", ident.Name))
+				return true
+			}
+			ast.Inspect(body, visit)
+		}
+	}
+	fn := func(node ast.Node) bool {
+		switch node := node.(type) {
+		case *ast.FuncDecl:
+			if node.Body != nil {
+				check(node, node.Body)
+			}
+		case *ast.FuncLit:
+			check(node, node.Body)
+		}
+		return true
+	}
+	for _, f := range c.filterGenerated(j.Program.Files) {
+		ast.Inspect(f, fn)
+	}
+}
@@ -32,3 +32,21 @@ func BenchmarkNetHttp(b *testing.B) {
 		}
 	}
 }
+
+func TestChanCapacityThreshold(t *testing.T) {
+	c := NewChecker()
+	c.ChanCapacityThreshold = 5
+	testutil.TestAll(t, c, "CheckLargeChanCapacityThreshold")
+}
+
+func TestPointerSliceThreshold(t *testing.T) {
+	c := NewChecker()
+	c.PointerSliceThreshold = 32
+	testutil.TestAll(t, c, "CheckSmallStructPointerSliceThreshold")
+}
+
+func TestDynamicConversions(t *testing.T) {
+	c := NewChecker()
+	c.DynamicConversions = true
+	testutil.TestAll(t, c, "CheckNarrowingConversionDynamic")
+}
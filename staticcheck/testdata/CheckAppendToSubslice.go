@@ -0,0 +1,72 @@
+package pkg
+
+var sink []int
+
+func fn1(a []int) {
+	b := a[:2]
+	b = append(b, 1) // MATCH "appending to b may overwrite a[2:], which b shares its backing array with and which is used later"
+	_ = a[2]
+	sink = b
+}
+
+func fn2(a []int, n int) {
+	b := a[1:n]
+	for i := 0; i < 3; i++ {
+		b = append(b, i) // MATCH "appending to b may overwrite a[n:]"
+	}
+	println(len(a))
+	sink = b
+}
+
+func fn3(a []int) []int {
+	// a isn't used after appending to b
+	b := a[:2]
+	b = append(b, 1)
+	return b
+}
+
+func fn4(a []int) {
+	// full slice expressions limit the capacity of b
+	b := a[:2:2]
+	b = append(b, 1)
+	_ = a[2]
+	sink = b
+}
+
+func fn5(a []int) {
+	// copying the elements doesn't share the backing array
+	b := make([]int, 2)
+	copy(b, a[:2])
+	b = append(b, 1)
+	_ = a[2]
+	sink = b
+
+	c := append([]int(nil), a[:2]...)
+	c = append(c, 1)
+	_ = a[2]
+	sink = c
+}
+
+func fn6(a []int) {
+	// b no longer aliases a when it's appended to
+	b := a[:2]
+	println(len(b))
+	b = nil
+	b = append(b, 1)
+	_ = a[2]
+	sink = b
+}
+
+func fn7(a []int) {
+	// b extends to the end of a
+	b := a[1:]
+	b = append(b, 1)
+	_ = a[0]
+	sink = b
+}
+
+func fn8(s string) {
+	b := s[:2]
+	_ = b
+	_ = s
+}
@@ -0,0 +1,12 @@
+package pkg
+
+func fn1(x int) { // MATCH "argument x is overwritten before first use"
+	x = 1 // RELATED "x is overwritten here"
+	println(x)
+}
+
+func fn2(x int) {
+	println(x)
+	x = 1
+	println(x)
+}
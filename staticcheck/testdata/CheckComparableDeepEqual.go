@@ -0,0 +1,44 @@
+package pkg
+
+import "reflect"
+
+type Point struct {
+	X, Y int
+	Name string
+}
+
+type Nested struct {
+	P   Point
+	Arr [2]int
+}
+
+type WithPointer struct {
+	P *int
+}
+
+type WithSlice struct {
+	S []int
+}
+
+type WithFloat struct {
+	F float64
+}
+
+type WithInterface struct {
+	V interface{}
+}
+
+func fn(a, b Point, n1, n2 Nested, i, k int, s1, s2 string, wp1, wp2 WithPointer, ws1, ws2 WithSlice, wf1, wf2 WithFloat, wi1, wi2 WithInterface, f1, f2 float64, p1, p2 *Point) {
+	_ = reflect.DeepEqual(a, b)   // MATCH "a and b can be compared with ==, which is faster than reflect.DeepEqual"
+	_ = reflect.DeepEqual(n1, n2) // MATCH "n1 and n2 can be compared with =="
+	_ = reflect.DeepEqual(i, k)   // MATCH "i and k can be compared with =="
+	_ = reflect.DeepEqual(s1, s2) // MATCH "s1 and s2 can be compared with =="
+	_ = reflect.DeepEqual(wp1, wp2)
+	_ = reflect.DeepEqual(ws1, ws2)
+	_ = reflect.DeepEqual(wf1, wf2)
+	_ = reflect.DeepEqual(wi1, wi2)
+	_ = reflect.DeepEqual(f1, f2)
+	_ = reflect.DeepEqual(p1, p2)
+	_ = reflect.DeepEqual(i, s1)
+	_ = reflect.DeepEqual(a, &b)
+}
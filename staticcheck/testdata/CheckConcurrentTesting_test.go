@@ -0,0 +1,24 @@
+package pkg
+
+import "testing"
+
+func TestConcurrent(t *testing.T) {
+	done := make(chan struct{})
+	go func() { // MATCH /the goroutine calls T.FailNow, which must be called in the same goroutine as the test/
+		defer close(done)
+		if testing.Short() {
+			t.FailNow()
+		}
+	}()
+	<-done
+}
+
+func TestConcurrentError(t *testing.T) {
+	errs := make(chan error)
+	go func() {
+		errs <- nil
+	}()
+	if err := <-errs; err != nil {
+		t.Fatal(err)
+	}
+}
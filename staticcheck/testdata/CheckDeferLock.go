@@ -6,7 +6,7 @@ var r sync.Mutex
 var rw sync.RWMutex
 
 func fn1() {
-	r.Lock()
+	r.Lock() // RELATED "locked here"
 	defer r.Lock() // MATCH /deferring Lock right after having locked already; did you mean to defer Unlock/
 }
 
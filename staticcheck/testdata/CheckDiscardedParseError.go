@@ -0,0 +1,20 @@
+package pkg
+
+import "strconv"
+
+func fn(s string) {
+	n, _ := strconv.Atoi(s)      // MATCH "discarding the error of strconv.Atoi means that invalid input goes unnoticed and n silently gets a zero value"
+	b, _ := strconv.ParseBool(s) // MATCH "discarding the error of strconv.ParseBool"
+	var f float64
+	f, _ = strconv.ParseFloat(s, 64)    // MATCH "discarding the error of strconv.ParseFloat"
+	i, _ := strconv.ParseInt(s, 10, 64) // MATCH "discarding the error of strconv.ParseInt"
+
+	m, err := strconv.Atoi(s)
+	if err != nil {
+		return
+	}
+	_, _ = strconv.Atoi(s)
+	u, err := strconv.ParseUint(s, 10, 64)
+	_ = err
+	println(n, b, f, i, m, u)
+}
@@ -0,0 +1,47 @@
+package pkg
+
+import (
+	"errors"
+	"fmt"
+)
+
+func fn1(x int) error {
+	var err error
+	if x < 0 {
+		err = fmt.Errorf("negative value %d", x) // MATCH "the error created by fmt.Errorf is never returned or used; did you forget to return it?"
+	}
+	if x > 10 {
+		err = errors.New("too large") // MATCH "the error created by errors.New is never returned or used"
+	}
+	err = nil
+	return err
+}
+
+func fn2(x int) error {
+	if x < 0 {
+		return fmt.Errorf("negative value %d", x)
+	}
+	var err error
+	if x > 10 {
+		err = errors.New("too large")
+	}
+	return err
+}
+
+func fn3(x int) {
+	if x < 0 {
+		fmt.Errorf("negative value %d", x) // MATCH "the error created by fmt.Errorf is never returned or used"
+	}
+	err := errors.New("logged")
+	println(err.Error())
+	_ = errors.New("deliberately discarded")
+}
+
+func fn4(errs chan error) {
+	errs <- errors.New("sent")
+	var err error
+	for i := 0; i < 3; i++ {
+		err = fmt.Errorf("attempt %d", i)
+	}
+	panic(err)
+}
@@ -0,0 +1,55 @@
+package pkg
+
+import "io"
+
+type Shape interface {
+	area() float64
+}
+
+type Circle struct{ r float64 }
+type Square struct{ a float64 }
+type Triangle struct{ b, h float64 }
+
+func (c Circle) area() float64   { return 3 * c.r * c.r }
+func (s *Square) area() float64  { return s.a * s.a }
+func (t Triangle) area() float64 { return t.b * t.h / 2 }
+func (Triangle) corners() int    { return 3 }
+
+type cornered interface {
+	Shape
+	corners() int
+}
+
+func fn1(s Shape) {
+	switch s.(type) { // MATCH "type switch on s doesn't handle *Square, Triangle and has no default case"
+	case Circle:
+	}
+
+	switch s := s.(type) {
+	case Circle, *Circle:
+	case *Square:
+	case Triangle:
+		_ = s
+	}
+
+	switch s.(type) {
+	case *Circle:
+	case *Square:
+	case cornered:
+	}
+
+	switch s.(type) {
+	case Circle:
+	default:
+	}
+
+	switch s.(type) { // MATCH "type switch on s doesn't handle Circle and has no default case"
+	case *Square, *Triangle:
+	}
+}
+
+func fn2(r io.Reader) {
+	switch r.(type) {
+	case *io.LimitedReader:
+	}
+}
@@ -0,0 +1,48 @@
+package pkg
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+)
+
+func fn1(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain")
+	w.WriteHeader(200)
+	w.Header().Set("X-Foo", "bar") // MATCH "modifying headers after the response has been written has no effect"
+}
+
+func fn2(w http.ResponseWriter, r *http.Request) {
+	w.Write(nil)
+	w.Header().Add("X-Foo", "bar") // MATCH "modifying headers after the response has been written has no effect"
+}
+
+func fn3(w http.ResponseWriter, r *http.Request) {
+	fmt.Fprintf(w, "hello")
+	w.Header().Del("X-Foo") // MATCH "modifying headers after the response has been written has no effect"
+}
+
+func fn4(w http.ResponseWriter, r *http.Request) {
+	io.WriteString(w, "hello")
+	if r != nil {
+		w.Header().Set("X-Foo", "bar") // MATCH "modifying headers after the response has been written has no effect"
+	}
+}
+
+func fn5(w http.ResponseWriter, r *http.Request) {
+	if r.Method == "HEAD" {
+		w.WriteHeader(200)
+	}
+	w.Header().Set("X-Foo", "bar")
+}
+
+func fn6(w http.ResponseWriter, r *http.Request) {
+	defer w.WriteHeader(200)
+	w.Header().Set("X-Foo", "bar")
+	w.Write(nil)
+}
+
+func fn7(w1, w2 http.ResponseWriter) {
+	w1.Write(nil)
+	w2.Header().Set("X-Foo", "bar")
+}
@@ -0,0 +1,67 @@
+package pkg
+
+type T struct {
+	n    int
+	name string
+	arr  [2]int
+	s    []int
+	m    map[string]int
+	p    *T
+	in   struct{ x int }
+}
+
+func (t T) SetName(name string) {
+	t.name = name // MATCH "ineffective assignment to field name; SetName has a value receiver, so the change is discarded when the method returns. Did you mean to use a pointer receiver?"
+}
+
+func (t T) Inc() {
+	t.n++               // MATCH "ineffective assignment to field n"
+	t.in.x = t.in.x + 1 // MATCH "ineffective assignment to field in"
+	t.arr[0] = 1        // MATCH "ineffective assignment to field arr"
+}
+
+func (t T) External() {
+	t.s[0] = 1
+	t.m["a"] = 1
+	t.p.n = 1
+}
+
+func (t T) WithName(name string) T {
+	t.name = name
+	return t
+}
+
+func (t T) Observed() int {
+	t.n = 2
+	return t.n * 2
+}
+
+func (t *T) SetNamePtr(name string) {
+	t.name = name
+}
+
+func (T) Unnamed() {}
+
+type Embedded struct{ x, y int }
+
+func (e Embedded) Sum() int { return e.x + e.y }
+
+type U struct {
+	Embedded
+	z int
+}
+
+func (u U) Promoted() int {
+	u.x = 1
+	return u.Embedded.Sum()
+}
+
+func (u U) PromotedRead() int {
+	u.y = 1
+	return u.x
+}
+
+func (u U) PromotedUnused() {
+	u.x = 1 // MATCH "ineffective assignment to field Embedded"
+	_ = u.z
+}
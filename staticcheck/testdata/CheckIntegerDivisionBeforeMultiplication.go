@@ -0,0 +1,10 @@
+package pkg
+
+func fn(a, b, c int, f1, f2, f3 float64) {
+	_ = a / b * c // MATCH "integer division happens before the multiplication"
+	_ = (a / b) * c
+	_ = a * c / b
+	_ = a / 8 * 8
+	_ = f1 / f2 * f3
+	_ = a / b * c * 2 // MATCH "did you mean a * c / b?"
+}
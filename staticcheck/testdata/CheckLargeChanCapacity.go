@@ -0,0 +1,18 @@
+package pkg
+
+const queueSize = 1000
+
+func fn() {
+	_ = make(chan int)
+	_ = make(chan int, 0)
+	_ = make(chan int, 100)
+	_ = make(chan int, 101)      // MATCH "channel created with a capacity of 101"
+	_ = make(chan string, 1<<20) // MATCH "channel created with a capacity of 1048576"
+	_ = make(chan int, queueSize)
+	_ = make(chan int, 2*queueSize)
+	_ = make([]int, 1000)
+	_ = make(map[int]int, 1000)
+
+	n := 1000
+	_ = make(chan int, n)
+}
@@ -0,0 +1,6 @@
+package pkg
+
+func fn() {
+	_ = make(chan int, 5)
+	_ = make(chan int, 6) // MATCH "channel created with a capacity of 6"
+}
@@ -47,3 +47,44 @@ func (t T) bar() {
 		println("")
 	}
 }
+
+func poll(interval time.Duration, fn func() bool) {
+	for range time.Tick(interval) { // MATCH /use time.NewTicker with a deferred call to Stop/
+		if fn() {
+			return
+		}
+	}
+}
+
+func pollTicker(interval time.Duration, fn func() bool) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for range ticker.C {
+		if fn() {
+			return
+		}
+	}
+}
+
+var heartbeat = time.Tick(time.Second)
+
+func init() {
+	go func() {
+		for range time.Tick(0) {
+			println("")
+			if true {
+				return
+			}
+		}
+	}()
+}
+
+func fn5() {
+	//lint:ignore SA1015 this ticker lives as long as the program
+	for range time.Tick(0) {
+		println("")
+		if true {
+			return
+		}
+	}
+}
@@ -0,0 +1,41 @@
+package pkg
+
+type T struct{ p *int }
+
+func fn1(xs []int, out []*int) []*int {
+	for _, v := range xs {
+		out = append(out, &v)
+	}
+	for _, v := range xs {
+		v := v
+		out = append(out, &v)
+	}
+	for i := 0; i < 10; i++ {
+		out = append(out, &i)
+	}
+	return out
+}
+
+func fn2(xs []int, m map[int]*int, ch chan *int, ts []T) {
+	var last *int
+	for k, v := range xs {
+		last = &v
+		m[k] = &v
+		ch <- &v
+		ts = append(ts, T{p: &v})
+
+		p := &v
+		println(*p)
+	}
+	_ = last
+	_ = ts
+}
+
+func fn3(xs []int) *int {
+	for _, v := range xs {
+		if v > 0 {
+			return &v
+		}
+	}
+	return nil
+}
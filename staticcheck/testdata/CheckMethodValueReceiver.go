@@ -0,0 +1,83 @@
+package pkg
+
+type T struct{ x int }
+
+func (T) Value()   {}
+func (*T) Ptr()    {}
+func (T) Get() int { return 0 }
+
+type I interface{ Value() }
+
+func fn1(ts []T, ptrs []*T, is []I) {
+	var fns []func()
+	for _, t := range ts {
+		fns = append(fns, t.Ptr) // MATCH "the method value t.Ptr binds the address of the loop variable t"
+		fns = append(fns, t.Value)
+	}
+	for _, t := range ptrs {
+		fns = append(fns, t.Ptr)
+	}
+	for _, i := range is {
+		fns = append(fns, i.Value)
+	}
+	for _, t := range ts {
+		t := t
+		fns = append(fns, t.Ptr)
+	}
+
+	var f func()
+	for _, t := range ts {
+		f = t.Ptr // MATCH "the method value t.Ptr binds the address of the loop variable t"
+	}
+	_ = f
+
+	for _, t := range ts {
+		g := t.Ptr
+		g()
+	}
+
+	ch := make(chan func())
+	for i, t := 0, (T{}); i < 10; i++ {
+		ch <- t.Ptr // MATCH "the method value t.Ptr binds the address of the loop variable t"
+	}
+
+	m := map[int]func(){}
+	for k, t := range ts {
+		m[k] = t.Ptr // MATCH "the method value t.Ptr binds the address of the loop variable t"
+	}
+	_ = fns
+}
+
+func fn2() {
+	var t T
+	f := t.Value // MATCH "the method value t.Value copies t when it is evaluated; the later modification of t.x won't be visible when calling f"
+	t.x = 1
+	f()
+
+	var t2 T
+	g := t2.Get // MATCH "the later modification of t2 won't be visible"
+	t2 = T{x: 1}
+	_ = g()
+
+	var t3 T
+	h := t3.Ptr
+	t3.x = 1
+	h()
+
+	var t4 T
+	f4 := t4.Value
+	f4()
+	t4.x = 1
+
+	var t5 T
+	f5 := t5.Value
+	_ = []func(){f5}
+	t5.x++
+	f5 = t5.Value
+	f5()
+
+	t6 := &T{}
+	f6 := t6.Value
+	t6.x = 1
+	f6()
+}
@@ -0,0 +1,12 @@
+package pkg
+
+type T2 struct{}
+
+func (*T2) Ptr() {}
+
+func fn(ts []T2, fns []func()) {
+	for _, t := range ts {
+		fns = append(fns, t.Ptr)
+	}
+	_ = fns
+}
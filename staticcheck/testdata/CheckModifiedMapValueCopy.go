@@ -0,0 +1,42 @@
+package pkg
+
+type T struct {
+	n     int
+	inner struct{ n int }
+	p     *T
+	s     []int
+}
+
+func fn1(m map[string]T) {
+	v := m["a"]
+	v.n = 1 // MATCH "v is a copy of the map value m["a"], modifying it doesn't change the map; assign it back with m["a"] = v"
+	v.inner.n++
+}
+
+func fn2(m map[string]T) {
+	v, ok := m["a"]
+	if ok {
+		v.n = 1 // MATCH /v is a copy of the map value/
+	}
+}
+
+func fn3(m map[string]T) {
+	v := m["a"]
+	v.n = 1
+	m["a"] = v
+}
+
+func fn4(m map[string]T) int {
+	v := m["a"]
+	v.n++
+	return v.n
+}
+
+func fn5(m map[string]T, pm map[string]*T) {
+	v := m["a"]
+	v.p.n = 1
+	v.s[0] = 1
+
+	w := pm["a"]
+	w.n = 1
+}
@@ -0,0 +1,40 @@
+package pkg
+
+func fn1() {
+	x := int64(1 << 40)
+	_ = int32(x) // MATCH "conversion of 1099511627776 to int32 overflows"
+
+	y := 1000
+	_ = int16(y)
+	_ = byte(y) // MATCH "conversion of 1000 to byte overflows"
+
+	z := -1
+	_ = uint(z) // MATCH "conversion of -1 to uint overflows"
+}
+
+func fn2(cond bool) {
+	x := 10
+	if cond {
+		x = 1000
+	}
+	_ = int16(x)
+	_ = int8(x) // MATCH "conversion from int to int8 may overflow, as the value can be in the range [10, 1000]"
+
+	y := x + 200
+	_ = int8(y) // MATCH "conversion from int to int8 overflows, as the value is in the range [210, 1200]"
+
+	z := 10
+	if cond {
+		z = 20
+	}
+	_ = int8(z)
+}
+
+func fn3(x int64, y int32) {
+	_ = int32(x)
+	_ = int64(y)
+	_ = int(y)
+	for i := 0; i < 1000; i++ {
+		_ = byte(i)
+	}
+}
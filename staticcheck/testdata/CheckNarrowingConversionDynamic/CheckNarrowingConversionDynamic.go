@@ -0,0 +1,13 @@
+package pkg
+
+func fn(x int64, y int32, b bool) {
+	_ = int32(x) // MATCH "conversion from int64 to int32 may overflow"
+	_ = byte(y)  // MATCH "conversion from int32 to byte may overflow"
+	_ = int64(y)
+
+	z := 10
+	if b {
+		z = 20
+	}
+	_ = int8(z)
+}
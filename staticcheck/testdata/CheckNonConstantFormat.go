@@ -0,0 +1,27 @@
+package pkg
+
+import (
+	"fmt"
+	"log"
+	"os"
+)
+
+const format = "%d items\n"
+
+func fn(s string, n int, l *log.Logger, formats map[bool]string) error {
+	fmt.Printf(formats[n > 1], n)         // MATCH "non-constant format string in call to Printf; its verbs can't be checked against the arguments"
+	fmt.Fprintf(os.Stderr, s+": %d\n", n) // MATCH "non-constant format string in call to Fprintf"
+	l.Printf(s, n)                        // MATCH "non-constant format string in call to Printf"
+	_ = fmt.Sprintf(formats[n > 1], n)    // MATCH "non-constant format string in call to Sprintf"
+
+	fmt.Printf("%d items\n", n)
+	fmt.Printf(format, n)
+	fmt.Printf(format+"%s\n", n, s)
+	log.Printf("%s", s)
+	fmt.Printf(s, []interface{}{n}...)
+
+	//lint:ignore SA1028 the format comes from a trusted translation table
+	fmt.Printf(formats[true], n)
+
+	return fmt.Errorf(s+": %v", n) // MATCH "non-constant format string in call to Errorf"
+}
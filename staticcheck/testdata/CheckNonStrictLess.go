@@ -0,0 +1,30 @@
+package pkg
+
+type byAge []struct{ age int }
+
+func (s byAge) Len() int      { return len(s) }
+func (s byAge) Swap(i, j int) { s[i], s[j] = s[j], s[i] }
+func (s byAge) Less(i, j int) bool {
+	return s[i].age <= s[j].age // MATCH "use < instead"
+}
+
+type byName []string
+
+func (s byName) Len() int           { return len(s) }
+func (s byName) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
+func (s byName) Less(i, j int) bool { return s[i] < s[j] }
+
+type reversed struct{ s []int }
+
+func (r *reversed) Len() int      { return len(r.s) }
+func (r *reversed) Swap(i, j int) { r.s[i], r.s[j] = r.s[j], r.s[i] }
+func (r *reversed) Less(i, j int) bool {
+	if r.s[i] == 0 {
+		return false
+	}
+	return (r.s[i] >= r.s[j]) // MATCH "use > instead"
+}
+
+type notSortable []int
+
+func (s notSortable) Less(i, j int) bool { return s[i] <= s[j] }
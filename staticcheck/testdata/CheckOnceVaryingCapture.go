@@ -0,0 +1,68 @@
+package pkg
+
+import "sync"
+
+type Config struct{}
+
+var once sync.Once
+
+func initialize(Config) {}
+func setup()            {}
+
+func fn1(cfg Config) {
+	once.Do(func() {
+		initialize(cfg) // MATCH "the function passed to once.Do uses cfg, which may differ between calls of fn1, but only the first call runs the function"
+	})
+}
+
+type T struct {
+	cfg  Config
+	once sync.Once
+}
+
+func (t *T) fn2(cfg Config, n int) {
+	t.once.Do(func() {
+		t.cfg = cfg // MATCH "the function passed to t.once.Do uses cfg"
+		initialize(cfg)
+		_ = n // MATCH "uses n"
+	})
+}
+
+func fn3(once *sync.Once, cfg Config) {
+	once.Do(func() {
+		initialize(cfg) // MATCH "the function passed to once.Do uses cfg"
+	})
+}
+
+var defaultConfig Config
+
+func fn4(cfg Config) {
+	once.Do(setup)
+	once.Do(func() {
+		initialize(defaultConfig)
+	})
+
+	// a new Once for every call
+	var local sync.Once
+	local.Do(func() {
+		initialize(cfg)
+	})
+
+	// the parameter is shadowed
+	once.Do(func() {
+		cfg := Config{}
+		initialize(cfg)
+	})
+}
+
+func (t *T) fn5() {
+	t.once.Do(func() {
+		initialize(t.cfg)
+	})
+}
+
+func (t *T) fn6() {
+	once.Do(func() {
+		initialize(t.cfg) // MATCH "the function passed to once.Do uses t"
+	})
+}
@@ -0,0 +1,109 @@
+package pkg
+
+type T struct {
+	xs []int
+}
+
+func fn1(xs []int, m map[string]int, arr [4]int, parr *[4]int, s string, ch chan int, t T) {
+	var out1 []int // MATCH "should preallocate out1 with make([]int, 0, len(xs)) before appending to it in a loop"
+	for _, x := range xs {
+		out1 = append(out1, x*2)
+	}
+
+	out2 := []string{} // MATCH "should preallocate out2 with make([]string, 0, len(m)) before appending to it in a loop"
+	for k := range m {
+		out2 = append(out2, k)
+	}
+
+	var out3 []int // MATCH "should preallocate out3 with make([]int, 0, len(arr)) before appending to it in a loop"
+	for _, x := range arr {
+		out3 = append(out3, x)
+	}
+
+	var out4 []int // MATCH "should preallocate out4 with make([]int, 0, len(parr)) before appending to it in a loop"
+	for _, x := range parr {
+		out4 = append(out4, x)
+	}
+
+	var out5 []int // MATCH "should preallocate out5 with make([]int, 0, len(t.xs)) before appending to it in a loop"
+	for _, x := range t.xs {
+		out5 = append(out5, x)
+	}
+
+	_ = len(out1)
+	_ = out5[0]
+	_, _, _ = out2, out3, out4
+}
+
+func fn3(xs []int) []int {
+	// preallocating would return an empty slice instead of nil when
+	// xs is empty
+	var out []int
+	for _, x := range xs {
+		out = append(out, x)
+	}
+	return out
+}
+
+func fn2(xs []int, s string, ch chan int) {
+	out1 := make([]int, 0, len(xs))
+	for _, x := range xs {
+		out1 = append(out1, x)
+	}
+
+	var out2 []int
+	for _, x := range xs {
+		if x > 0 {
+			out2 = append(out2, x)
+		}
+	}
+
+	var out3 []int
+	for _, x := range xs {
+		if x < 0 {
+			continue
+		}
+		out3 = append(out3, x)
+	}
+
+	var out4 []rune
+	for _, r := range s {
+		out4 = append(out4, r)
+	}
+
+	var out5 []int
+	for x := range ch {
+		out5 = append(out5, x)
+	}
+
+	var out6 []int
+	for _, x := range xs {
+		out6 = append(out6, x, x)
+	}
+
+	var out7 []int
+	for _, x := range xs {
+		out7 = append(out7, x)
+		out7 = append(out7, x)
+	}
+
+	var out8 []int
+	for _, x := range xs {
+		out8 = append(out8, len(out8)+x)
+	}
+
+	var out9 []int
+	for _, x := range get() {
+		out9 = append(out9, x)
+	}
+
+	var out10 []int
+	n := 0
+	for _, x := range xs {
+		out10 = append(out10, x)
+	}
+
+	_, _, _, _, _, _, _, _, _, _, _ = out1, out2, out3, out4, out5, out6, out7, out8, out9, out10, n
+}
+
+func get() []int { return nil }
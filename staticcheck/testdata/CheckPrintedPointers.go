@@ -0,0 +1,30 @@
+package pkg
+
+import (
+	"fmt"
+	"log"
+)
+
+type T struct{ x int }
+
+type S struct{ x int }
+
+func (*S) String() string { return "" }
+
+type List []*T
+
+func (List) String() string { return "" }
+
+func fn(t *T, ts []*T, arr [2]*T, m map[string]*T, ss []*S, l List, ints []*int) {
+	fmt.Println(t)
+	fmt.Printf("%v\n", t)
+	fmt.Println(ts)           // MATCH "printing ts prints the addresses of its elements"
+	fmt.Printf("%+v\n", ts)   // MATCH "printing ts prints the addresses of its elements"
+	fmt.Printf("%d %v", 1, m) // MATCH "printing m prints the addresses of its elements"
+	log.Print(arr)            // MATCH "printing arr prints the addresses of its elements"
+	fmt.Printf("%p\n", ts)
+	fmt.Println(ts[0])
+	fmt.Println(ss)
+	fmt.Println(l)
+	fmt.Println(ints)
+}
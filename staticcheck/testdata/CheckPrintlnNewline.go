@@ -0,0 +1,24 @@
+package pkg
+
+import (
+	"fmt"
+	"log"
+	"os"
+)
+
+func fn(s string, l *log.Logger) {
+	fmt.Println("x\n")                  // MATCH "Println already adds a newline, the one at the end of the string results in an empty line"
+	fmt.Fprintln(os.Stderr, "a", "b\n") // MATCH "Fprintln already adds a newline"
+	_ = fmt.Sprintln("done\n")          // MATCH "Sprintln already adds a newline"
+	log.Println("starting\n")           // MATCH "Println already adds a newline"
+	l.Println("line\n")                 // MATCH "Println already adds a newline"
+	fmt.Println(`raw
+`) // MATCH:15 "Println already adds a newline"
+	fmt.Println("\n") // MATCH "Println already adds a newline"
+
+	fmt.Println(s)
+	fmt.Println("a\nb")
+	fmt.Println("a\n", s)
+	fmt.Print("x\n")
+	fmt.Printf("x\n")
+}
@@ -45,5 +45,23 @@ func fn5() {
 	}
 }
 
+const one = 1
+
+func fn6(x, y int, s string) {
+	if x == 1 {
+	} else if x == 2 {
+	} else if x == 0x1 { // MATCH /condition occurs multiple times/
+	} else if 2 == x { // MATCH /condition occurs multiple times/
+	} else if x != 1 {
+	} else if y == one {
+	} else if y == 1 { // MATCH /condition occurs multiple times/
+	} else if s == "a" {
+	} else if s == "b" {
+	} else if s == ("a") { // MATCH /condition occurs multiple times/
+	} else {
+		println()
+	}
+}
+
 func gen() bool    { return false }
 func gen2() string { return "" }
@@ -0,0 +1,37 @@
+package pkg
+
+import (
+	"database/sql"
+	"log"
+)
+
+func fn1(db *sql.DB) {
+	rows, err := db.Query("SELECT name FROM users")
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var name string
+		rows.Scan(&name)     // MATCH "the error returned by rows.Scan is discarded"
+		_ = rows.Scan(&name) // MATCH "the error returned by rows.Scan is discarded"
+		println(name)
+	}
+}
+
+func fn2(rows *sql.Rows) error {
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return err
+		}
+		println(name)
+	}
+	return rows.Err()
+}
+
+func fn3(rows *sql.Rows) {
+	var name string
+	rows.Next()
+	rows.Scan(&name)
+}
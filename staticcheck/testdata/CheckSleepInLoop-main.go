@@ -0,0 +1,13 @@
+package main
+
+import "time"
+
+func waitForServer(up func() bool) {
+	for !up() {
+		time.Sleep(time.Second)
+	}
+}
+
+func main() {
+	waitForServer(func() bool { return true })
+}
@@ -0,0 +1,40 @@
+package pkg
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+func fn1(ready *int32) {
+	for atomic.LoadInt32(ready) == 0 {
+		time.Sleep(10 * time.Millisecond) // MATCH "time.Sleep in a loop is usually polling for a condition"
+	}
+}
+
+func fn2(done func() bool) {
+	for {
+		if done() {
+			break
+		}
+		time.Sleep(time.Second) // MATCH "time.Sleep in a loop is usually polling for a condition"
+	}
+}
+
+func fn3(done func() bool) {
+	for !done() {
+		for i := 0; i < 3; i++ {
+			time.Sleep(time.Second) // MATCH "time.Sleep in a loop"
+		}
+	}
+}
+
+func fn4() {
+	// a standalone delay
+	time.Sleep(time.Second)
+
+	for i := 0; i < 3; i++ {
+		go func() {
+			time.Sleep(time.Second)
+		}()
+	}
+}
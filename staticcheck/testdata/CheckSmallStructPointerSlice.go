@@ -0,0 +1,53 @@
+package pkg
+
+import "sync"
+
+type small struct{ a, b int32 }
+
+type large struct{ a, b, c, d int64 }
+
+type mutable struct{ a int32 }
+
+func (m *mutable) Set(a int32) { m.a = a }
+
+type locked struct{ mu sync.Mutex }
+
+type T struct {
+	s []*small // MATCH "slice of pointers to small, which is only 8 bytes large; a slice of values would avoid the indirection"
+	l []*large
+	m []*mutable
+	k []*locked
+	v []small
+}
+
+func fn1(s []*small) int { // MATCH "slice of pointers to small, which is only 8 bytes large"
+	n := len(s)
+	for _, e := range s {
+		n += int(e.a)
+	}
+	for i := range s {
+		n += int(s[i].b)
+	}
+	return n
+}
+
+func fn2(l []*large) int64 { return l[0].a }
+
+func fn3(s []*small) { s[0].a = 1 }
+
+func fn4(s []*small) {
+	for _, e := range s {
+		e.b++
+	}
+}
+
+func fn5(s []*small) []*small { return s }
+
+func fn6(s []*small) *small { return s[0] }
+
+func fn7(s []*small) *int32 { return &s[0].a }
+
+func fn8(s []*small, t []*small) int32 { // MATCH "slice of pointers to small"
+	_ = append(t, &small{a: s[0].a})
+	return s[0].a
+}
@@ -0,0 +1,10 @@
+package pkg
+
+type medium struct{ a, b, c, d int64 }
+
+type large struct{ a, b, c, d, e int64 }
+
+type T struct {
+	m []*medium // MATCH "slice of pointers to medium, which is only 32 bytes large"
+	l []*large
+}
@@ -0,0 +1,47 @@
+package pkg
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"testing"
+)
+
+type Size int
+
+func (s Size) String() string { return "size" }
+
+type PtrStringer int
+
+func (s *PtrStringer) String() string { return "ptr" }
+
+type Formatted int
+
+func (Formatted) String() string             { return "" }
+func (Formatted) Format(f fmt.State, c rune) {}
+
+func fn(t *testing.T, l *log.Logger) {
+	var s Size
+	var p PtrStringer
+	var f Formatted
+	err := errors.New("")
+
+	fmt.Printf("%d", s)                   // MATCH "s is formatted with %d, which doesn't use its String method; did you mean %s?"
+	fmt.Sprintf("%s %d", s, s)            // MATCH "s is formatted with %d, which doesn't use its String method; did you mean %s?"
+	fmt.Fprintf(os.Stdout, "%x %f", 1, s) // MATCH "s is formatted with %f, which doesn't use its String method; did you mean %s?"
+	fmt.Printf("%*d %d", 1, 2, s)         // MATCH "s is formatted with %d, which doesn't use its String method; did you mean %s?"
+	fmt.Printf("%d", &p)                  // MATCH "&p is formatted with %d, which doesn't use its String method; did you mean %s?"
+	_ = fmt.Errorf("%d", err)             // MATCH "err is formatted with %d, which doesn't use its Error method; did you mean %s?"
+	l.Printf("%d", s)                     // MATCH "s is formatted with %d, which doesn't use its String method; did you mean %s?"
+	t.Errorf("%d", s)                     // MATCH "s is formatted with %d, which doesn't use its String method; did you mean %s?"
+
+	fmt.Printf("%s %v %q %x", s, s, s, s)
+	fmt.Printf("%02d", s)
+	fmt.Printf("%d", int(s))
+	fmt.Printf("%d", p)
+	fmt.Printf("%d", f)
+	fmt.Printf("%[1]d", s)
+	fmt.Printf("%%d", s)
+	fmt.Println("%d", s)
+}
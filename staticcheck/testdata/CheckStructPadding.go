@@ -0,0 +1,30 @@
+package pkg
+
+type T1 struct { // MATCH "struct T1 occupies 24 bytes but could occupy 16 bytes with its fields in the following order: b, a, c"
+	a bool
+	b int64
+	c bool
+}
+
+type T2 struct {
+	b int64
+	a bool
+	c bool
+}
+
+type T3 struct {
+	a bool
+	_ [7]byte
+	b int64
+	c bool
+}
+
+type T4 struct { // MATCH "struct T4 occupies 16 bytes but could occupy 12 bytes with its fields in the following order: s, a, c"
+	a bool
+	s struct{ x, y int32 }
+	c bool
+}
+
+type T5 struct {
+	a, b, c bool
+}
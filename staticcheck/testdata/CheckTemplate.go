@@ -19,3 +19,14 @@ func fn() {
 	t2.Parse(tmpl1)
 	tt.New("").Delims("[[", "]]").Parse("{{abc-}}")
 }
+
+func fn2() {
+	tt.New("").Parse("{{.Name")                         // MATCH "unclosed action"
+	th.New("").Parse("{{$x}}")                          // MATCH /undefined variable/
+	tt.New("").Parse("{{range}}{{end}}")                // MATCH "missing value for range"
+	tt.New("").Funcs(nil).Parse("{{if .X}}")            // MATCH "unexpected EOF"
+	th.New("").Option("missingkey=error").Parse("{{.A") // MATCH "unclosed action"
+	tt.New("").Funcs(nil).Parse("{{.A | fn}}")
+	tt.Must(tt.New("").Parse("{{range .X}}{{.}}{{end}}"))
+	th.Must(th.New("").Parse(`<a href="{{.URL}}">{{.Name}}</a>`))
+}
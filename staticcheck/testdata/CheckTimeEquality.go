@@ -0,0 +1,15 @@
+package pkg
+
+import "time"
+
+func fn(a, b time.Time, p *time.Time, ts []time.Time) bool {
+	if a == b { // MATCH "comparing time.Time values with == also compares their locations and monotonic clock readings; use the Equal method instead"
+		return true
+	}
+	if a != time.Now() { // MATCH "comparing time.Time values with != also compares"
+		return false
+	}
+	_ = *p == ts[0] // MATCH "comparing time.Time values with =="
+
+	return a.Equal(b) || !a.Equal(b) || a == time.Time{} || a.IsZero() || a.Unix() == b.Unix()
+}
@@ -0,0 +1,82 @@
+package pkg
+
+import "sync"
+
+type Weighted struct{}
+
+func (*Weighted) Acquire(n int64) error { return nil }
+func (*Weighted) Release(n int64)       {}
+
+func work(int) {}
+
+func fn1(items []int, m map[string]int, ch chan int) {
+	for _, item := range items {
+		go work(item) // MATCH "starting a goroutine for every element of items doesn't bound the number of concurrent goroutines"
+	}
+
+	for _, v := range m {
+		v := v
+		go func() { // MATCH "starting a goroutine for every element of m"
+			work(v)
+		}()
+	}
+
+	for v := range ch {
+		go work(v) // MATCH "starting a goroutine for every element of ch"
+	}
+
+	var wg sync.WaitGroup
+	for _, item := range items {
+		wg.Add(1)
+		go func(item int) { // MATCH "starting a goroutine for every element of items"
+			defer wg.Done()
+			work(item)
+		}(item)
+	}
+	wg.Wait()
+}
+
+func fn2(items []int, arr [4]int) {
+	sem := make(chan struct{}, 10)
+	for _, item := range items {
+		sem <- struct{}{}
+		go func(item int) {
+			defer func() { <-sem }()
+			work(item)
+		}(item)
+	}
+
+	w := &Weighted{}
+	for _, item := range items {
+		w.Acquire(1)
+		go func(item int) {
+			defer w.Release(1)
+			work(item)
+		}(item)
+	}
+
+	// waiting for batches of goroutines
+	var wg sync.WaitGroup
+	for i, item := range items {
+		wg.Add(1)
+		go func(item int) {
+			defer wg.Done()
+			work(item)
+		}(item)
+		if i%10 == 0 {
+			wg.Wait()
+		}
+	}
+
+	// arrays have a fixed size
+	for _, item := range arr {
+		go work(item)
+	}
+
+	// the goroutine isn't started for every element
+	for _, item := range items {
+		if item == 0 {
+			go work(item)
+		}
+	}
+}
@@ -0,0 +1,28 @@
+package pkg
+
+func fn1(dst, src []int, s string) {
+	copy(dst, src)     // MATCH "the number of elements copied is ignored"
+	copy(dst[1:], src) // MATCH "the number of elements copied is ignored"
+
+	b := make([]byte, 4)
+	copy(b, s) // MATCH "the number of elements copied is ignored"
+}
+
+func fn2(src []int, s string) int {
+	dst := make([]int, len(src))
+	copy(dst, src)
+
+	b := make([]byte, len(s))
+	copy(b, s)
+
+	c := make([]byte, 8)
+	copy(c, "abc")
+	copy(c[2:], "abcdef")
+
+	var arr [4]int
+	copy(arr[:], src[:4])
+	copy(arr[1:], src[2:4])
+
+	n := copy(arr[:], src)
+	return n
+}
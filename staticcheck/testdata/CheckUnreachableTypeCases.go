@@ -0,0 +1,42 @@
+package pkg
+
+import (
+	"io"
+	"os"
+)
+
+type T struct{}
+
+func (T) Read([]byte) (int, error) { return 0, nil }
+
+func fn(v interface{}) {
+	switch v.(type) {
+	case io.Reader:
+	case *os.File: // MATCH "case *os.File is unreachable because it is matched by the earlier case io.Reader"
+	case T: // MATCH "case T is unreachable because it is matched by the earlier case io.Reader"
+	case *T: // MATCH "case *T is unreachable"
+	case io.ReadCloser: // MATCH "case io.ReadCloser is unreachable"
+	case io.Writer:
+	case nil:
+	}
+
+	switch v.(type) {
+	case io.Reader, io.ReadCloser:
+	case *os.File, int: // MATCH "case *os.File is unreachable"
+	}
+
+	switch v.(type) {
+	case *os.File:
+	case io.ReadWriter:
+	case io.Reader:
+	case int:
+	case interface{}:
+	case string: // MATCH "case string is unreachable because it is matched by the earlier case interface{}"
+	}
+
+	switch x := v.(type) {
+	case error:
+		_ = x
+	case *os.PathError: // MATCH "case *os.PathError is unreachable"
+	}
+}
@@ -0,0 +1,26 @@
+package pkg
+
+func fnErr() error { return nil }
+
+func fnIntErr() (int, error) { return 0, nil }
+
+func fn1() error {
+	err := fnErr() // MATCH "the error assigned to err here is never checked"
+	err = fnErr()
+	return err
+}
+
+func fn2() (int, error) {
+	_, err := fnIntErr() // MATCH "the error assigned to err here is never checked"
+	n, err := fnIntErr()
+	return n, err
+}
+
+func fn3() error {
+	err := fnErr()
+	if err != nil {
+		return err
+	}
+	err = fnErr()
+	return err
+}
@@ -12,9 +12,9 @@ func fn() {
 	_ = fmt.Sprintf(fn2()) // MATCH /should use print-style function/
 	log.Printf(fn2())      // MATCH /should use print-style function/
 	fmt.Printf(s)          // MATCH /should use print-style function/
-	fmt.Printf(s, "")
+	fmt.Printf(s, "")      // MATCH "non-constant format string in call to Printf"
 
-	fmt.Printf(fn2(), "")
+	fmt.Printf(fn2(), "") // MATCH "non-constant format string in call to Printf"
 	fmt.Printf("")
 	fmt.Printf("", "")
 }
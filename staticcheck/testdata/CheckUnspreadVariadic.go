@@ -0,0 +1,25 @@
+package pkg
+
+import (
+	"fmt"
+	"log"
+)
+
+func logf(format string, args ...interface{}) {}
+
+func wrap(args ...interface{}) {
+	fmt.Println(args) // MATCH "args is passed as a single argument to the variadic fmt.Println; did you mean args...?"
+	fmt.Println(args...)
+	fmt.Println("args:", args)
+	log.Print(args)  // MATCH "did you mean args...?"
+	logf("%v", args) // MATCH "to the variadic logf; did you mean args...?"
+	logf("%v", args...)
+	_ = append(args, args)
+}
+
+func fn(names []string, vals []interface{}) {
+	fmt.Println(names)
+	fmt.Println(vals) // MATCH "did you mean vals...?"
+	fmt.Println(vals[0])
+	fmt.Println([]interface{}{1, 2})
+}
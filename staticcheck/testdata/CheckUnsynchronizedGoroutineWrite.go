@@ -0,0 +1,52 @@
+package pkg
+
+import "sync"
+
+func fn1() int {
+	x := 0
+	go func() {
+		x = 1
+	}()
+	return x // MATCH "x is read while the goroutine started on line 7 may still be writing it"
+}
+
+func fn2(n int) int {
+	go func() {
+		n++
+	}()
+	n = 2
+	return n // MATCH "n is read while the goroutine"
+}
+
+func fn3() int {
+	var wg sync.WaitGroup
+	x := 0
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		x = 1
+	}()
+	wg.Wait()
+	return x
+}
+
+func fn4() int {
+	done := make(chan struct{})
+	x := 0
+	go func() {
+		x = 1
+		close(done)
+	}()
+	<-done
+	return x
+}
+
+func fn5() int {
+	x := 0
+	y := x
+	go func() {
+		y := 2
+		_ = y
+	}()
+	return y
+}
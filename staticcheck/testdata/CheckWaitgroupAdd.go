@@ -32,3 +32,29 @@ func fn() {
 func fn2(wg sync.WaitGroup) {
 	wg.Add(1)
 }
+
+type T struct {
+	wg sync.WaitGroup
+}
+
+func fn3(t *T, wg *sync.WaitGroup) {
+	go func() {
+		t.wg.Add(1) // MATCH "should call t.wg.Add(1) before starting"
+		t.wg.Done()
+	}()
+
+	go func() {
+		wg.Add(1) // MATCH "should call wg.Add(1) before starting"
+		wg.Done()
+	}()
+
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+		}()
+	}
+
+	wg.Wait()
+	t.wg.Wait()
+}
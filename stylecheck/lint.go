@@ -6,23 +6,38 @@ import (
 	"go/constant"
 	"go/token"
 	"go/types"
+	"regexp"
+	"strconv"
 	"strings"
 	"unicode"
 	"unicode/utf8"
 
 	"honnef.co/go/tools/lint"
 	. "honnef.co/go/tools/lint/lintdsl"
-	"honnef.co/go/tools/ssa"
 
 	"golang.org/x/tools/go/types/typeutil"
 )
 
 type Checker struct {
 	CheckGenerated bool
+	// FloatZero causes ST1013 to also flag comparisons of
+	// floating-point values with 0.
+	FloatZero bool
+	// PanicInInit causes ST1014 to also flag panics in init
+	// functions, which usually guard invariants of the package.
+	PanicInInit bool
+	// UnwrappedErrorStatements is the number of statements that
+	// have to precede a return in a function for ST1017 to flag
+	// returning an error without adding context.
+	UnwrappedErrorStatements int
+	// NakedReturnLines is the number of lines that a function with
+	// named results has to exceed for ST1021 to flag its naked
+	// returns.
+	NakedReturnLines int
 }
 
 func NewChecker() *Checker {
-	return &Checker{}
+	return &Checker{UnwrappedErrorStatements: 10, NakedReturnLines: 30}
 }
 
 func (*Checker) Name() string   { return "stylecheck" }
@@ -59,6 +74,30 @@ func (c *Checker) Funcs() map[string]lint.Func {
 		"ST1010": c.CheckContextFirstArg,
 		"ST1011": c.CheckTimeNames,
 		"ST1012": c.CheckErrorVarNames,
+		"ST1013": c.CheckFloatEquality,
+		"ST1014": c.CheckLibraryPanic,
+		"ST1015": c.CheckEmbeddedMutex,
+		"ST1016": c.CheckPointerToInterface,
+		"ST1017": c.CheckUnwrappedErrorReturn,
+		"ST1018": c.CheckEagerDeferArgs,
+		"ST1019": c.CheckPlusBuildConstraints,
+		"ST1020": c.CheckMissingInterfaceAssertion,
+		"ST1021": c.CheckNakedReturns,
+	}
+}
+
+func (c *Checker) Info() map[string]lint.CheckInfo {
+	return map[string]lint.CheckInfo{
+		"ST1005": {Fixable: true},
+		"ST1013": {OptIn: true, Since: "2019.2"},
+		"ST1014": {OptIn: true, Since: "2019.2"},
+		"ST1015": {Since: "2019.2"},
+		"ST1016": {Since: "2019.2"},
+		"ST1017": {OptIn: true, Since: "2019.2"},
+		"ST1018": {OptIn: true, Since: "2019.2"},
+		"ST1019": {Fixable: true, Since: "2019.2"},
+		"ST1020": {OptIn: true, Since: "2019.2"},
+		"ST1021": {OptIn: true, Since: "2019.2"},
 	}
 }
 
@@ -318,75 +357,90 @@ fnLoop:
 }
 
 func (c *Checker) CheckErrorStrings(j *lint.Job) {
-	for _, fn := range j.Program.InitialFunctions {
-		if IsInTest(j, fn) {
-			// We don't care about malformed error messages in tests;
-			// they're usually for direct human consumption, not part
-			// of an API
-			continue
+	fn := func(node ast.Node) bool {
+		call, ok := node.(*ast.CallExpr)
+		if !ok || len(call.Args) == 0 {
+			return true
 		}
-		for _, block := range fn.Blocks {
-			for _, ins := range block.Instrs {
-				call, ok := ins.(*ssa.Call)
-				if !ok {
-					continue
-				}
-				if !IsCallTo(call.Common(), "errors.New") && !IsCallTo(call.Common(), "fmt.Errorf") {
-					continue
-				}
-
-				k, ok := call.Common().Args[0].(*ssa.Const)
-				if !ok {
-					continue
-				}
+		if !IsCallToAnyAST(j, call, "errors.New", "fmt.Errorf") {
+			return true
+		}
+		s, ok := ExprToString(j, call.Args[0])
+		if !ok || len(s) == 0 {
+			return true
+		}
+		// Fixes can only be offered if the error string is a
+		// literal, not a named constant.
+		lit, _ := call.Args[0].(*ast.BasicLit)
 
-				s := constant.StringVal(k.Value)
-				if len(s) == 0 {
-					continue
+		switch s[len(s)-1] {
+		case '.', ':', '!', '\n':
+			p := j.Errorf(call, "error strings should not end with punctuation or a newline")
+			if lit != nil {
+				suffix := s[len(strings.TrimRight(s, ".:!\n")):]
+				if lit.Value[0] != '`' {
+					suffix = strconv.Quote(suffix)
+					suffix = suffix[1 : len(suffix)-1]
 				}
-				switch s[len(s)-1] {
-				case '.', ':', '!', '\n':
-					j.Errorf(call, "error strings should not end with punctuation or a newline")
-				}
-				idx := strings.IndexByte(s, ' ')
-				if idx == -1 {
-					// single word error message, probably not a real
-					// error but something used in tests or during
-					// debugging
-					continue
-				}
-				word := s[:idx]
-				first, _ := utf8.DecodeRuneInString(word)
-				if !unicode.IsUpper(first) {
-					continue
-				}
-				many := false
-				for _, c := range []rune(word)[1:] {
-					if unicode.IsUpper(c) {
-						many = true
-						break
-					}
-				}
-				if !many {
-					// First word in error starts with a capital
-					// letter, and the word doesn't contain any other
-					// capitals, making it unlikely to be an
-					// initialism or multi-word function name.
-					//
-					// It could still be a single-word function name
-					// or a proper noun, though.
-					//
-					// TODO(dh): example from the stdlib that we incorrectly flag:
-					// 	func (w *pooledFlateWriter) Write(p []byte) (n int, err error) {
-					// 		...
-					// 		return 0, errors.New("Write after Close")
-					// 		...
-					// 	}
-
-					j.Errorf(call, "error strings should not be capitalized")
+				if strings.HasSuffix(lit.Value[:len(lit.Value)-1], suffix) {
+					end := lit.End() - 1
+					p.Fixes = []lint.SuggestedFix{{
+						Message: "remove trailing punctuation",
+						Edits:   []lint.TextEdit{j.Edit(end-token.Pos(len(suffix)), end, "")},
+					}}
 				}
 			}
 		}
+		idx := strings.IndexByte(s, ' ')
+		if idx == -1 {
+			// single word error message, probably not a real
+			// error but something used in tests or during
+			// debugging
+			return true
+		}
+		word := s[:idx]
+		first, size := utf8.DecodeRuneInString(word)
+		if !unicode.IsUpper(first) {
+			return true
+		}
+		for _, c := range word[size:] {
+			if unicode.IsUpper(c) {
+				// The word contains more capitals, making it likely
+				// to be an initialism or multi-word function name.
+				return true
+			}
+		}
+		// First word in error starts with a capital letter, and the
+		// word doesn't contain any other capitals, making it unlikely
+		// to be an initialism or multi-word function name.
+		//
+		// It could still be a single-word function name or a proper
+		// noun, though.
+		//
+		// TODO(dh): example from the stdlib that we incorrectly flag:
+		// 	func (w *pooledFlateWriter) Write(p []byte) (n int, err error) {
+		// 		...
+		// 		return 0, errors.New("Write after Close")
+		// 		...
+		// 	}
+		p := j.Errorf(call, "error strings should not be capitalized")
+		if lit != nil && strings.HasPrefix(lit.Value[1:], word[:size]) {
+			start := lit.Pos() + 1
+			p.Fixes = []lint.SuggestedFix{{
+				Message: "lowercase first letter",
+				Edits:   []lint.TextEdit{j.Edit(start, start+token.Pos(size), string(unicode.ToLower(first)))},
+			}}
+		}
+		return true
+	}
+	for _, f := range j.Program.Files {
+		if IsInTest(j, f) {
+			// We don't care about malformed error messages in tests;
+			// they're usually for direct human consumption, not part
+			// of an API
+			continue
+		}
+		ast.Inspect(f, fn)
 	}
 }
 
@@ -459,3 +513,576 @@ func (c *Checker) CheckErrorVarNames(j *lint.Job) {
 		}
 	}
 }
+
+func (c *Checker) CheckEmbeddedMutex(j *lint.Job) {
+	mutexes := []string{"sync.Mutex", "*sync.Mutex", "sync.RWMutex", "*sync.RWMutex"}
+	for _, f := range j.Program.Files {
+		for _, decl := range f.Decls {
+			gen, ok := decl.(*ast.GenDecl)
+			if !ok || gen.Tok != token.TYPE {
+				continue
+			}
+			for _, spec := range gen.Specs {
+				spec := spec.(*ast.TypeSpec)
+				if !spec.Name.IsExported() {
+					continue
+				}
+				st, ok := spec.Type.(*ast.StructType)
+				if !ok {
+					continue
+				}
+				for _, field := range st.Fields.List {
+					if len(field.Names) != 0 {
+						continue
+					}
+					T := TypeOf(j, field.Type)
+					for _, name := range mutexes {
+						if IsType(T, name) {
+							j.Errorf(field, "exported type %s embeds %s, which makes its Lock and Unlock methods part of the API of %s; use a named field instead",
+								spec.Name.Name, name, spec.Name.Name)
+							break
+						}
+					}
+				}
+			}
+		}
+	}
+}
+
+// callsRecover reports whether body calls recover, not counting
+// calls in function literals.
+func callsRecover(j *lint.Job, body *ast.BlockStmt) bool {
+	found := false
+	ast.Inspect(body, func(node ast.Node) bool {
+		switch node := node.(type) {
+		case *ast.FuncLit:
+			return false
+		case *ast.CallExpr:
+			if ident, ok := node.Fun.(*ast.Ident); ok && ident.Name == "recover" {
+				if _, ok := ObjectOf(j, ident).(*types.Builtin); ok {
+					found = true
+				}
+			}
+		}
+		return !found
+	})
+	return found
+}
+
+func (c *Checker) CheckPointerToInterface(j *lint.Job) {
+	// Functions that are deferred to turn panics into errors
+	// idiomatically set the caller's error through an *error
+	// parameter, as in defer recoverErr(&err).
+	recovers := map[*ast.FuncType]bool{}
+	check := func(fields *ast.FieldList, allowError bool) {
+		if fields == nil {
+			return
+		}
+		for _, field := range fields.List {
+			star, ok := field.Type.(*ast.StarExpr)
+			if !ok {
+				continue
+			}
+			T := TypeOf(j, star.X)
+			if _, ok := T.Underlying().(*types.Interface); !ok {
+				continue
+			}
+			if allowError && types.Identical(T, types.Universe.Lookup("error").Type()) {
+				continue
+			}
+			j.Errorf(field.Type, "%s is a pointer to an interface; interface values can already hold pointers, use %s instead",
+				Render(j, field.Type), Render(j, star.X))
+		}
+	}
+	fn := func(node ast.Node) bool {
+		switch node := node.(type) {
+		case *ast.FuncDecl:
+			if node.Body != nil && callsRecover(j, node.Body) {
+				recovers[node.Type] = true
+			}
+		case *ast.FuncLit:
+			if callsRecover(j, node.Body) {
+				recovers[node.Type] = true
+			}
+		case *ast.FuncType:
+			check(node.Params, recovers[node])
+			check(node.Results, false)
+		case *ast.StructType:
+			check(node.Fields, false)
+		}
+		return true
+	}
+	for _, f := range c.filterGenerated(j.Program.Files) {
+		ast.Inspect(f, fn)
+	}
+}
+
+func isFloat(T types.Type) bool {
+	basic, ok := T.Underlying().(*types.Basic)
+	return ok && basic.Info()&types.IsFloat != 0
+}
+
+func isConstZero(j *lint.Job, expr ast.Expr) bool {
+	val := j.Program.Info.Types[expr].Value
+	return val != nil && constant.Sign(val) == 0
+}
+
+func (c *Checker) CheckFloatEquality(j *lint.Job) {
+	fn := func(node ast.Node) bool {
+		expr, ok := node.(*ast.BinaryExpr)
+		if !ok || (expr.Op != token.EQL && expr.Op != token.NEQ) {
+			return true
+		}
+		if !isFloat(TypeOf(j, expr.X)) || !isFloat(TypeOf(j, expr.Y)) {
+			return true
+		}
+		if !c.FloatZero && (isConstZero(j, expr.X) || isConstZero(j, expr.Y)) {
+			// comparing with zero is frequently used to check for
+			// unset values, which is exact
+			return true
+		}
+		j.Errorf(expr, "floating-point values should not be compared with %s; consider comparing their difference against an epsilon", expr.Op)
+		return true
+	}
+	for _, f := range c.filterGenerated(j.Program.Files) {
+		ast.Inspect(f, fn)
+	}
+}
+
+func (c *Checker) CheckLibraryPanic(j *lint.Job) {
+	isError := func(arg ast.Expr) bool {
+		if IsCallToAnyAST(j, arg, "fmt.Errorf", "errors.New") {
+			return true
+		}
+		return IsType(TypeOf(j, arg).Underlying(), "string")
+	}
+	fn := func(node ast.Node) bool {
+		call, ok := node.(*ast.CallExpr)
+		if !ok || len(call.Args) != 1 || !IsIdent(call.Fun, "panic") {
+			return true
+		}
+		if _, ok := ObjectOf(j, call.Fun.(*ast.Ident)).(*types.Builtin); !ok {
+			return true
+		}
+		if isError(call.Args[0]) {
+			j.Errorf(call, "library code should return errors instead of panicking")
+		}
+		return true
+	}
+	for _, f := range c.filterGenerated(j.Program.Files) {
+		if IsInTest(j, f) || IsInMain(j, f) {
+			continue
+		}
+		for _, decl := range f.Decls {
+			fdecl, ok := decl.(*ast.FuncDecl)
+			if !ok || fdecl.Body == nil {
+				continue
+			}
+			name := fdecl.Name.Name
+			if strings.HasPrefix(name, "Must") {
+				// Must functions panic by convention
+				continue
+			}
+			if name == "init" && fdecl.Recv == nil && !c.PanicInInit {
+				continue
+			}
+			ast.Inspect(fdecl.Body, fn)
+		}
+	}
+}
+
+func (c *Checker) CheckUnwrappedErrorReturn(j *lint.Job) {
+	// In long functions, an error returned as is doesn't tell which
+	// of the many things the function does failed. We only flag
+	// errors that were returned by calls, as opposed to errors the
+	// function created itself, and count all statements preceding
+	// the return, including those in nested blocks.
+	fn := func(decl *ast.FuncDecl) {
+		fromCall := map[types.Object]bool{}
+		ast.Inspect(decl.Body, func(node ast.Node) bool {
+			assign, ok := node.(*ast.AssignStmt)
+			if !ok {
+				return true
+			}
+			for i, lhs := range assign.Lhs {
+				ident, ok := lhs.(*ast.Ident)
+				if !ok {
+					continue
+				}
+				var rhs ast.Expr
+				if len(assign.Rhs) == 1 {
+					rhs = assign.Rhs[0]
+				} else if i < len(assign.Rhs) {
+					rhs = assign.Rhs[i]
+				}
+				if _, ok := rhs.(*ast.CallExpr); ok && !IsCallToAnyAST(j, rhs, "errors.New", "fmt.Errorf") {
+					fromCall[ObjectOf(j, ident)] = true
+				}
+			}
+			return true
+		})
+
+		stmts := 0
+		ast.Inspect(decl.Body, func(node ast.Node) bool {
+			if _, ok := node.(*ast.FuncLit); ok {
+				return false
+			}
+			stmt, ok := node.(ast.Stmt)
+			if !ok {
+				return true
+			}
+			if _, ok := stmt.(*ast.BlockStmt); ok {
+				return true
+			}
+			preceding := stmts
+			stmts++
+			ret, ok := stmt.(*ast.ReturnStmt)
+			if !ok || preceding <= c.UnwrappedErrorStatements || len(ret.Results) == 0 {
+				return true
+			}
+			ident, ok := ret.Results[len(ret.Results)-1].(*ast.Ident)
+			if !ok || !IsType(TypeOf(j, ident), "error") || !fromCall[ObjectOf(j, ident)] {
+				return true
+			}
+			j.Errorf(ident, "%s is returned without adding context, in a function with more than %d statements; consider wrapping it, e.g. with fmt.Errorf(\"...: %%w\", %s)",
+				ident.Name, c.UnwrappedErrorStatements, ident.Name)
+			return true
+		})
+	}
+	for _, f := range c.filterGenerated(j.Program.Files) {
+		if IsInTest(j, f) {
+			continue
+		}
+		for _, decl := range f.Decls {
+			if decl, ok := decl.(*ast.FuncDecl); ok && decl.Body != nil {
+				fn(decl)
+			}
+		}
+	}
+}
+
+func (c *Checker) CheckEagerDeferArgs(j *lint.Job) {
+	// The arguments of a deferred call are evaluated when the defer
+	// statement executes, which is easy to forget when they are
+	// calls themselves, as in defer log.Print(time.Since(start)).
+	// Conversions and builtins are cheap and seldom meant to be
+	// lazy, so we only look inside them. Evaluating arguments early
+	// is often intended, which makes this a heuristic, and problems
+	// are reported with a confidence of 0.5, so that -min-confidence
+	// can hide them.
+	isCheap := func(call *ast.CallExpr) bool {
+		if j.Program.Info.Types[call.Fun].IsType() {
+			return true
+		}
+		if ident, ok := call.Fun.(*ast.Ident); ok {
+			if _, ok := ObjectOf(j, ident).(*types.Builtin); ok {
+				return true
+			}
+		}
+		return false
+	}
+	fn := func(node ast.Node) bool {
+		stmt, ok := node.(*ast.DeferStmt)
+		if !ok {
+			return true
+		}
+		for _, arg := range stmt.Call.Args {
+			ast.Inspect(arg, func(node ast.Node) bool {
+				switch node := node.(type) {
+				case *ast.FuncLit:
+					return false
+				case *ast.CallExpr:
+					if isCheap(node) {
+						return true
+					}
+					p := j.Errorf(node, "%s is evaluated when the defer statement executes, not when the deferred call runs; wrap the call in a function literal if it should be evaluated later",
+						Render(j, node))
+					p.Confidence = 0.5
+					return false
+				}
+				return true
+			})
+		}
+		return true
+	}
+	for _, f := range c.filterGenerated(j.Program.Files) {
+		ast.Inspect(f, fn)
+	}
+}
+
+// buildExpr is a build constraint in the //go:build syntax, together
+// with its top-level operator, if any, which decides whether it has
+// to be parenthesized when it is combined with other constraints.
+type buildExpr struct {
+	s  string
+	op string
+}
+
+func joinBuildExprs(op string, x, y buildExpr) buildExpr {
+	arg := func(e buildExpr) string {
+		if e.op != "" && e.op != op {
+			return "(" + e.s + ")"
+		}
+		return e.s
+	}
+	return buildExpr{arg(x) + " " + op + " " + arg(y), op}
+}
+
+func isGoBuild(text string) bool {
+	if !strings.HasPrefix(text, "//go:build") {
+		return false
+	}
+	rest := text[len("//go:build"):]
+	return rest == "" || rest[0] == ' ' || rest[0] == '\t'
+}
+
+func isBuildTag(tag string) bool {
+	if tag == "" {
+		return false
+	}
+	for _, r := range tag {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '_' && r != '.' {
+			return false
+		}
+	}
+	return true
+}
+
+// parsePlusBuild parses a // +build line into the equivalent
+// //go:build constraint. Space-separated options are alternatives,
+// and comma-separated terms of an option all have to be satisfied.
+// It returns false if text isn't a // +build line or if it is
+// malformed.
+func parsePlusBuild(text string) (buildExpr, bool) {
+	if !strings.HasPrefix(text, "//") {
+		return buildExpr{}, false
+	}
+	text = strings.TrimSpace(text[len("//"):])
+	if !strings.HasPrefix(text, "+build") {
+		return buildExpr{}, false
+	}
+	text = text[len("+build"):]
+	if text != "" && text[0] != ' ' && text[0] != '\t' {
+		return buildExpr{}, false
+	}
+	var x buildExpr
+	for _, option := range strings.Fields(text) {
+		var y buildExpr
+		for _, term := range strings.Split(option, ",") {
+			if !isBuildTag(strings.TrimPrefix(term, "!")) {
+				return buildExpr{}, false
+			}
+			z := buildExpr{s: term}
+			if y.s == "" {
+				y = z
+			} else {
+				y = joinBuildExprs("&&", y, z)
+			}
+		}
+		if x.s == "" {
+			x = y
+		} else {
+			x = joinBuildExprs("||", x, y)
+		}
+	}
+	return x, x.s != ""
+}
+
+func (c *Checker) CheckPlusBuildConstraints(j *lint.Job) {
+	// Files that already have a //go:build line are left to gofmt,
+	// which keeps the // +build lines in sync with it. All // +build
+	// lines of a file have to be satisfied, so their expressions are
+	// joined with &&. The lines are parsed by hand instead of with
+	// go/build/constraint, which needs Go 1.16.
+	fn := func(f *ast.File) {
+		cutoff := f.Package
+		if f.Doc != nil {
+			cutoff = f.Doc.Pos()
+		}
+		var first *ast.Comment
+		var expr buildExpr
+		for _, cg := range f.Comments {
+			if cg.Pos() >= cutoff {
+				break
+			}
+			for _, cmt := range cg.List {
+				if isGoBuild(cmt.Text) {
+					return
+				}
+				x, ok := parsePlusBuild(cmt.Text)
+				if !ok {
+					continue
+				}
+				if first == nil {
+					first = cmt
+					expr = x
+				} else {
+					expr = joinBuildExprs("&&", expr, x)
+				}
+			}
+		}
+		if first == nil {
+			return
+		}
+		p := j.Errorf(f, "the // +build syntax for build constraints is deprecated, add //go:build %s", expr.s)
+		p.Fixes = []lint.SuggestedFix{{
+			Message: "add //go:build line",
+			Edits:   []lint.TextEdit{j.Edit(first.Pos(), first.Pos(), "//go:build "+expr.s+"\n")},
+		}}
+	}
+	for _, f := range c.filterGenerated(j.Program.Files) {
+		fn(f)
+	}
+}
+
+var implementsDocRe = regexp.MustCompile(`(?i:implements|satisfies)\s+(?:the\s+)?([A-Za-z_]\w*(?:\.[A-Za-z_]\w*)?)`)
+
+func (c *Checker) CheckMissingInterfaceAssertion(j *lint.Job) {
+	// A type whose documentation claims that it implements an
+	// interface should guard that claim with an assertion such as
+	// var _ io.Reader = (*T)(nil), so that it can't silently stop
+	// being true. Assertions anywhere in the package count, including
+	// in tests.
+	type assertion struct {
+		iface types.Type
+		typ   *types.TypeName
+	}
+	for _, pkg := range j.Program.Packages {
+		var assertions []assertion
+		for _, f := range pkg.Info.Files {
+			for _, decl := range f.Decls {
+				gen, ok := decl.(*ast.GenDecl)
+				if !ok || gen.Tok != token.VAR {
+					continue
+				}
+				for _, spec := range gen.Specs {
+					vspec := spec.(*ast.ValueSpec)
+					if vspec.Type == nil {
+						continue
+					}
+					for i, name := range vspec.Names {
+						if name.Name != "_" || i >= len(vspec.Values) {
+							continue
+						}
+						named, ok := Dereference(pkg.Info.TypeOf(vspec.Values[i])).(*types.Named)
+						if ok {
+							assertions = append(assertions, assertion{pkg.Info.TypeOf(vspec.Type), named.Obj()})
+						}
+					}
+				}
+			}
+		}
+		asserted := func(iface types.Type, typ *types.TypeName) bool {
+			for _, a := range assertions {
+				if a.typ == typ && types.Identical(a.iface, iface) {
+					return true
+				}
+			}
+			return false
+		}
+
+		for _, f := range c.filterGenerated(pkg.Info.Files) {
+			if IsInTest(j, f) {
+				continue
+			}
+			// lookup resolves an interface name as written in the
+			// documentation, possibly qualified by a package name
+			// imported in the file.
+			lookup := func(name string) types.Object {
+				dot := strings.IndexByte(name, '.')
+				if dot == -1 {
+					if obj := pkg.Info.Pkg.Scope().Lookup(name); obj != nil {
+						return obj
+					}
+					return types.Universe.Lookup(name)
+				}
+				for _, imp := range f.Imports {
+					var obj types.Object
+					if imp.Name != nil {
+						obj = pkg.Info.Defs[imp.Name]
+					} else {
+						obj = pkg.Info.Implicits[imp]
+					}
+					if pn, ok := obj.(*types.PkgName); ok && pn.Name() == name[:dot] {
+						return pn.Imported().Scope().Lookup(name[dot+1:])
+					}
+				}
+				return nil
+			}
+			for _, decl := range f.Decls {
+				gen, ok := decl.(*ast.GenDecl)
+				if !ok || gen.Tok != token.TYPE {
+					continue
+				}
+				for _, spec := range gen.Specs {
+					tspec := spec.(*ast.TypeSpec)
+					doc := tspec.Doc
+					if doc == nil && len(gen.Specs) == 1 {
+						doc = gen.Doc
+					}
+					if doc == nil {
+						continue
+					}
+					tn, ok := pkg.Info.Defs[tspec.Name].(*types.TypeName)
+					if !ok {
+						continue
+					}
+					for _, m := range implementsDocRe.FindAllStringSubmatch(doc.Text(), -1) {
+						obj, ok := lookup(m[1]).(*types.TypeName)
+						if !ok {
+							continue
+						}
+						iface, ok := obj.Type().Underlying().(*types.Interface)
+						if !ok || !types.Implements(types.NewPointer(tn.Type()), iface) {
+							continue
+						}
+						if asserted(obj.Type(), tn) {
+							continue
+						}
+						j.Errorf(tspec.Name, "%s is documented to implement %s; consider guarding that with var _ %s = (*%s)(nil)",
+							tn.Name(), m[1], m[1], tn.Name())
+					}
+				}
+			}
+		}
+	}
+}
+
+func (c *Checker) CheckNakedReturns(j *lint.Job) {
+	fset := j.Program.Prog.Fset
+	fn := func(node ast.Node) bool {
+		var typ *ast.FuncType
+		var body *ast.BlockStmt
+		switch node := node.(type) {
+		case *ast.FuncDecl:
+			typ, body = node.Type, node.Body
+		case *ast.FuncLit:
+			typ, body = node.Type, node.Body
+		default:
+			return true
+		}
+		if body == nil || typ.Results == nil || len(typ.Results.List) == 0 || len(typ.Results.List[0].Names) == 0 {
+			return true
+		}
+		lines := fset.Position(body.Rbrace).Line - fset.Position(typ.Pos()).Line + 1
+		if lines <= c.NakedReturnLines {
+			return true
+		}
+		ast.Inspect(body, func(node ast.Node) bool {
+			switch node := node.(type) {
+			case *ast.FuncLit:
+				// function literals are checked on their own
+				return false
+			case *ast.ReturnStmt:
+				if len(node.Results) == 0 {
+					j.Errorf(node, "naked return in a function that is %d lines long; consider listing the returned values explicitly", lines)
+				}
+			}
+			return true
+		})
+		return true
+	}
+	for _, f := range c.filterGenerated(j.Program.Files) {
+		ast.Inspect(f, fn)
+	}
+}
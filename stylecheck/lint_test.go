@@ -10,3 +10,27 @@ func TestAll(t *testing.T) {
 	c := NewChecker()
 	testutil.TestAll(t, c, "")
 }
+
+func TestFloatZero(t *testing.T) {
+	c := NewChecker()
+	c.FloatZero = true
+	testutil.TestAll(t, c, "CheckFloatEqualityZero")
+}
+
+func TestPanicInInit(t *testing.T) {
+	c := NewChecker()
+	c.PanicInInit = true
+	testutil.TestAll(t, c, "CheckLibraryPanicInInit")
+}
+
+func TestUnwrappedErrorStatements(t *testing.T) {
+	c := NewChecker()
+	c.UnwrappedErrorStatements = 2
+	testutil.TestAll(t, c, "CheckUnwrappedErrorReturnStatements")
+}
+
+func TestNakedReturnLines(t *testing.T) {
+	c := NewChecker()
+	c.NakedReturnLines = 5
+	testutil.TestAll(t, c, "CheckNakedReturnsLines")
+}
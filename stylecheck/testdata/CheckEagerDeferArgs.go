@@ -0,0 +1,28 @@
+// Package pkg ...
+package pkg
+
+import (
+	"log"
+	"os"
+	"sync"
+	"time"
+)
+
+func expensive() int { return 0 }
+
+func fn1() {
+	start := time.Now()
+	defer log.Printf("took %s", time.Since(start)) // MATCH "time.Since(start) is evaluated when the defer statement executes, not when the deferred call runs"
+	defer log.Printf("result: %v", expensive())    // MATCH "expensive() is evaluated when the defer statement executes"
+	defer log.Println(int64(expensive()))          // MATCH "expensive() is evaluated when the defer statement executes"
+}
+
+func fn2(mu *sync.Mutex, ch chan int, s []int, name string) {
+	defer log.Printf("result: %v", 42)
+	defer log.Println("done", name, len(s), int64(len(s)))
+	defer mu.Unlock()
+	defer close(ch)
+	defer os.Remove(name)
+	defer func() { log.Printf("result: %v", expensive()) }()
+	defer log.Println(func() int { return expensive() })
+}
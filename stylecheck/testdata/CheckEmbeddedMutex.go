@@ -0,0 +1,28 @@
+// Package pkg ...
+package pkg
+
+import "sync"
+
+type Counter struct {
+	sync.Mutex // MATCH "exported type Counter embeds sync.Mutex, which makes its Lock and Unlock methods part of the API of Counter"
+	n          int
+}
+
+type Cache struct {
+	*sync.RWMutex // MATCH "exported type Cache embeds *sync.RWMutex"
+	m             map[string]string
+}
+
+type Named struct {
+	mu sync.Mutex
+	n  int
+}
+
+type unexported struct {
+	sync.Mutex
+	n int
+}
+
+type Other struct {
+	sync.WaitGroup
+}
@@ -1,7 +1,12 @@
 // Package pkg ...
 package pkg
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+)
+
+const errMsg = "Constant error message."
 
 func fn() {
 	errors.New("a perfectly fine error")
@@ -10,4 +15,17 @@ func fn() {
 	errors.New("URL is okay")
 	errors.New("SomeFunc is okay")
 	errors.New("URL is okay, but the period is not.") // MATCH "error strings should not end with punctuation or a newline"
+	errors.New("IO failed")
+	errors.New("Ünicode isn't special")         // MATCH "error strings should not be capitalized"
+	errors.New("trailing newline\n")            // MATCH "error strings should not end with punctuation or a newline"
+	errors.New(`raw string ending in a colon:`) // MATCH "error strings should not end with punctuation or a newline"
+	errors.New("several exclamation marks!!!")  // MATCH "error strings should not end with punctuation or a newline"
+	errors.New("Both problems at once.")        // MATCH "error strings should not be capitalized"
+	// MATCH:23 "error strings should not end with punctuation or a newline"
+	errors.New(errMsg) // MATCH "error strings should not be capitalized"
+	// MATCH:25 "error strings should not end with punctuation or a newline"
+	errors.New("Single")
+	fmt.Errorf("Bad value %d", 1) // MATCH "error strings should not be capitalized"
+	fmt.Errorf("value %d is too big", 1)
+	fmt.Errorf("value %d is too big.", 1) // MATCH "error strings should not end with punctuation or a newline"
 }
@@ -0,0 +1,14 @@
+// Package pkg ...
+package pkg
+
+type Float float32
+
+func fn(a, b float64, c, d Float, x, y int) {
+	_ = a == b   // MATCH "floating-point values should not be compared with =="
+	_ = c != d   // MATCH "floating-point values should not be compared with !="
+	_ = a == 1.5 // MATCH "floating-point values should not be compared with =="
+	_ = a == 0
+	_ = 0.0 != c
+	_ = x == y
+	_ = a < b
+}
@@ -0,0 +1,9 @@
+// Package pkg ...
+package pkg
+
+func fn(a, b float64, x int) {
+	_ = a == b   // MATCH "floating-point values should not be compared with =="
+	_ = a == 0   // MATCH "floating-point values should not be compared with =="
+	_ = 0.0 != a // MATCH "floating-point values should not be compared with !="
+	_ = x == 0
+}
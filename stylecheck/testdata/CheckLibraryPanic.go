@@ -0,0 +1,41 @@
+// Package pkg ...
+package pkg
+
+import (
+	"errors"
+	"fmt"
+)
+
+type T struct{}
+
+func fn1(x int) {
+	if x < 0 {
+		panic("negative") // MATCH "library code should return errors instead of panicking"
+	}
+	panic(fmt.Sprintf("%d", x))        // MATCH "library code should return errors instead of panicking"
+	panic(fmt.Errorf("%d", x))         // MATCH "library code should return errors instead of panicking"
+	panic(errors.New("foo"))           // MATCH "library code should return errors instead of panicking"
+	func() { panic("in a closure") }() // MATCH "library code should return errors instead of panicking"
+}
+
+func (T) fn2() {
+	panic("method") // MATCH "library code should return errors instead of panicking"
+}
+
+func fn3() {
+	defer func() {
+		if r := recover(); r != nil {
+			panic(r)
+		}
+	}()
+	var err error
+	panic(err)
+}
+
+func MustFn() {
+	panic("fine")
+}
+
+func init() {
+	panic("invariant violated")
+}
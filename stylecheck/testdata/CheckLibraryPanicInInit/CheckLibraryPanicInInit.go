@@ -0,0 +1,6 @@
+// Package pkg ...
+package pkg
+
+func init() {
+	panic("invariant violated") // MATCH "library code should return errors instead of panicking"
+}
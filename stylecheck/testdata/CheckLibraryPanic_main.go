@@ -0,0 +1,6 @@
+// Package main ...
+package main
+
+func main() {
+	panic("fine")
+}
@@ -0,0 +1,36 @@
+// Package pkg ...
+package pkg
+
+import (
+	"fmt"
+	"io"
+)
+
+// Reader implements io.Reader.
+type Reader struct{} // MATCH "Reader is documented to implement io.Reader; consider guarding that with var _ io.Reader = (*Reader)(nil)"
+
+func (*Reader) Read([]byte) (int, error) { return 0, nil }
+
+// Guarded implements io.Reader.
+type Guarded struct{}
+
+func (Guarded) Read([]byte) (int, error) { return 0, nil }
+
+var _ io.Reader = Guarded{}
+
+// Err satisfies the error interface and implements fmt.Stringer,
+// the latter of which is guarded.
+type Err int // MATCH "Err is documented to implement error; consider guarding that with var _ error = (*Err)(nil)"
+
+func (Err) Error() string  { return "" }
+func (Err) String() string { return "" }
+
+var _ fmt.Stringer = Err(0)
+
+// Wrong claims that it implements io.Writer, but doesn't.
+type Wrong struct{}
+
+// Undocumented has a Read method.
+type Undocumented struct{}
+
+func (Undocumented) Read([]byte) (int, error) { return 0, nil }
@@ -0,0 +1,118 @@
+// Package pkg ...
+package pkg
+
+func long(s []int) (n int, err error) {
+	if len(s) == 0 {
+		return // MATCH "naked return in a function that is 36 lines long; consider listing the returned values explicitly"
+	}
+	n++
+	n += 2
+	n += 3
+	n += 4
+	n += 5
+	n += 6
+	n += 7
+	n += 8
+	n += 9
+	n += 10
+	n += 11
+	n += 12
+	n += 13
+	n += 14
+	n += 15
+	n += 16
+	n += 17
+	n += 18
+	n += 19
+	n += 20
+	n += 21
+	n += 22
+	n += 23
+	n += 24
+	n += 25
+	n += 26
+	n += 27
+	n += 28
+	n += 29
+	n += 30
+	return n, nil
+}
+
+func longClosure() (n int) {
+	f := func() (m int) {
+		m = 1
+		return
+	}
+	n = f()
+	n++
+	n += 2
+	n += 3
+	n += 4
+	n += 5
+	n += 6
+	n += 7
+	n += 8
+	n += 9
+	n += 10
+	n += 11
+	n += 12
+	n += 13
+	n += 14
+	n += 15
+	n += 16
+	n += 17
+	n += 18
+	n += 19
+	n += 20
+	n += 21
+	n += 22
+	n += 23
+	n += 24
+	n += 25
+	n += 26
+	n += 27
+	n += 28
+	n += 29
+	n += 30
+	return // MATCH "naked return in a function that is 38 lines long"
+}
+
+func short() (n int) {
+	n = 1
+	return
+}
+
+func unnamed(s []int) int {
+	n := 0
+	n++
+	n += 2
+	n += 3
+	n += 4
+	n += 5
+	n += 6
+	n += 7
+	n += 8
+	n += 9
+	n += 10
+	n += 11
+	n += 12
+	n += 13
+	n += 14
+	n += 15
+	n += 16
+	n += 17
+	n += 18
+	n += 19
+	n += 20
+	n += 21
+	n += 22
+	n += 23
+	n += 24
+	n += 25
+	n += 26
+	n += 27
+	n += 28
+	n += 29
+	n += 30
+	return n
+}
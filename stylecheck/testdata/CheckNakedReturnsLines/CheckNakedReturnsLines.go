@@ -0,0 +1,15 @@
+// Package pkg ...
+package pkg
+
+func fn1() (n int) {
+	n = 1
+	n++
+	return
+}
+
+func fn2() (n int) {
+	n = 1
+	n++
+	n++
+	return // MATCH "naked return in a function that is 6 lines long"
+}
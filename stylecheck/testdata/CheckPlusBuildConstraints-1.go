@@ -0,0 +1,4 @@
+// +build linux
+
+// Package pkg ...
+package pkg // MATCH "the // +build syntax for build constraints is deprecated, add //go:build linux"
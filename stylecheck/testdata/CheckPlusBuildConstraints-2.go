@@ -0,0 +1,7 @@
+// Copyright notice
+
+// +build linux,amd64 darwin,!cgo
+// +build !race
+
+// Package pkg ...
+package pkg // MATCH "add //go:build ((linux && amd64) || (darwin && !cgo)) && !race"
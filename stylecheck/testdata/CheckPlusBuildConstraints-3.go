@@ -0,0 +1,5 @@
+//go:build !windows
+// +build !windows
+
+// Package pkg ...
+package pkg
@@ -0,0 +1,57 @@
+// Package pkg ...
+package pkg
+
+import "io"
+
+type T struct {
+	r  *io.Reader // MATCH "*io.Reader is a pointer to an interface; interface values can already hold pointers, use io.Reader instead"
+	t  *T
+	rs []*io.Reader
+}
+
+type E interface {
+	Err() *error // MATCH "*error is a pointer to an interface"
+}
+
+func fn1(err *error) {} // MATCH "*error is a pointer to an interface; interface values can already hold pointers, use error instead"
+
+func fn2(t *T) *T { return t }
+
+func fn3() (*io.Writer, error) { return nil, nil } // MATCH "*io.Writer is a pointer to an interface"
+
+func fn4() {
+	_ = func(r, w *io.ReadWriter) {} // MATCH "*io.ReadWriter is a pointer to an interface"
+}
+
+func recoverErr(err *error) {
+	if r := recover(); r != nil {
+		*err = io.EOF
+	}
+}
+
+func fn5() (err error) {
+	defer func(err *error) {
+		if recover() != nil {
+			*err = io.EOF
+		}
+	}(&err)
+	return nil
+}
+
+func recoverReader(r *io.Reader) { // MATCH "*io.Reader is a pointer to an interface"
+	recover()
+}
+
+func recoverNested(err *error) { // MATCH "*error is a pointer to an interface"
+	_ = func() { recover() }
+}
+
+func recoverResult() *error { // MATCH "*error is a pointer to an interface"
+	recover()
+	return nil
+}
+
+//lint:ignore ST1016 the function sets the caller's error
+func setErr(err *error) {
+	*err = io.EOF
+}
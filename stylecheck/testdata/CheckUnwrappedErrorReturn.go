@@ -0,0 +1,59 @@
+// Package pkg ...
+package pkg
+
+import (
+	"errors"
+	"fmt"
+)
+
+func step() error         { return nil }
+func value() (int, error) { return 0, nil }
+
+func short() error {
+	if err := step(); err != nil {
+		return err
+	}
+	return nil
+}
+
+func long() (int, error) {
+	a := 1
+	b := 2
+	c := a + b
+	_ = c
+	if err := step(); err != nil {
+		return 0, err
+	}
+	a++
+	b++
+	c++
+	a++
+	x, err := value()
+	if err != nil {
+		return 0, err // MATCH "err is returned without adding context, in a function with more than 10 statements; consider wrapping it, e.g. with fmt.Errorf("...: %w", err)"
+	}
+	if err := step(); err != nil {
+		return 0, fmt.Errorf("second step: %w", err)
+	}
+	errCreated := errors.New("created here")
+	if x > 0 {
+		return 0, errCreated
+	}
+	return x, nil
+}
+
+func long2(err error) error {
+	a := 0
+	a++
+	a++
+	a++
+	a++
+	a++
+	a++
+	a++
+	a++
+	a++
+	a++
+	// parameters aren't errors returned by calls
+	return err
+}
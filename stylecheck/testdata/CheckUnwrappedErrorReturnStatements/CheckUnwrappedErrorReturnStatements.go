@@ -0,0 +1,20 @@
+// Package pkg ...
+package pkg
+
+func step() error { return nil }
+
+func fn1() error {
+	if err := step(); err != nil {
+		return err
+	}
+	return nil
+}
+
+func fn2() error {
+	step()
+	step()
+	if err := step(); err != nil {
+		return err // MATCH "err is returned without adding context, in a function with more than 2 statements"
+	}
+	return nil
+}
@@ -0,0 +1,276 @@
+// Package typeinfo exports the results of type-checking a package in
+// a form that can be serialized as JSON, for tools that want to use
+// them without running the type checker themselves.
+//
+// The JSON schema is defined by the types of this package and their
+// struct tags. Fields are only ever added to it, not changed or
+// removed. Types are written as by types.TypeString, with packages
+// qualified by their import paths, e.g. *net/http.Request.
+package typeinfo // import "honnef.co/go/tools/typeinfo"
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+	"sort"
+)
+
+// Package is the type information of a package.
+type Package struct {
+	Path string `json:"path"`
+	Name string `json:"name"`
+	// Idents lists all identifiers that declare or refer to an
+	// object, sorted by position.
+	Idents []Ident `json:"idents"`
+	// Selectors lists all selector expressions that select a field
+	// or method, sorted by position.
+	Selectors []Selector `json:"selectors"`
+	// API lists the exported package-level objects, sorted by name.
+	API []Object `json:"api"`
+}
+
+// Position is a position in a source file. Lines and columns start
+// at 1, and columns are counted in bytes.
+type Position struct {
+	File   string `json:"file"`
+	Line   int    `json:"line"`
+	Column int    `json:"column"`
+}
+
+// An Ident is an identifier and the object it denotes.
+type Ident struct {
+	Pos  Position `json:"pos"`
+	Name string   `json:"name"`
+	// Kind is the kind of object; see Object.
+	Kind string `json:"kind"`
+	Type string `json:"type,omitempty"`
+	// Def is true if the identifier declares the object, as opposed
+	// to referring to it.
+	Def bool `json:"def"`
+	// Decl is the position of the object's declaration. It is
+	// missing for objects declared outside of the package's files,
+	// such as imported and predeclared ones.
+	Decl *Position `json:"decl,omitempty"`
+	// Object is the qualified name of package-level objects,
+	// fields and methods, such as net/http.Get. It is empty for
+	// local objects.
+	Object string `json:"object,omitempty"`
+}
+
+// A Selector is a selector expression x.f that selects a field or
+// method. Qualified identifiers, such as fmt.Println, aren't
+// selectors; they are described by Idents.
+type Selector struct {
+	// Pos is the position of the selected name.
+	Pos Position `json:"pos"`
+	// Kind is one of "field", "method" for method values and
+	// calls, and "method-expr" for method expressions such as
+	// T.m.
+	Kind   string `json:"kind"`
+	Object string `json:"object"`
+	// Type is the type of the selector expression.
+	Type string `json:"type"`
+	// Indirect is true if selecting the field or method requires
+	// dereferencing pointers.
+	Indirect bool `json:"indirect"`
+}
+
+// An Object is a named entity of the exported API of a package.
+type Object struct {
+	Name string `json:"name"`
+	// Kind is one of "const", "var", "type", "func", "pkgname",
+	// "label", "builtin" and "nil".
+	Kind string `json:"kind"`
+	Type string `json:"type"`
+	// Methods lists the exported methods of named types, including
+	// those with pointer receivers and those of interfaces, sorted
+	// by name.
+	Methods []Object `json:"methods,omitempty"`
+	// Fields lists the exported fields of struct types, in the
+	// order of their declaration. Fields of embedded structs aren't
+	// included, but the embedded fields themselves are.
+	Fields []Object `json:"fields,omitempty"`
+	// Embedded is true for embedded fields.
+	Embedded bool `json:"embedded,omitempty"`
+}
+
+// Export returns the type information of pkg, whose files have
+// already been type-checked, recording their results in info. info
+// has to have its Defs, Uses and Selections maps set.
+func Export(fset *token.FileSet, pkg *types.Package, info *types.Info, files []*ast.File) *Package {
+	out := &Package{
+		Path:      pkg.Path(),
+		Name:      pkg.Name(),
+		Idents:    []Ident{},
+		Selectors: []Selector{},
+		API:       []Object{},
+	}
+	position := func(pos token.Pos) Position {
+		p := fset.Position(pos)
+		return Position{p.Filename, p.Line, p.Column}
+	}
+	inFiles := func(obj types.Object) bool {
+		f := fset.File(obj.Pos())
+		if f == nil {
+			return false
+		}
+		for _, file := range files {
+			if fset.File(file.Pos()) == f {
+				return true
+			}
+		}
+		return false
+	}
+
+	var idents []*ast.Ident
+	for id := range info.Defs {
+		if info.Defs[id] != nil {
+			idents = append(idents, id)
+		}
+	}
+	for id := range info.Uses {
+		idents = append(idents, id)
+	}
+	sort.Slice(idents, func(i, j int) bool { return idents[i].Pos() < idents[j].Pos() })
+	for _, id := range idents {
+		obj, def := info.Defs[id]
+		if !def {
+			obj = info.Uses[id]
+		}
+		ident := Ident{
+			Pos:    position(id.Pos()),
+			Name:   id.Name,
+			Kind:   kind(obj),
+			Def:    def,
+			Object: qualifiedName(obj),
+		}
+		if _, ok := obj.(*types.PkgName); !ok && obj.Type() != nil {
+			ident.Type = types.TypeString(obj.Type(), nil)
+		}
+		if inFiles(obj) {
+			decl := position(obj.Pos())
+			ident.Decl = &decl
+		}
+		out.Idents = append(out.Idents, ident)
+	}
+
+	var sels []*ast.SelectorExpr
+	for sel := range info.Selections {
+		sels = append(sels, sel)
+	}
+	sort.Slice(sels, func(i, j int) bool { return sels[i].Sel.Pos() < sels[j].Sel.Pos() })
+	for _, sel := range sels {
+		s := info.Selections[sel]
+		var k string
+		switch s.Kind() {
+		case types.FieldVal:
+			k = "field"
+		case types.MethodVal:
+			k = "method"
+		case types.MethodExpr:
+			k = "method-expr"
+		}
+		out.Selectors = append(out.Selectors, Selector{
+			Pos:      position(sel.Sel.Pos()),
+			Kind:     k,
+			Object:   qualifiedName(s.Obj()),
+			Type:     types.TypeString(s.Type(), nil),
+			Indirect: s.Indirect(),
+		})
+	}
+
+	scope := pkg.Scope()
+	for _, name := range scope.Names() {
+		obj := scope.Lookup(name)
+		if !obj.Exported() {
+			continue
+		}
+		o := Object{
+			Name: name,
+			Kind: kind(obj),
+			Type: types.TypeString(obj.Type(), nil),
+		}
+		// The types of named types are just their names, so their
+		// methods and fields are listed separately.
+		if tname, ok := obj.(*types.TypeName); ok {
+			T := tname.Type()
+			if !types.IsInterface(T) {
+				T = types.NewPointer(T)
+			}
+			ms := types.NewMethodSet(T)
+			for i := 0; i < ms.Len(); i++ {
+				m := ms.At(i).Obj()
+				if m.Exported() {
+					o.Methods = append(o.Methods, Object{
+						Name: m.Name(),
+						Kind: kind(m),
+						Type: types.TypeString(m.Type(), nil),
+					})
+				}
+			}
+			if st, ok := tname.Type().Underlying().(*types.Struct); ok {
+				for i := 0; i < st.NumFields(); i++ {
+					f := st.Field(i)
+					if f.Exported() {
+						o.Fields = append(o.Fields, Object{
+							Name:     f.Name(),
+							Kind:     kind(f),
+							Type:     types.TypeString(f.Type(), nil),
+							Embedded: f.Anonymous(),
+						})
+					}
+				}
+			}
+		}
+		out.API = append(out.API, o)
+	}
+	return out
+}
+
+func kind(obj types.Object) string {
+	switch obj.(type) {
+	case *types.Const:
+		return "const"
+	case *types.Var:
+		return "var"
+	case *types.TypeName:
+		return "type"
+	case *types.Func:
+		return "func"
+	case *types.PkgName:
+		return "pkgname"
+	case *types.Label:
+		return "label"
+	case *types.Builtin:
+		return "builtin"
+	case *types.Nil:
+		return "nil"
+	default:
+		return ""
+	}
+}
+
+// qualifiedName returns the qualified name of package-level objects,
+// fields and methods, and the empty string for all other objects.
+func qualifiedName(obj types.Object) string {
+	switch obj := obj.(type) {
+	case *types.Func:
+		// methods are written as (*T).m or T.m
+		return obj.FullName()
+	case *types.Var:
+		if obj.IsField() {
+			// fields don't know their struct types, so we can't
+			// qualify them any further
+			if obj.Pkg() == nil {
+				return obj.Name()
+			}
+			return obj.Pkg().Path() + "." + obj.Name()
+		}
+	case *types.PkgName:
+		return obj.Imported().Path()
+	}
+	if obj.Pkg() == nil || obj.Parent() != obj.Pkg().Scope() {
+		return ""
+	}
+	return obj.Pkg().Path() + "." + obj.Name()
+}
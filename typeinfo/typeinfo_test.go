@@ -0,0 +1,137 @@
+package typeinfo
+
+import (
+	"encoding/json"
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"reflect"
+	"testing"
+)
+
+const src = `package pkg
+
+import "strings"
+
+type T struct {
+	F int
+	g int
+	*E
+}
+
+type E struct{}
+
+func (t *T) M() string { return strings.Repeat("x", t.F) }
+
+type I interface {
+	M() string
+}
+
+var V = (*T).M
+
+func fn() {
+	var t T
+	_ = t.M()
+}
+`
+
+func export(t *testing.T) *Package {
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "pkg.go", src, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	info := &types.Info{
+		Defs:       map[*ast.Ident]types.Object{},
+		Uses:       map[*ast.Ident]types.Object{},
+		Selections: map[*ast.SelectorExpr]*types.Selection{},
+	}
+	conf := types.Config{Importer: importer.Default()}
+	pkg, err := conf.Check("example.com/pkg", fset, []*ast.File{f}, info)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return Export(fset, pkg, info, []*ast.File{f})
+}
+
+func TestExport(t *testing.T) {
+	pkg := export(t)
+
+	if pkg.Path != "example.com/pkg" || pkg.Name != "pkg" {
+		t.Errorf("got package %s (%s), want example.com/pkg (pkg)", pkg.Path, pkg.Name)
+	}
+
+	var repeat, tf *Ident
+	for i := range pkg.Idents {
+		id := &pkg.Idents[i]
+		switch {
+		case id.Name == "Repeat":
+			repeat = id
+		case id.Name == "t" && id.Pos.Line == 22 && id.Def:
+			tf = id
+		}
+	}
+	if repeat == nil {
+		t.Fatal("no ident for strings.Repeat")
+	}
+	if repeat.Object != "strings.Repeat" || repeat.Kind != "func" || repeat.Type != "func(s string, count int) string" || repeat.Decl != nil {
+		t.Errorf("unexpected ident for strings.Repeat: %+v", *repeat)
+	}
+	if tf == nil {
+		t.Fatal("no ident for local variable t")
+	}
+	want := Ident{
+		Pos:  Position{"pkg.go", 22, 6},
+		Name: "t",
+		Kind: "var",
+		Type: "example.com/pkg.T",
+		Def:  true,
+		Decl: &Position{"pkg.go", 22, 6},
+	}
+	if !reflect.DeepEqual(*tf, want) {
+		t.Errorf("got %+v, want %+v", *tf, want)
+	}
+
+	wantSels := []Selector{
+		{Position{"pkg.go", 13, 55}, "field", "example.com/pkg.F", "int", true},
+		{Position{"pkg.go", 19, 14}, "method-expr", "(*example.com/pkg.T).M", "func(t *example.com/pkg.T) string", true},
+		{Position{"pkg.go", 23, 8}, "method", "(*example.com/pkg.T).M", "func() string", false},
+	}
+	if !reflect.DeepEqual(pkg.Selectors, wantSels) {
+		t.Errorf("got selectors %+v, want %+v", pkg.Selectors, wantSels)
+	}
+
+	wantAPI := []Object{
+		{Name: "E", Kind: "type", Type: "example.com/pkg.E"},
+		{Name: "I", Kind: "type", Type: "example.com/pkg.I", Methods: []Object{
+			{Name: "M", Kind: "func", Type: "func() string"},
+		}},
+		{Name: "T", Kind: "type", Type: "example.com/pkg.T", Methods: []Object{
+			{Name: "M", Kind: "func", Type: "func() string"},
+		}, Fields: []Object{
+			{Name: "F", Kind: "var", Type: "int"},
+			{Name: "E", Kind: "var", Type: "*example.com/pkg.E", Embedded: true},
+		}},
+		{Name: "V", Kind: "var", Type: "func(*example.com/pkg.T) string"},
+	}
+	if !reflect.DeepEqual(pkg.API, wantAPI) {
+		t.Errorf("got API %+v, want %+v", pkg.API, wantAPI)
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	pkg := export(t)
+	b, err := json.Marshal(pkg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got Package
+	if err := json.Unmarshal(b, &got); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(&got, pkg) {
+		t.Errorf("round trip changed the type information:\ngot  %+v\nwant %+v", got, *pkg)
+	}
+}
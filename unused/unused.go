@@ -153,7 +153,10 @@ type Checker struct {
 	Mode               CheckMode
 	WholeProgram       bool
 	ConsiderReflection bool
-	Debug              io.Writer
+	// ExcludeTests causes tests, benchmarks and examples not to be
+	// treated as roots, so that code only used by them is unused.
+	ExcludeTests bool
+	Debug        io.Writer
 
 	graph *graph
 
@@ -199,8 +202,9 @@ func (e Error) Error() string {
 	return fmt.Sprintf("errors in %d packages", len(e.Errors))
 }
 
-func (c *Checker) Check(lprog *loader.Program) []Unused {
-	var unused []Unused
+// analyze builds the graph of lprog and marks the nodes that are
+// reachable from the roots as used.
+func (c *Checker) analyze(lprog *loader.Program) {
 	c.lprog = lprog
 	if c.WholeProgram {
 		c.findExportedInterfaces()
@@ -235,7 +239,26 @@ func (c *Checker) Check(lprog *loader.Program) []Unused {
 	if c.Debug != nil {
 		c.printDebugGraph(c.Debug)
 	}
+}
 
+// Reachable returns the objects of lprog that are reachable from the
+// roots, that is the objects that Check doesn't consider unused.
+// Outside of whole-program mode, this is the code reachable from the
+// exported API of the packages.
+func (c *Checker) Reachable(lprog *loader.Program) map[types.Object]bool {
+	c.analyze(lprog)
+	reachable := map[types.Object]bool{}
+	for _, node := range c.graph.nodes {
+		if obj, ok := node.obj.(types.Object); ok && node.used {
+			reachable[obj] = true
+		}
+	}
+	return reachable
+}
+
+func (c *Checker) Check(lprog *loader.Program) []Unused {
+	var unused []Unused
+	c.analyze(lprog)
 	for _, node := range c.graph.nodes {
 		if node.used || node.quiet {
 			continue
@@ -916,6 +939,9 @@ func (c *Checker) isRoot(obj types.Object) bool {
 	if obj.Exported() {
 		f := c.lprog.Fset.Position(obj.Pos()).Filename
 		if strings.HasSuffix(f, "_test.go") {
+			if c.ExcludeTests {
+				return false
+			}
 			return strings.HasPrefix(obj.Name(), "Test") ||
 				strings.HasPrefix(obj.Name(), "Benchmark") ||
 				strings.HasPrefix(obj.Name(), "Example")